@@ -0,0 +1,136 @@
+// Package audit keeps an append-only log of every change pair makes to git
+// configuration, for debugging and for organizations which track changes to
+// workstations.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+// Entry is a single change. Serializes to one line of JSON.
+type Entry struct {
+	Time   time.Time `json:"time"`           // When the change was made
+	User   string    `json:"user"`           // Who made it, by OS login
+	Host   string    `json:"host"`           // Which machine it was made on
+	Action string    `json:"action"`         // What was done. e.g. git config
+	File   string    `json:"file,omitempty"` // The file changed, if known
+	Key    string    `json:"key"`            // The setting changed. e.g. user.name
+	Old    string    `json:"old"`            // The value before
+	New    string    `json:"new"`            // The value after
+}
+
+// Path returns the location of the audit log.
+func Path() string {
+	return filepath.Join(cfg.DataDir(), "audit.jsonl")
+}
+
+// New describes a change to key, filling in when and by whom.
+func New(action, file, key, old, new string) *Entry {
+	e := &Entry{Time: time.Now(), Action: action, File: file, Key: key, Old: old, New: new}
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	e.Host, _ = os.Hostname()
+	return e
+}
+
+// Append adds e to the end of the audit log at path. The log is only ever
+// appended to, and is readable only by its owner.
+func Append(path string, e *Entry) error {
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(buf, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Record appends a change to the default audit log, unless nothing changed,
+// and sends it to any configured sinks. A *SinkError means only the sinks
+// failed.
+func Record(action, file, key, old, new string) error {
+	if old == new {
+		return nil
+	}
+	e := New(action, file, key, old, new)
+	if err := Append(Path(), e); err != nil {
+		return err
+	}
+	sinks, err := Sinks()
+	if err != nil {
+		return &SinkError{err}
+	}
+	for _, s := range sinks {
+		if err := s.Write(e); err != nil {
+			return &SinkError{err}
+		}
+	}
+	return nil
+}
+
+// Last returns the value pair last set key to in file, according to entries,
+// and whether pair has set it at all. A different value in the file now
+// means something else has changed it since.
+func Last(entries []*Entry, file, key string) (string, bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Action == "git config" && e.File == file && e.Key == key {
+			return e.New, true
+		}
+	}
+	return "", false
+}
+
+// SinkError is returned by Record when the change was logged to the audit
+// log but couldn't be sent to a sink. Callers will usually only warn.
+type SinkError struct {
+	Err error
+}
+
+func (e *SinkError) Error() string {
+	return "log sink: " + e.Err.Error()
+}
+
+// Read returns every entry in the audit log at path, oldest first. A missing
+// log has no entries.
+func Read(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "audit")
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, "pair", "audit.jsonl")
+
+	if entries, err := Read(path); err != nil || len(entries) != 0 {
+		t.Fatalf("expected a missing log to be empty, got %v, %v", entries, err)
+	}
+	Append(path, New("git config", "~/.gitconfig_local", "user.name", "Michael Bluth", "Lindsay Bluth and Michael Bluth"))
+	Append(path, New("git config", "~/.gitconfig_local", "user.email", "mb@example.com", "git+lb+mb@example.com"))
+
+	entries, err := Read(path)
+	if err != nil {
+		t.Fatalf("expected no error reading the log, got %v", err)
+	}
+	if len(entries) != 2 || entries[1].Key != "user.email" || entries[1].Old != "mb@example.com" {
+		t.Fatalf("expected both changes in order, got %v", entries)
+	}
+	if entries[0].Time.IsZero() || entries[0].Host == "" {
+		t.Fatalf("expected when and where to be recorded, got %+v", entries[0])
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the log to be private, got %v", info.Mode())
+	}
+}
+
+func TestLast(t *testing.T) {
+	entries := []*Entry{
+		New("git config", "~/.gitconfig_local", "user.name", "", "Michael Bluth"),
+		New("git config", "~/.gitconfig", "user.name", "", "Lindsay Bluth"),
+		New("git config", "~/.gitconfig_local", "user.name", "Michael Bluth", "Lindsay Bluth and Michael Bluth"),
+		New("git config", "~/.gitconfig_local", "user.email", "", "git+lb+mb@example.com"),
+	}
+	if last, ok := Last(entries, "~/.gitconfig_local", "user.name"); !ok || last != "Lindsay Bluth and Michael Bluth" {
+		t.Fatalf("expected the latest value set in the file, got %q, %v", last, ok)
+	}
+	if _, ok := Last(entries, "~/.gitconfig_local", "commit.template"); ok {
+		t.Fatalf("expected nothing for a key pair never set")
+	}
+}
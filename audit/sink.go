@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+// Sink receives audit entries as well as the audit log, such as the system
+// log for centralized workstation logging.
+type Sink interface {
+	Write(e *Entry) error
+}
+
+// ParseSinks parses sink specs: "syslog", "journald", or "file:PATH" to
+// append JSON lines to PATH.
+func ParseSinks(specs []string) ([]Sink, error) {
+	var sinks []Sink
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		switch {
+		case spec == "":
+		case spec == "syslog":
+			sinks = append(sinks, syslogSink{})
+		case spec == "journald":
+			sinks = append(sinks, journaldSink{socket: journaldSocket})
+		case strings.HasPrefix(spec, "file:"):
+			sinks = append(sinks, fileSink{path: strings.TrimPrefix(spec, "file:")})
+		default:
+			return nil, fmt.Errorf("unknown log sink %s; expected syslog, journald or file:PATH", spec)
+		}
+	}
+	return sinks, nil
+}
+
+// Sinks returns the sinks configured by $PAIR_LOG, a comma-separated list of
+// sink specs, or otherwise by log in the config.
+func Sinks() ([]Sink, error) {
+	if env := os.Getenv("PAIR_LOG"); env != "" {
+		return ParseSinks(strings.Split(env, ","))
+	}
+	config, err := cfg.Read()
+	if err != nil {
+		return nil, nil
+	}
+	return ParseSinks(config.Log)
+}
+
+// Message describes e in a single line for text logs.
+func (e *Entry) Message() string {
+	msg := fmt.Sprintf("%s %s: %q -> %q user=%s", e.Action, e.Key, e.Old, e.New, e.User)
+	if e.File != "" {
+		msg += " file=" + e.File
+	}
+	return msg
+}
+
+// fileSink appends entries to a file of its own.
+type fileSink struct {
+	path string
+}
+
+func (s fileSink) Write(e *Entry) error {
+	return Append(s.path, e)
+}
+
+const journaldSocket = "/run/systemd/journal/socket"
+
+// journaldSink sends entries to the systemd journal with its native protocol,
+// keeping each field of the entry as a journal field.
+type journaldSink struct {
+	socket string
+}
+
+func (s journaldSink) Write(e *Entry) error {
+	conn, err := net.Dial("unixgram", s.socket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(journalFields(e)))
+	return err
+}
+
+// journalFields formats e in the journal's native protocol.
+func journalFields(e *Entry) string {
+	clean := strings.NewReplacer("\n", " ").Replace
+	fields := []string{
+		"MESSAGE=" + clean(e.Message()),
+		"PRIORITY=5",
+		"SYSLOG_IDENTIFIER=pair",
+		"PAIR_ACTION=" + clean(e.Action),
+		"PAIR_FILE=" + clean(e.File),
+		"PAIR_KEY=" + clean(e.Key),
+		"PAIR_OLD=" + clean(e.Old),
+		"PAIR_NEW=" + clean(e.New),
+		"PAIR_USER=" + clean(e.User),
+	}
+	return strings.Join(fields, "\n") + "\n"
+}
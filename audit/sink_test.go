@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSinks(t *testing.T) {
+	sinks, err := ParseSinks([]string{"syslog", " journald", "file:/tmp/pair.log", ""})
+	if err != nil || len(sinks) != 3 {
+		t.Fatalf("expected three sinks, got %v, %v", sinks, err)
+	}
+	if f, ok := sinks[2].(fileSink); !ok || f.path != "/tmp/pair.log" {
+		t.Fatalf("expected a file sink for /tmp/pair.log, got %v", sinks[2])
+	}
+	if _, err := ParseSinks([]string{"carrier-pigeon"}); err == nil {
+		t.Fatalf("expected an error for an unknown sink")
+	}
+}
+
+func TestJournaldSink(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "journal")
+	defer os.RemoveAll(dir) // clean up
+	socket := filepath.Join(dir, "socket")
+	conn, err := net.ListenPacket("unixgram", socket)
+	if err != nil {
+		t.Skipf("unix datagram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	e := New("git config", "", "user.name", "Michael Bluth", "Lindsay Bluth\nand Michael Bluth")
+	if err := (journaldSink{socket: socket}).Write(e); err != nil {
+		t.Fatalf("expected no error writing to the journal, got %v", err)
+	}
+	buf := make([]byte, 4096)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("expected to receive a journal entry, got %v", err)
+	}
+	fields := string(buf[:n])
+	for _, field := range []string{"SYSLOG_IDENTIFIER=pair\n", "PAIR_KEY=user.name\n", "PAIR_NEW=Lindsay Bluth and Michael Bluth\n"} {
+		if !strings.Contains(fields, field) {
+			t.Fatalf("expected %q in the journal entry, got %q", field, fields)
+		}
+	}
+}
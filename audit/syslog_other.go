@@ -0,0 +1,13 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package audit
+
+import "errors"
+
+// syslogSink is unavailable where Go has no syslog client.
+type syslogSink struct{}
+
+func (syslogSink) Write(e *Entry) error {
+	return errors.New("syslog isn't supported on this platform")
+}
@@ -0,0 +1,241 @@
+// Package auth manages the API tokens pair's integrations use, one per
+// provider, kept in the secret store and overridable from the environment.
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/directory"
+	"github.com/keeferrourke/pair/internal/httpx"
+	"github.com/keeferrourke/pair/secret"
+)
+
+// Provider is a service pair can authenticate with.
+type Provider struct {
+	Name  string   // Name of the provider. e.g. github
+	Usage string   // What the token is, for help
+	Env   []string // Variables which override the stored token, in order
+
+	test func(token string) (string, error)
+}
+
+// API roots, replaceable in tests.
+var (
+	githubURL = "https://api.github.com"
+	gitlabURL = "https://gitlab.com/api/v4"
+	slackURL  = "https://slack.com/api"
+)
+
+// Providers are the services pair integrates with.
+var Providers = []*Provider{
+	{
+		Name:  "github",
+		Usage: "A personal access token.",
+		Env:   []string{"PAIR_GITHUB_TOKEN", "GITHUB_TOKEN"},
+		test:  testGitHub,
+	},
+	{
+		Name:  "gitlab",
+		Usage: "A personal access token. Set $PAIR_GITLAB_URL for a self-managed instance.",
+		Env:   []string{"PAIR_GITLAB_TOKEN", "GITLAB_TOKEN"},
+		test:  testGitLab,
+	},
+	{
+		Name:  "jira",
+		Usage: "Your email and an API token, as email:token, for tracker.site or $PAIR_JIRA_URL.",
+		Env:   []string{"PAIR_JIRA_TOKEN"},
+		test:  testJira,
+	},
+	{
+		Name:  "ldap",
+		Usage: "The password for directory.bind_dn, for looking people up in your company directory.",
+		Env:   []string{"PAIR_LDAP_PASSWORD"},
+		test:  testLDAP,
+	},
+	{
+		Name:  "slack",
+		Usage: "An incoming webhook URL, or a bot or user token.",
+		Env:   []string{"PAIR_SLACK_TOKEN"},
+		test:  testSlack,
+	},
+}
+
+// Lookup finds the provider called name.
+func Lookup(name string) (*Provider, error) {
+	var names []string
+	for _, p := range Providers {
+		if p.Name == name {
+			return p, nil
+		}
+		names = append(names, p.Name)
+	}
+	return nil, fmt.Errorf("unknown provider %s, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// Token returns the token for p from the environment, or else the store, and
+// where it came from. It's empty if there isn't one.
+func (p *Provider) Token(store secret.Store) (token, source string, err error) {
+	for _, env := range p.Env {
+		if token := os.Getenv(env); token != "" {
+			return token, "$" + env, nil
+		}
+	}
+	token, err = store.Get(p.Name)
+	if err == secret.ErrNotFound {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return token, "stored", nil
+}
+
+// Token returns the token for the named provider from the environment or the
+// default store, or "" if there isn't one.
+func Token(name string) string {
+	p, err := Lookup(name)
+	if err != nil {
+		return ""
+	}
+	token, _, _ := p.Token(secret.Default())
+	return token
+}
+
+// Test checks token with the provider, returning who it authenticates as.
+func (p *Provider) Test(token string) (string, error) {
+	return p.test(token)
+}
+
+// getJSON fetches url with header set, decoding the response into out.
+func getJSON(url string, header http.Header, out interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := httpx.New(10 * time.Second).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token rejected: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func testGitHub(token string) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	err := getJSON(githubURL+"/user", http.Header{"Authorization": {"token " + token}}, &user)
+	return user.Login, err
+}
+
+func testGitLab(token string) (string, error) {
+	base := gitlabURL
+	if url := os.Getenv("PAIR_GITLAB_URL"); url != "" {
+		base = strings.TrimRight(url, "/") + "/api/v4"
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	err := getJSON(base+"/user", http.Header{"Private-Token": {token}}, &user)
+	return user.Username, err
+}
+
+func testJira(token string) (string, error) {
+	site := os.Getenv("PAIR_JIRA_URL")
+	if config, err := cfg.Read(); site == "" && err == nil && config.Tracker != nil && config.Tracker.Kind == cfg.TrackJira {
+		site = config.Tracker.Site
+	}
+	if site == "" {
+		return "", errors.New("set tracker.site in your config or $PAIR_JIRA_URL to your Jira site, e.g. https://example.atlassian.net")
+	}
+	if !strings.Contains(token, ":") {
+		return "", errors.New("expected the Jira token as email:token")
+	}
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte(token))
+	var user struct {
+		DisplayName string `json:"displayName"`
+	}
+	err := getJSON(strings.TrimRight(site, "/")+"/rest/api/2/myself", http.Header{"Authorization": {basic}}, &user)
+	return user.DisplayName, err
+}
+
+// testLDAP binds to the configured directory by looking yourself up. Not
+// being found still means the bind worked.
+func testLDAP(password string) (string, error) {
+	config, err := cfg.Read()
+	if err != nil {
+		return "", err
+	}
+	if config.Directory == nil {
+		return "", errors.New("set directory in your config first")
+	}
+	if config.Directory.BindDN == "" {
+		return "", errors.New("directory.bind_dn isn't set, so no password is needed")
+	}
+	d, err := directory.New(config.Directory, password)
+	if err != nil {
+		return "", err
+	}
+	alias := "pair"
+	if config.Author != nil {
+		alias = config.Author.Alias
+	}
+	if _, err := d.Lookup(alias); err != nil && err != directory.ErrNotFound {
+		return "", err
+	}
+	return config.Directory.BindDN, nil
+}
+
+func testSlack(token string) (string, error) {
+	if strings.HasPrefix(token, "https://") {
+		return testSlackWebhook(token)
+	}
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		User  string `json:"user"`
+		Team  string `json:"team"`
+	}
+	if err := getJSON(slackURL+"/auth.test", http.Header{"Authorization": {"Bearer " + token}}, &result); err != nil {
+		return "", err
+	}
+	if !result.OK {
+		return "", fmt.Errorf("token rejected: %s", result.Error)
+	}
+	return result.User + " in " + result.Team, nil
+}
+
+// testSlackWebhook checks a webhook without posting anything: Slack answers
+// an empty message with 400 no_text if the webhook exists.
+func testSlackWebhook(webhook string) (string, error) {
+	req, err := http.NewRequest("POST", webhook, strings.NewReader("{}"))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpx.New(10 * time.Second).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadRequest || strings.TrimSpace(string(body)) != "no_text" {
+		return "", fmt.Errorf("webhook rejected: %s", resp.Status)
+	}
+	return "incoming webhook", nil
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keeferrourke/pair/secret"
+)
+
+func TestToken(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "auth")
+	defer os.RemoveAll(dir) // clean up
+	store := secret.File(filepath.Join(dir, "secrets.yml"))
+	p, _ := Lookup("github")
+
+	os.Unsetenv("PAIR_GITHUB_TOKEN")
+	os.Unsetenv("GITHUB_TOKEN")
+	if token, source, err := p.Token(store); token != "" || source != "" || err != nil {
+		t.Fatalf("expected no token yet, got %q from %q, %v", token, source, err)
+	}
+	store.Set("github", "ghp_stored")
+	if token, source, _ := p.Token(store); token != "ghp_stored" || source != "stored" {
+		t.Fatalf("expected the stored token, got %q from %q", token, source)
+	}
+	os.Setenv("GITHUB_TOKEN", "ghp_env")
+	defer os.Unsetenv("GITHUB_TOKEN")
+	if token, source, _ := p.Token(store); token != "ghp_env" || source != "$GITHUB_TOKEN" {
+		t.Fatalf("expected the environment to take precedence, got %q from %q", token, source)
+	}
+
+	if _, err := Lookup("bitbucket"); err == nil {
+		t.Fatalf("expected an error for an unknown provider")
+	}
+}
+
+func TestTestGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" || r.Header.Get("Authorization") != "token ghp_banana" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"login": "lbluth"}`)
+	}))
+	defer server.Close()
+	defer func(url string) { githubURL = url }(githubURL)
+	githubURL = server.URL
+
+	p, _ := Lookup("github")
+	if who, err := p.Test("ghp_banana"); err != nil || who != "lbluth" {
+		t.Fatalf("expected the token to authenticate as lbluth, got %q, %v", who, err)
+	}
+	if _, err := p.Test("ghp_wrong"); err == nil || err.Error() != "token rejected: 401 Unauthorized" {
+		t.Fatalf("expected a rejected token, got %v", err)
+	}
+}
+
+func TestTestSlackWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/T0/B0/x" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "no_text")
+	}))
+	defer server.Close()
+
+	// Webhooks are told apart from tokens by their https:// scheme.
+	if _, err := testSlackWebhook(server.URL + "/services/T0/B0/x"); err != nil {
+		t.Fatalf("expected the webhook to exist, got %v", err)
+	}
+	if _, err := testSlackWebhook(server.URL + "/services/T0/B0/gone"); err == nil {
+		t.Fatalf("expected a missing webhook to be rejected")
+	}
+}
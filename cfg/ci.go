@@ -0,0 +1,41 @@
+package cfg
+
+import (
+	"errors"
+	"strings"
+)
+
+// CI resolves the identity for automation, such as a merge queue or backport
+// bot, landing commits on behalf of a pair. The humans given by aliases are
+// the author and bot, an identity like "Merge Bot <bot@example.com>", is the
+// committer. The same aliases give the same identity in any order, so
+// commits made by automation are reproducible. With no aliases the bot is
+// both author and committer.
+func (c *Config) CI(aliases []string, bot string) (author *Author, committer *Author, err error) {
+	committer, ok := ParseGuest(bot)
+	if !ok {
+		return nil, nil, errors.New("bot identity must look like Name <email>: " + bot)
+	}
+	humans, err := c.Resolve(aliases)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(humans) == 0 {
+		return committer, committer, nil
+	}
+	template, err := c.EmailTemplate()
+	if err != nil {
+		// Machines running automation rarely have an author of their own,
+		// so derive pair emails from the bot's domain instead.
+		template = "git@" + committer.Email[strings.LastIndex(committer.Email, "@")+1:]
+	}
+	email, err := c.ComposeEmail(template, humans)
+	if err != nil {
+		return nil, nil, err
+	}
+	name, err := c.FormatName(humans)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Author{Name: name, Email: email}, committer, nil
+}
@@ -0,0 +1,35 @@
+package cfg
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCI(t *testing.T) {
+	os.Unsetenv("PAIR_EMAIL")
+	config := &Config{Teammates: roster.Teammates}
+	author, committer, err := config.CI([]string{"lb", "gb"}, "Merge Bot <bot@bluth.com>")
+	if err != nil {
+		t.Fatalf("expected no error resolving the CI identity, got %v", err)
+	}
+	if author.Name != "George Bluth and Lindsay Bluth" || author.Email != "git+gb+lb@bluth.com" {
+		t.Fatalf("expected the humans as author with emails from the bot's domain, got %s <%s>", author.Name, author.Email)
+	}
+	if committer.Name != "Merge Bot" || committer.Email != "bot@bluth.com" {
+		t.Fatalf("expected the bot as committer, got %s <%s>", committer.Name, committer.Email)
+	}
+
+	again, _, _ := config.CI([]string{"gb", "lb"}, "Merge Bot <bot@bluth.com>")
+	if *again != *author {
+		t.Fatalf("expected the same identity whatever the order of aliases, got %v and %v", again, author)
+	}
+
+	author, _, err = roster.CI(nil, "Merge Bot <bot@bluth.com>")
+	if err != nil || author.Email != "bot@bluth.com" {
+		t.Fatalf("expected the bot alone with no aliases, got %v, %v", author, err)
+	}
+
+	if _, _, err := roster.CI([]string{"lb"}, "merge-bot"); err == nil {
+		t.Fatalf("expected an error for a bot without an email")
+	}
+}
@@ -1,26 +1,177 @@
 package cfg
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"net/mail"
+	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	"gopkg.in/yaml.v2"
+	"github.com/keeferrourke/pair/internal/lockedfile"
+	"gopkg.in/yaml.v3"
 )
 
 // Config contains configurations used on a per repo basis. Serializes to YAML.
 type Config struct {
-	Vcs       string    `yaml:"vcs"`       // What VCS are you using?
-	Author    *Author   `yaml:"author"`    // Who's machine is this?
-	Teammates []*Author `yaml:"teammates"` // Who's working with you?
-	Path      string    // Where this config came from
+	Vcs         string              `yaml:"vcs"`                    // What VCS are you using?
+	Author      *Author             `yaml:"author"`                 // Who's machine is this?
+	Teammates   []*Author           `yaml:"teammates"`              // Who's working with you?
+	Groups      map[string][]string `yaml:"groups,omitempty"`       // Named sets of aliases. e.g. frontend: [lb, gb]
+	SessionTTL  string              `yaml:"session_ttl,omitempty"`  // How long until a pair goes stale? e.g. 8h
+	IdleTimeout string              `yaml:"idle_timeout,omitempty"` // How long without commits until you're unpaired? e.g. 4h
+	Policy      *Policy             `yaml:"policy,omitempty"`       // How strictly are the rules enforced?
+	Mob         *Mob                `yaml:"mob,omitempty"`          // Who takes turns driving? Usually set in the repository's config
+	Rotation    *Rotation           `yaml:"rotation,omitempty"`     // Who takes turns leading the author string?
+	Path        string              `yaml:"-"`                      // Where this config came from
+
+	NameTemplate   string     `yaml:"name_template,omitempty"`   // How are pair names composed? See FormatName
+	TrailerStyle   string     `yaml:"trailer_style,omitempty"`   // How are co-authors credited? Combined or CoAuthor
+	EmailStyle     string     `yaml:"email_style,omitempty"`     // What email does a pair commit as? PlusAddress, AuthorEmail or GitHubNoreply
+	EmailSeparator string     `yaml:"email_separator,omitempty"` // What joins the aliases in a pair's email? "+" if empty
+	Attribution    string     `yaml:"attribution,omitempty"`     // Is the pair the committer too? AttributeBoth or AttributeAuthorOnly
+	BranchTemplate string     `yaml:"branch_template,omitempty"` // How are branches named? See FormatBranch
+	DefaultBranch  string     `yaml:"default_branch,omitempty"`  // What do new branches start from? Detected from origin/HEAD if empty
+	TerminalTitle  bool       `yaml:"terminal_title,omitempty"`  // Should the terminal title show the pair?
+	Strict         bool       `yaml:"strict,omitempty"`          // Must aliases be typed exactly? Otherwise a unique prefix will do
+	Log            []string   `yaml:"log,omitempty"`             // Where else are changes logged? e.g. [syslog, "file:/var/log/pair.log"]
+	TeamRosterURL  string     `yaml:"team_url,omitempty"`        // Where's the organization roster?
+	Locale         string     `yaml:"locale,omitempty"`          // Which language are messages in? e.g. fr_CA
+	SlackWebhook   string     `yaml:"slack_webhook,omitempty"`   // Where do reminders get posted? A Slack incoming webhook URL
+	Git            *Git       `yaml:"git,omitempty"`             // How is git run?
+	Directory      *Directory `yaml:"directory,omitempty"`       // Where are unknown aliases looked up? e.g. an LDAP server
+	Tracker        *Tracker   `yaml:"tracker,omitempty"`         // Where are tickets in branch names looked up? e.g. Jira
+	Org            []*Author  `yaml:"-"`                         // Who else is in the organization?
+	Repo           *Config    `yaml:"-"`                         // The repository's own config, see FindRepoFile
+
+	doc       *yaml.Node     // The document as it was read, comments and all
+	env       []EnvOverride  // Settings overridden from the environment, see ApplyEnv
+	defaulted []string       // Settings filled in from the built-in defaults, see ApplyDefaults
+	fromRepo  []string       // Settings overridden by the repository's config, see ApplyRepo
+	builtin   []*Author      // The built-in roster, beneath the organization roster
+	legacy    []legacyAuthor // People in the legacy pairs files, see ReadLegacyRoster
+	warnings  []error        // Problems reading the config which weren't fatal, see Warnings
+}
+
+// Policy describes how hooks react when pairing rules are broken. Each rule
+// is either "warn" (the default) or "block", and a stale pair can also
+// "revert" to just you. Serialized to YAML.
+type Policy struct {
+	Stale    string `yaml:"stale,omitempty"`    // Using a pair past the session TTL or its expiry
+	Trailers string `yaml:"trailers,omitempty"` // Committing while pairing without a trailer for each co-author
+}
+
+// Directory describes a company directory, such as LDAP or Active
+// Directory, which aliases nobody in the roster has are looked up in.
+// Serialized to YAML.
+type Directory struct {
+	URL            string `yaml:"url"`                       // Server. e.g. ldaps://ldap.example.com
+	BaseDN         string `yaml:"base_dn"`                   // Where people are. e.g. ou=people,dc=example,dc=com
+	BindDN         string `yaml:"bind_dn,omitempty"`         // Who to search as, anonymously if empty
+	AliasAttribute string `yaml:"alias_attribute,omitempty"` // Holds the alias, uid if empty. e.g. sAMAccountName
+	NameAttribute  string `yaml:"name_attribute,omitempty"`  // Holds the name, cn if empty. e.g. displayName
+	EmailAttribute string `yaml:"email_attribute,omitempty"` // Holds the email, mail if empty
+}
+
+// Tracker describes the issue tracker whose tickets branches are named after,
+// so `pair branch ONCALL-843` can add the ticket's title. Its token is kept
+// by pair auth. Serialized to YAML.
+type Tracker struct {
+	Kind string `yaml:"kind"`           // Which tracker: TrackJira or TrackGitHub
+	Site string `yaml:"site,omitempty"` // Jira site, or GitHub Enterprise API root. e.g. https://example.atlassian.net
+	Repo string `yaml:"repo,omitempty"` // GitHub repository whose issues are tickets, origin's if empty. e.g. bluth/banana-stand
+}
+
+// Issue trackers.
+const (
+	TrackJira   = "jira"
+	TrackGitHub = "github"
+)
+
+// Git describes how git is run, for systems with several git installs or
+// wrapper scripts. Serialized to YAML.
+type Git struct {
+	Path string   `yaml:"path,omitempty"` // Git binary. e.g. /opt/git/bin/git
+	Args []string `yaml:"args,omitempty"` // Passed before every command. e.g. [-c, protocol.file.allow=always]
 }
 
+// Policy actions.
+const (
+	Warn   = "warn"
+	Block  = "block"
+	Revert = "revert" // Only for stale: go back to just you
+)
+
 // Author describes a project collaborator. Serialized to YAML.
 type Author struct {
-	Name  string `yaml:"name"`  // Author name. e.g. Lindsey Bluth
-	Alias string `yaml:"alias"` // Nickname. e.g. lb
-	Email string `yaml:"email"` // Email address. e.g. lindsb@example.com
+	Name        string `yaml:"name" json:"name"`                                     // Author name. e.g. Lindsey Bluth
+	Alias       string `yaml:"alias" json:"alias"`                                   // Nickname. e.g. lb
+	Email       string `yaml:"email,omitempty" json:"email"`                         // Email address. e.g. lindsb@example.com
+	Status      string `yaml:"status,omitempty" json:"status,omitempty"`             // Availability. e.g. away
+	Until       string `yaml:"until,omitempty" json:"until,omitempty"`               // Last day of the status. e.g. 2026-11-01
+	Timezone    string `yaml:"timezone,omitempty" json:"timezone,omitempty"`         // Where are they? e.g. Europe/Berlin
+	Hours       string `yaml:"hours,omitempty" json:"hours,omitempty"`               // Working hours, local. e.g. 9-17
+	DisplayName string `yaml:"display_name,omitempty" json:"display_name,omitempty"` // Preferred name. e.g. Lindsay
+	Pronouns    string `yaml:"pronouns,omitempty" json:"pronouns,omitempty"`         // e.g. she/her
+	SigningKey  string `yaml:"signingkey,omitempty" json:"signingkey,omitempty"`     // GPG key id or SSH public key
+	GitHub      string `yaml:"github,omitempty" json:"github,omitempty"`             // GitHub login. e.g. lindsay
+	GitHubID    int64  `yaml:"github_id,omitempty" json:"github_id,omitempty"`       // GitHub's numeric ID for the login
+}
+
+// PreferredName returns the name the author prefers to be shown as, which
+// may differ from the name used in commits.
+func (a *Author) PreferredName() string {
+	if a.DisplayName != "" {
+		return a.DisplayName
+	}
+	return a.Name
+}
+
+// Label returns the author's preferred name followed by their pronouns, if
+// they've shared them. e.g. "Lindsay (she/her)".
+func (a *Author) Label() string {
+	if a.Pronouns == "" {
+		return a.PreferredName()
+	}
+	return fmt.Sprintf("%s (%s)", a.PreferredName(), a.Pronouns)
+}
+
+// NoreplyEmail returns the address GitHub credits commits to the author with
+// without revealing their own email, or "" if their GitHub login isn't
+// known. e.g. 1234+lindsay@users.noreply.github.com
+func (a *Author) NoreplyEmail() string {
+	switch {
+	case a.GitHub == "":
+		return ""
+	case a.GitHubID == 0:
+		// Accounts created before July 2017 can still use the old form.
+		return a.GitHub + "@users.noreply.github.com"
+	}
+	return fmt.Sprintf("%d+%s@users.noreply.github.com", a.GitHubID, a.GitHub)
+}
+
+// Away is the status of a teammate who shouldn't be paired with.
+const Away = "away"
+
+// AwayOn reports whether the author is marked away on the day of now. An away
+// status without an until date lasts until it's removed.
+func (a *Author) AwayOn(now time.Time) bool {
+	if a.Status != Away {
+		return false
+	}
+	if a.Until == "" {
+		return true
+	}
+	until, err := time.ParseInLocation("2006-01-02", a.Until, now.Location())
+	if err != nil {
+		return true
+	}
+	return now.Before(until.AddDate(0, 0, 1))
 }
 
 // ByName implements sort.Interface for []*Author based on the author name.
@@ -44,10 +195,15 @@ func NewFromFile(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	config := Config{Path: path}
-	if err := yaml.Unmarshal(buf, &config); err != nil {
+	config := Config{Path: path, doc: &yaml.Node{}}
+	if err := yaml.Unmarshal(buf, config.doc); err != nil {
 		return nil, err
 	}
+	if config.doc.Kind != 0 {
+		if err := config.doc.Decode(&config); err != nil {
+			return nil, err
+		}
+	}
 	return &config, nil
 }
 
@@ -60,16 +216,76 @@ func (c *Config) Reload() error {
 	c.Vcs = updated.Vcs
 	c.Author = updated.Author
 	c.Teammates = updated.Teammates
+	c.Groups = updated.Groups
+	c.SessionTTL = updated.SessionTTL
+	c.IdleTimeout = updated.IdleTimeout
+	c.Policy = updated.Policy
+	c.Mob = updated.Mob
+	c.Rotation = updated.Rotation
+	c.TeamRosterURL = updated.TeamRosterURL
+	c.NameTemplate = updated.NameTemplate
+	c.TrailerStyle = updated.TrailerStyle
+	c.EmailStyle = updated.EmailStyle
+	c.EmailSeparator = updated.EmailSeparator
+	c.Attribution = updated.Attribution
+	c.BranchTemplate = updated.BranchTemplate
+	c.DefaultBranch = updated.DefaultBranch
+	c.TerminalTitle = updated.TerminalTitle
+	c.Strict = updated.Strict
+	c.Log = updated.Log
+	c.Locale = updated.Locale
+	c.SlackWebhook = updated.SlackWebhook
+	c.Git = updated.Git
+	c.Directory = updated.Directory
+	c.Tracker = updated.Tracker
+	c.doc = updated.doc
 	return nil
 }
 
-// Save saves the config to disk.
+// Save saves the config to disk, creating its directory if necessary. When
+// the config was read from a file, the changes are merged into the document
+// on disk so hand-written comments, key order and anchors survive, as do
+// settings changed there since it was read, unless this config changed them
+// too. The file, or the file it links to, is replaced atomically under a
+// lock, keeping the old one beside it as a .bak.
 func (c *Config) Save() error {
-	buf, err := yaml.Marshal(c)
-	if err != nil {
+	var updated yaml.Node
+	if err := updated.Encode(c); err != nil {
 		return err
 	}
-	return ioutil.WriteFile(c.Path, buf, 0644)
+	c.withoutOverrides(&updated)
+	path := c.Path
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return lockedfile.Update(path, 0644, func(old []byte) ([]byte, error) {
+		doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&updated}}
+		if c.doc != nil && len(c.doc.Content) > 0 {
+			current := &yaml.Node{}
+			if err := yaml.Unmarshal(old, current); err != nil {
+				return nil, err
+			}
+			if len(current.Content) > 0 {
+				current.Content[0] = merge(current.Content[0], rebase(c.doc.Content[0], current.Content[0], &updated))
+				doc = current
+			}
+		}
+		var b bytes.Buffer
+		enc := yaml.NewEncoder(&b)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+		enc.Close()
+		// Keep what this config holds as the base of the next save, so it
+		// doesn't undo changes made on disk that it never read.
+		if c.doc != nil && len(c.doc.Content) > 0 {
+			c.doc.Content[0] = merge(c.doc.Content[0], &updated)
+		} else {
+			c.doc = doc
+		}
+		return b.Bytes(), nil
+	})
 }
 
 // Validate checks that an in-memory configuration is ok.
@@ -83,9 +299,360 @@ func (c *Config) Validate() (bool, error) {
 	if c.Author.Email == "" {
 		return false, errors.New("author.email is required")
 	}
+	if _, err := c.TTL(); err != nil {
+		return false, err
+	}
+	if _, err := c.Idle(); err != nil {
+		return false, err
+	}
+	for _, a := range c.Teammates {
+		if a.Until != "" {
+			if _, err := time.Parse("2006-01-02", a.Until); err != nil {
+				return false, fmt.Errorf("until for %s must be a date like 2006-01-02, got %s", a.Alias, a.Until)
+			}
+		}
+		if _, err := a.Location(); err != nil {
+			return false, fmt.Errorf("timezone for %s: %v", a.Alias, err)
+		}
+		if _, _, err := a.WorkingHours(); err != nil {
+			return false, fmt.Errorf("hours for %s: %v", a.Alias, err)
+		}
+	}
+	if _, err := c.FormatName([]*Author{c.Author}); err != nil {
+		return false, fmt.Errorf("name_template: %v", err)
+	}
+	switch c.TrailerStyle {
+	case "", Combined, CoAuthor:
+	default:
+		return false, fmt.Errorf("trailer_style must be %s or %s, got %s", Combined, CoAuthor, c.TrailerStyle)
+	}
+	if strings.ContainsAny(c.EmailSeparator, "@ \t\"<>(),;:") {
+		return false, fmt.Errorf("email_separator can't go in an email address, got %q", c.EmailSeparator)
+	}
+	if _, err := c.FormatBranch([]*Author{c.Author}, "ONCALL-843"); err != nil {
+		return false, fmt.Errorf("branch_template: %v", err)
+	}
+	switch c.EmailStyle {
+	case "", PlusAddress, AuthorEmail, GitHubNoreply:
+	default:
+		return false, fmt.Errorf("email_style must be %s, %s or %s, got %s", PlusAddress, AuthorEmail, GitHubNoreply, c.EmailStyle)
+	}
+	switch c.Attribution {
+	case "", AttributeBoth, AttributeAuthorOnly:
+	default:
+		return false, fmt.Errorf("attribution must be %s or %s, got %s", AttributeBoth, AttributeAuthorOnly, c.Attribution)
+	}
+	if c.Rotation != nil {
+		switch c.Rotation.Every {
+		case "", RotatePair, RotateCommit:
+		default:
+			return false, fmt.Errorf("rotation.every must be %s or %s, got %s", RotatePair, RotateCommit, c.Rotation.Every)
+		}
+	}
+	if c.Directory != nil && (c.Directory.URL == "" || c.Directory.BaseDN == "") {
+		return false, errors.New("directory.url and directory.base_dn are required")
+	}
+	if c.Tracker != nil {
+		switch c.Tracker.Kind {
+		case TrackJira:
+			if c.Tracker.Site == "" {
+				return false, errors.New("tracker.site is required for jira")
+			}
+		case TrackGitHub:
+		default:
+			return false, fmt.Errorf("tracker.kind must be %s or %s, got %s", TrackJira, TrackGitHub, c.Tracker.Kind)
+		}
+	}
+	if c.Mob != nil {
+		seen := make(map[string]bool)
+		for _, alias := range c.Mob.Order {
+			if seen[alias] {
+				return false, fmt.Errorf("mob.order lists %s more than once", alias)
+			}
+			seen[alias] = true
+		}
+	}
+	if c.Policy != nil {
+		switch c.Policy.Stale {
+		case "", Warn, Block, Revert:
+		default:
+			return false, fmt.Errorf("policy.stale must be %s, %s or %s, got %s", Warn, Block, Revert, c.Policy.Stale)
+		}
+		switch c.Policy.Trailers {
+		case "", Warn, Block:
+		default:
+			return false, fmt.Errorf("policy.trailers must be %s or %s, got %s", Warn, Block, c.Policy.Trailers)
+		}
+	}
 	return true, nil
 }
 
+// TTL returns how long a pair stays fresh, or zero if it never goes stale.
+func (c *Config) TTL() (time.Duration, error) {
+	setting := c.SessionTTL
+	if setting == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(setting)
+	if err != nil {
+		return 0, fmt.Errorf("session_ttl is not a duration: %v", err)
+	}
+	return ttl, nil
+}
+
+// ShowTitle reports whether changing the pair should update the terminal
+// title: $PAIR_TITLE if set, otherwise terminal_title from the config.
+func (c *Config) ShowTitle() bool {
+	if env := os.Getenv("PAIR_TITLE"); env != "" {
+		show, _ := strconv.ParseBool(env)
+		return show
+	}
+	return c.TerminalTitle
+}
+
+// Webhook returns the Slack incoming webhook reminders are posted to:
+// $PAIR_SLACK_WEBHOOK if set, otherwise slack_webhook from the config.
+func (c *Config) Webhook() string {
+	if url := os.Getenv("PAIR_SLACK_WEBHOOK"); url != "" {
+		return url
+	}
+	return c.SlackWebhook
+}
+
+// Idle returns how long a pair may go without commits before it's reverted
+// to just you, or zero if it never is.
+func (c *Config) Idle() (time.Duration, error) {
+	if c.IdleTimeout == "" {
+		return 0, nil
+	}
+	idle, err := time.ParseDuration(c.IdleTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("idle_timeout is not a duration: %v", err)
+	}
+	return idle, nil
+}
+
+// OnStale returns the policy action for using a stale pair.
+func (c *Config) OnStale() string {
+	if c.Policy == nil || c.Policy.Stale == "" {
+		return Warn
+	}
+	return c.Policy.Stale
+}
+
+// OnMissingTrailers returns the policy action for committing while pairing
+// without crediting every co-author with a trailer.
+func (c *Config) OnMissingTrailers() string {
+	if c.Policy == nil || c.Policy.Trailers == "" {
+		return Warn
+	}
+	return c.Policy.Trailers
+}
+
+// AddTeammate adds a to the teammates, refusing invalid or duplicate aliases
+// and emails.
+func (c *Config) AddTeammate(a *Author) error {
+	if err := validateTeammate(a); err != nil {
+		return err
+	}
+	if _, ok := c.Groups[a.Alias]; ok {
+		return fmt.Errorf("alias %s is already the name of a group", a.Alias)
+	}
+	for _, other := range c.localAuthors() {
+		if other.Alias == a.Alias {
+			return fmt.Errorf("alias %s is already taken by %s", a.Alias, other.Name)
+		}
+		if a.Email != "" && strings.EqualFold(other.Email, a.Email) {
+			return fmt.Errorf("email %s already belongs to %s", a.Email, other.Name)
+		}
+	}
+	c.Teammates = append(c.Teammates, a)
+	return nil
+}
+
+// UpdateTeammate changes the name and email of you or the teammate with
+// alias, leaving either as it is if empty, and returns who was updated.
+func (c *Config) UpdateTeammate(alias, name, email string) (*Author, error) {
+	a := c.lookupLocal(alias)
+	if a == nil {
+		return nil, errors.New("no such username: " + alias)
+	}
+	updated := *a
+	if name != "" {
+		updated.Name = name
+	}
+	if email != "" {
+		updated.Email = email
+	}
+	if err := validateTeammate(&updated); err != nil {
+		return nil, err
+	}
+	for _, other := range c.localAuthors() {
+		if other != a && updated.Email != "" && strings.EqualFold(other.Email, updated.Email) {
+			return nil, fmt.Errorf("email %s already belongs to %s", updated.Email, other.Name)
+		}
+	}
+	*a = updated
+	return a, nil
+}
+
+// localAuthors returns you, if set, and your teammates.
+func (c *Config) localAuthors() []*Author {
+	if c.Author == nil {
+		return c.Teammates
+	}
+	return append([]*Author{c.Author}, c.Teammates...)
+}
+
+// validateTeammate checks that a has a name, an alias that can be given on
+// the command line and go in a pair's email, and an email that's a bare
+// address if it has one.
+func validateTeammate(a *Author) error {
+	if a.Alias == "" || a.Name == "" {
+		return errors.New("alias and name are required")
+	}
+	if strings.HasPrefix(a.Alias, "-") || strings.ContainsAny(a.Alias, " \t\n,+@<>\"") {
+		return fmt.Errorf("alias %q can't start with - or contain spaces or any of ,+@<>\"", a.Alias)
+	}
+	if a.Email != "" {
+		if addr, err := mail.ParseAddress(a.Email); err != nil || addr.Address != a.Email {
+			return fmt.Errorf("email %s isn't an email address like lb@example.com", a.Email)
+		}
+	}
+	return nil
+}
+
+// RemoveTeammate removes the teammate with alias, scrubbing them from every
+// group as well. Groups left empty are removed too.
+func (c *Config) RemoveTeammate(alias string) (*Author, error) {
+	if c.Author != nil && c.Author.Alias == alias {
+		return nil, errors.New("you can't remove yourself from the roster")
+	}
+	var removed *Author
+	for i, a := range c.Teammates {
+		if a.Alias == alias {
+			removed = a
+			c.Teammates = append(c.Teammates[:i], c.Teammates[i+1:]...)
+			break
+		}
+	}
+	if removed == nil {
+		return nil, errors.New("no such username: " + alias)
+	}
+	for group, aliases := range c.Groups {
+		var kept []string
+		for _, a := range aliases {
+			if a != alias {
+				kept = append(kept, a)
+			}
+		}
+		if len(kept) == 0 {
+			delete(c.Groups, group)
+		} else {
+			c.Groups[group] = kept
+		}
+	}
+	return removed, nil
+}
+
+// Lookup finds the author, either you or a teammate, with the given name.
+func (c *Config) Lookup(name string) *Author {
+	if c.Author != nil && c.Author.Name == name {
+		return c.Author
+	}
+	for _, a := range c.Roster() {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// VerifyIdentity checks that a git identity is a valid composition of the
+// roster: either just you, or you plus one or more teammates joined with
+// " and ". An identity which doesn't include you is most likely someone
+// else's, left active on a shared machine.
+func (c *Config) VerifyIdentity(name, email string) error {
+	if c.Author == nil {
+		return errors.New("author can't be nil")
+	}
+	if name == "" || email == "" {
+		return errors.New("no git identity is set")
+	}
+
+	authors, err := c.identityAuthors(name, email)
+	if err != nil {
+		return err
+	}
+	includesYou := false
+	for _, a := range authors {
+		if a == c.Author {
+			includesYou = true
+		}
+	}
+	if !includesYou {
+		return fmt.Errorf("identity %s <%s> doesn't include you (%s); is someone else's identity still active?", name, email, c.Author.Name)
+	}
+	if len(authors) == 1 && c.EmailStyle == GitHubNoreply && strings.EqualFold(email, c.Author.NoreplyEmail()) {
+		return nil
+	}
+	if len(authors) == 1 && c.Author.Email != "" && !strings.EqualFold(email, c.Author.Email) {
+		return fmt.Errorf("email %s doesn't match your email %s", email, c.Author.Email)
+	}
+	return nil
+}
+
+// identityAuthors works out who is in a git identity. With the default names
+// they're found by name; with a name_template the name can't be taken apart,
+// so they're found by the aliases in the email and the name is checked
+// against the template instead.
+func (c *Config) identityAuthors(name, email string) ([]*Author, error) {
+	if c.NameTemplate == "" {
+		var authors []*Author
+		for _, n := range strings.Split(name, " and ") {
+			a := c.Lookup(n)
+			if a == nil {
+				return nil, fmt.Errorf("%s is not in the roster", n)
+			}
+			authors = append(authors, a)
+		}
+		return authors, nil
+	}
+
+	local := email
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		local = email[:at]
+	}
+	var authors []*Author
+	if parts := strings.Split(local, c.separator()); len(parts) > 1 {
+		for _, alias := range parts[1:] { // In the order they were composed
+			resolved, err := c.Resolve([]string{alias})
+			if err != nil {
+				return nil, err
+			}
+			authors = append(authors, resolved...)
+		}
+	} else {
+		for _, a := range append([]*Author{c.Author}, c.Roster()...) {
+			if strings.EqualFold(a.Email, email) || a.Alias == local {
+				authors = []*Author{a}
+				break
+			}
+		}
+		if authors == nil {
+			return nil, fmt.Errorf("%s is not in the roster", email)
+		}
+	}
+	expected, err := c.FormatName(authors)
+	if err != nil {
+		return nil, fmt.Errorf("name_template: %v", err)
+	}
+	if name != expected {
+		return nil, fmt.Errorf("name %s doesn't match name_template, expected %s", name, expected)
+	}
+	return authors, nil
+}
+
 func (c *Config) equals(other *Config) bool {
 	if c == other {
 		return true
@@ -1,9 +1,15 @@
 package cfg
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 var (
@@ -116,9 +122,468 @@ func TestSave(t *testing.T) {
 	}
 }
 
+func TestSavePreservesComments(t *testing.T) {
+	f, _ := ioutil.TempFile("", "config-*.yml")
+	defer os.Remove(f.Name()) // clean up
+	original := `# Team roster; ask in #pairing before removing anyone.
+vcs: git
+author: &me
+  name: Michael Bluth # that's me
+  alias: mb
+  email: mb@example.com
+teammates:
+  # Lindsay is on leave until May.
+  - name: Lindsey Bluth
+    alias: lb
+  - name: George Bluth
+    alias: gb
+groups:
+  family: [lb, gb]
+`
+	ioutil.WriteFile(f.Name(), []byte(original), 0644)
+	config, err := NewFromFile(f.Name())
+	if err != nil {
+		t.Fatalf("error in NewFromFile: %v", err)
+	}
+	config.Teammates[0].Name = "Lindsay Bluth"
+	if _, err := config.RemoveTeammate("gb"); err != nil {
+		t.Fatalf("error removing teammate: %v", err)
+	}
+	config.AddTeammate(&Author{Name: "Buster Bluth", Alias: "bb"})
+	if err := config.Save(); err != nil {
+		t.Fatalf("error saving config: %v", err)
+	}
+
+	buf, _ := ioutil.ReadFile(f.Name())
+	expected := `# Team roster; ask in #pairing before removing anyone.
+vcs: git
+author: &me
+  name: Michael Bluth # that's me
+  alias: mb
+  email: mb@example.com
+teammates:
+  # Lindsay is on leave until May.
+  - name: Lindsay Bluth
+    alias: lb
+  - name: Buster Bluth
+    alias: bb
+groups:
+  family: [lb]
+`
+	if string(buf) != expected {
+		t.Fatalf("expected comments and layout to survive, got\n%s", buf)
+	}
+}
+
+func TestSaveKeepsChangesOnDisk(t *testing.T) {
+	f, _ := ioutil.TempFile("", "config-*.yml")
+	defer os.Remove(f.Name()) // clean up
+	ioutil.WriteFile(f.Name(), []byte("vcs: git\nauthor:\n  name: Michael Bluth\n  alias: mb\n  email: mb@example.com\nteammates: []\n"), 0644)
+	config, err := NewFromFile(f.Name())
+	if err != nil {
+		t.Fatalf("error in NewFromFile: %v", err)
+	}
+
+	// Another pair command changes the file in the meantime.
+	ioutil.WriteFile(f.Name(), []byte("vcs: git\nauthor:\n  name: Michael Bluth\n  alias: mb\n  email: mb@example.com\nteammates: []\nsession_ttl: 4h # set elsewhere\n"), 0644)
+	config.Author.Name = "Michael Bluth Sr."
+	if err := config.Save(); err != nil {
+		t.Fatalf("error saving config: %v", err)
+	}
+
+	buf, _ := ioutil.ReadFile(f.Name())
+	expected := "vcs: git\nauthor:\n  name: Michael Bluth Sr.\n  alias: mb\n  email: mb@example.com\nteammates: []\nsession_ttl: 4h # set elsewhere\n"
+	if string(buf) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, buf)
+	}
+
+	config.AddTeammate(&Author{Name: "Lindsay Bluth", Alias: "lb"})
+	if err := config.Save(); err != nil {
+		t.Fatalf("error saving config: %v", err)
+	}
+	written, _ := NewFromFile(f.Name())
+	if written.SessionTTL != "4h" || len(written.Teammates) != 1 {
+		t.Fatalf("expected session_ttl and the new teammate to survive a second save, got %q and %d teammates", written.SessionTTL, len(written.Teammates))
+	}
+}
+
+func TestSaveFollowsSymlink(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "config")
+	defer os.RemoveAll(dir) // clean up
+	target := filepath.Join(dir, "dotfiles.yml")
+	link := filepath.Join(dir, "pair.yml")
+	ioutil.WriteFile(target, []byte("vcs: git\nauthor:\n  name: Michael Bluth\n  alias: mb\n  email: mb@example.com\n"), 0644)
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("unable to symlink: %v", err)
+	}
+	config, err := NewFromFile(link)
+	if err != nil {
+		t.Fatalf("error in NewFromFile: %v", err)
+	}
+	config.Author.Name = "Michael Bluth Sr."
+	if err := config.Save(); err != nil {
+		t.Fatalf("error saving config: %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected %s to still be a symlink", link)
+	}
+	written, err := NewFromFile(target)
+	if err != nil {
+		t.Fatalf("error in NewFromFile: %v", err)
+	}
+	if written.Author.Name != "Michael Bluth Sr." {
+		t.Fatalf("expected the change in %s, got author %q", target, written.Author.Name)
+	}
+}
+
+func TestVerifyIdentity(t *testing.T) {
+	config = &Config{
+		Author: &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates: []*Author{
+			&Author{Name: "Lindsay Bluth", Alias: "lb"},
+			&Author{Name: "George Bluth", Alias: "gb"},
+		},
+	}
+	valid := map[string]string{
+		"Michael Bluth":                   "mb@example.com",
+		"Lindsay Bluth and Michael Bluth": "git+lb+mb@example.com",
+	}
+	for name, email := range valid {
+		if err := config.VerifyIdentity(name, email); err != nil {
+			t.Fatalf("expected %s <%s> to be valid, got %v", name, email, err)
+		}
+	}
+	invalid := map[string]string{
+		"":                               "",
+		"Lindsay Bluth":                  "lb@example.com",
+		"George Bluth and Lindsay Bluth": "git+gb+lb@example.com",
+		"Buster Bluth and Michael Bluth": "git+bb+mb@example.com",
+		"Michael Bluth ":                 "mb@example.com",
+	}
+	for name, email := range invalid {
+		if err := config.VerifyIdentity(name, email); err == nil {
+			t.Fatalf("expected %q <%s> to be invalid", name, email)
+		}
+	}
+	if err := config.VerifyIdentity("Michael Bluth", "gob@example.com"); err == nil {
+		t.Fatalf("expected a solo identity with someone else's email to be invalid")
+	}
+}
+
+func TestAddTeammate(t *testing.T) {
+	config = &Config{
+		Author:    &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates: []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"}},
+	}
+	if err := config.AddTeammate(&Author{Name: "Nina Kharlamova", Alias: "nk", Email: "nk@example.com"}); err != nil {
+		t.Fatalf("expected no error adding a new teammate, got %v", err)
+	}
+	if len(config.Teammates) != 2 {
+		t.Fatalf("expected teammate to be added, got %v", config.Teammates)
+	}
+	duplicates := []*Author{
+		&Author{Name: "Maeby Fünke", Alias: "mb"},
+		&Author{Name: "Lucille Bluth", Alias: "lb"},
+		&Author{Name: "Lindsay Fünke", Alias: "lf", Email: "LB@example.com"},
+		&Author{Alias: "gb"},
+		&Author{Name: "Tobias Fünke", Alias: "t f"},
+		&Author{Name: "Tobias Fünke", Alias: "tf+"},
+		&Author{Name: "Tobias Fünke", Alias: "-tf"},
+		&Author{Name: "Tobias Fünke", Alias: "tf", Email: "Tobias <tf@example.com>"},
+		&Author{Name: "Tobias Fünke", Alias: "tf", Email: "tf"},
+		&Author{Name: "Bluth Family", Alias: "family"},
+	}
+	config.Groups = map[string][]string{"family": {"mb", "lb"}}
+	for _, a := range duplicates {
+		if err := config.AddTeammate(a); err == nil {
+			t.Fatalf("expected an error adding %v", a)
+		}
+	}
+}
+
+func TestUpdateTeammate(t *testing.T) {
+	config = &Config{
+		Author: &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates: []*Author{
+			&Author{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"},
+			&Author{Name: "George Bluth", Alias: "gb"},
+		},
+	}
+	updated, err := config.UpdateTeammate("lb", "Lindsay Bluth Fünke", "")
+	if err != nil || updated != config.Teammates[0] {
+		t.Fatalf("expected Lindsay to be updated, got %v, %v", updated, err)
+	}
+	if updated.Name != "Lindsay Bluth Fünke" || updated.Email != "lb@example.com" {
+		t.Fatalf("expected only the name to change, got %s <%s>", updated.Name, updated.Email)
+	}
+	if _, err := config.UpdateTeammate("lb", "", "LB@example.com"); err != nil {
+		t.Fatalf("expected changing the case of your own email to be fine, got %v", err)
+	}
+	if _, err := config.UpdateTeammate("gb", "", "mb@example.com"); err == nil {
+		t.Fatalf("expected an error taking someone else's email")
+	}
+	if _, err := config.UpdateTeammate("gb", "", "not an email"); err == nil {
+		t.Fatalf("expected an error for an invalid email")
+	}
+	if config.Teammates[1].Email != "" {
+		t.Fatalf("expected a failed update to change nothing, got %s", config.Teammates[1].Email)
+	}
+	if _, err := config.UpdateTeammate("bb", "Buster Bluth", ""); err == nil {
+		t.Fatalf("expected an error updating an unknown alias")
+	}
+}
+
+func TestRemoveTeammate(t *testing.T) {
+	config = &Config{
+		Author: &Author{Name: "Michael Bluth", Alias: "mb"},
+		Teammates: []*Author{
+			&Author{Name: "Lindsay Bluth", Alias: "lb"},
+			&Author{Name: "George Bluth", Alias: "gb"},
+		},
+		Groups: map[string][]string{
+			"family": {"gb", "lb"},
+			"dads":   {"gb"},
+		},
+	}
+	removed, err := config.RemoveTeammate("gb")
+	if err != nil || removed.Name != "George Bluth" {
+		t.Fatalf("expected George Bluth to be removed, got %v, %v", removed, err)
+	}
+	if len(config.Teammates) != 1 || config.Teammates[0].Alias != "lb" {
+		t.Fatalf("expected only lb to remain, got %v", config.Teammates)
+	}
+	if len(config.Groups["family"]) != 1 {
+		t.Fatalf("expected gb to be scrubbed from groups, got %v", config.Groups)
+	}
+	if _, ok := config.Groups["dads"]; ok {
+		t.Fatalf("expected an emptied group to be removed, got %v", config.Groups)
+	}
+	if _, err := config.RemoveTeammate("gb"); err == nil {
+		t.Fatalf("expected an error removing an unknown alias")
+	}
+	if _, err := config.RemoveTeammate("mb"); err == nil {
+		t.Fatalf("expected an error removing yourself")
+	}
+}
+
+func TestAddLegacy(t *testing.T) {
+	authors := map[string]string{"mb": "Michael Bluth"}
+	if err := AddLegacy(authors, "lb", "Lindsay Bluth"); err != nil {
+		t.Fatalf("expected no error adding a new username, got %v", err)
+	}
+	if err := AddLegacy(authors, "mb", "Maeby Fünke"); err == nil {
+		t.Fatalf("expected an error adding a duplicate username")
+	}
+	if authors["mb"] != "Michael Bluth" || len(authors) != 2 {
+		t.Fatalf("expected existing usernames to be untouched, got %v", authors)
+	}
+}
+
+func TestReadLegacyFiles(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "pairs")
+	defer os.RemoveAll(dir) // clean up
+	personal := filepath.Join(dir, "personal")
+	team := filepath.Join(dir, "team")
+	ioutil.WriteFile(personal, []byte("---\nmb: Michael Bluth\nlb: Lindsay Bluth\n"), 0644)
+	ioutil.WriteFile(team, []byte("---\nlb: Lindsay Fünke\ngb: George Bluth\n"), 0644)
+
+	authors, conflicts, err := ReadLegacyFiles([]string{personal, filepath.Join(dir, "missing"), team})
+	if err != nil {
+		t.Fatalf("expected no error merging pairs files, got %v", err)
+	}
+	if len(authors) != 3 || authors["lb"] != "Lindsay Fünke" {
+		t.Fatalf("expected later files to override earlier ones, got %v", authors)
+	}
+	if len(conflicts) != 1 || conflicts[0].Overrides != "Lindsay Bluth" || conflicts[0].Previous != personal {
+		t.Fatalf("expected the lb conflict to be reported, got %v", conflicts)
+	}
+
+	if _, _, err := ReadLegacyFiles([]string{filepath.Join(dir, "missing")}); !os.IsNotExist(err) {
+		t.Fatalf("expected an error when no pairs file exists, got %v", err)
+	}
+}
+
+func TestSplitLegacyPaths(t *testing.T) {
+	paths := SplitLegacyPaths("/home/mb/.pairs:https://pairs.example.com/team.yml:http://localhost:8080/pairs.yml:http://localhost:8080")
+	expected := []string{"/home/mb/.pairs", "https://pairs.example.com/team.yml", "http://localhost:8080/pairs.yml", "http://localhost:8080"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("expected URLs to be kept whole, got %q", paths)
+	}
+}
+
+func TestFetchLegacy(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "cache")
+	defer os.RemoveAll(dir) // clean up
+	os.Setenv("XDG_CACHE_HOME", dir)
+	defer os.Unsetenv("XDG_CACHE_HOME")
+
+	body := "---\ntf: Tobias Fünke\n"
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		fmt.Fprint(w, body)
+	}))
+	url := server.URL + "/pairs.yml"
+	if authors, err := ReadLegacy(url); err != nil || authors["tf"] != "Tobias Fünke" {
+		t.Fatalf("expected the remote pairs file, got %v, %v", authors, err)
+	}
+	body = "---\ntf: Tobias Fünke\nlb: Lindsay Bluth\n"
+	if authors, err := ReadLegacy(url); err != nil || len(authors) != 1 || fetches != 1 {
+		t.Fatalf("expected the cached copy within the TTL, got %v, %v after %d fetches", authors, err, fetches)
+	}
+	if authors, err := FetchLegacy(url, true); err != nil || len(authors) != 2 {
+		t.Fatalf("expected a refresh to fetch it again, got %v, %v", authors, err)
+	}
+
+	body = "<html>Service Unavailable</html>"
+	if _, err := FetchLegacy(url, true); err == nil {
+		t.Fatalf("expected an error for something other than a pairs file")
+	}
+	server.Close()
+	authors, err := FetchLegacy(url, true)
+	if _, ok := err.(*StaleTeamError); !ok {
+		t.Fatalf("expected a stale pairs file error once offline, got %v", err)
+	}
+	if len(authors) != 2 {
+		t.Fatalf("expected the last good copy, got %v", authors)
+	}
+	old := time.Now().Add(-2 * RemoteTTL)
+	os.Chtimes(TeamCachePath(url), old, old)
+	authors, _, err = ReadLegacyFiles([]string{url})
+	if _, ok := err.(*StaleTeamError); !ok || len(authors) != 2 {
+		t.Fatalf("expected the cached copy to be merged with a stale error, got %v, %v", authors, err)
+	}
+}
+
 func TestReload(t *testing.T) {
 }
 
 func TestValidate(t *testing.T) {
+	config = &Config{
+		Vcs:        "git",
+		Author:     &Author{Name: "Michael Bluth", Email: "mb@example.com"},
+		SessionTTL: "8h",
+		Policy:     &Policy{Stale: Block},
+	}
+	if ok, err := config.Validate(); !ok || err != nil {
+		t.Fatalf("expected config to be valid, got %v", err)
+	}
+	if ttl, _ := config.TTL(); ttl != 8*time.Hour {
+		t.Fatalf("expected session_ttl of 8h, got %v", ttl)
+	}
+
+	config.SessionTTL = "a while"
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unparseable session_ttl to be invalid")
+	}
+
+	config.SessionTTL = ""
+	config.IdleTimeout = "forever"
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unparseable idle_timeout to be invalid")
+	}
+
+	config.IdleTimeout = ""
+	config.Policy.Stale = "panic"
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unknown policy action to be invalid")
+	}
+
+	config.Policy.Stale = Revert
+	if ok, err := config.Validate(); !ok {
+		t.Fatalf("expected stale pairs to be able to revert, got %v", err)
+	}
+	config.Policy.Trailers = Revert
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected missing trailers not to be able to revert")
+	}
+
+	config.Policy.Stale = Warn
+	config.Policy.Trailers = "shrug"
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unknown trailers policy action to be invalid")
+	}
+	config.Policy.Trailers = Block
+	if config.OnMissingTrailers() != Block {
+		t.Fatalf("expected missing trailers to block, got %v", config.OnMissingTrailers())
+	}
+
+	config.Policy = nil
+	if config.OnMissingTrailers() != Warn {
+		t.Fatalf("expected missing trailers to warn by default, got %v", config.OnMissingTrailers())
+	}
+	config.TrailerStyle = "footnotes"
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unknown trailer_style to be invalid")
+	}
+
+	config.TrailerStyle = CoAuthor
+	if config.OnStale() != Warn {
+		t.Fatalf("expected stale pairs to warn by default, got %v", config.OnStale())
+	}
 
+	config.Mob = &Mob{Order: []string{"lb", "mb", "lb"}}
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected a mob.order with duplicates to be invalid")
+	}
+
+	config.Mob = nil
+	config.Directory = &Directory{URL: "ldaps://ldap.example.com"}
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected a directory without base_dn to be invalid")
+	}
+
+	config.Directory = nil
+	config.EmailSeparator = "@"
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an email_separator of @ to be invalid")
+	}
+
+	config.EmailSeparator = ""
+	config.Attribution = "committer-only"
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unknown attribution to be invalid")
+	}
+
+	config.Attribution = ""
+	config.Rotation = &Rotation{Every: "day"}
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unknown rotation.every to be invalid")
+	}
+
+	config.Rotation = nil
+	config.Tracker = &Tracker{Kind: TrackJira}
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected a jira tracker without a site to be invalid")
+	}
+	config.Tracker = &Tracker{Kind: "trello"}
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unknown tracker.kind to be invalid")
+	}
+
+	config.Tracker = nil
+	config.Teammates = []*Author{&Author{Alias: "lb", Status: Away, Until: "next week"}}
+	if ok, _ := config.Validate(); ok {
+		t.Fatalf("expected an unparseable until date to be invalid")
+	}
+}
+
+func TestAwayOn(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	a := &Author{Alias: "lb", Status: Away, Until: "2026-10-20"}
+	if !a.AwayOn(day(20)) {
+		t.Fatalf("expected lb to be away through the until date")
+	}
+	if a.AwayOn(day(21)) {
+		t.Fatalf("expected lb to be back after the until date")
+	}
+	a.Until = ""
+	if !a.AwayOn(day(21)) {
+		t.Fatalf("expected an away status without a date to last")
+	}
+	a.Status = ""
+	if a.AwayOn(day(1)) {
+		t.Fatalf("expected no status to mean available")
+	}
 }
@@ -0,0 +1,149 @@
+package cfg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Contributor is someone who has committed to a repository, as listed by
+// `git shortlog -sne`.
+type Contributor struct {
+	Name    string
+	Email   string
+	Commits int
+}
+
+// ParseShortlog parses the output of `git shortlog -sne`, lines like
+// "    12\tLindsay Bluth <lb@example.com>". Lines that don't parse are
+// skipped.
+func ParseShortlog(output string) []*Contributor {
+	var contributors []*Contributor
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		commits, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			continue
+		}
+		identity := fields[1]
+		open, end := strings.LastIndex(identity, "<"), strings.LastIndex(identity, ">")
+		if open < 0 || end < open {
+			continue
+		}
+		contributors = append(contributors, &Contributor{
+			Name:    strings.TrimSpace(identity[:open]),
+			Email:   identity[open+1 : end],
+			Commits: commits,
+		})
+	}
+	return contributors
+}
+
+// ProposeTeammates proposes roster entries for contributors, such as those of
+// several repositories, most commits first. Contributors with the same email
+// or name are taken to be the same person. Anyone already in the roster,
+// pairs committing together, and bots are left out. Each proposal gets an
+// alias nobody has: their GitHub login if their email is a GitHub noreply
+// address, otherwise their initials, or failing that the start of their
+// email.
+func (c *Config) ProposeTeammates(contributors []*Contributor) []*Author {
+	var merged []*Contributor
+	byKey := make(map[string]*Contributor)
+	for _, contributor := range contributors {
+		emailKey, nameKey := "email:"+strings.ToLower(contributor.Email), "name:"+strings.ToLower(contributor.Name)
+		if existing := byKey[emailKey]; existing != nil {
+			existing.Commits += contributor.Commits
+			byKey[nameKey] = existing
+			continue
+		}
+		if existing := byKey[nameKey]; existing != nil {
+			existing.Commits += contributor.Commits
+			byKey[emailKey] = existing
+			continue
+		}
+		copy := *contributor
+		merged = append(merged, &copy)
+		byKey[emailKey], byKey[nameKey] = &copy, &copy
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Commits > merged[j].Commits })
+
+	taken := make(map[string]bool)
+	for _, a := range c.Roster() {
+		taken[a.Alias] = true
+	}
+	if c.Author != nil {
+		taken[c.Author.Alias] = true
+	}
+	for group := range c.Groups {
+		taken[group] = true
+	}
+	var proposed []*Author
+	for _, contributor := range merged {
+		identity := fmt.Sprintf("%s <%s>", contributor.Name, contributor.Email)
+		if contributor.Name == "" || contributor.Email == "" || strings.Contains(contributor.Name, " and ") ||
+			strings.HasSuffix(contributor.Name, "[bot]") || len(c.Recognize([]string{identity})) > 0 {
+			continue
+		}
+		a := &Author{Name: contributor.Name, Email: contributor.Email}
+		a.GitHub, a.GitHubID = parseNoreply(contributor.Email)
+		a.Alias = proposeAlias(a, taken)
+		taken[a.Alias] = true
+		proposed = append(proposed, a)
+	}
+	return proposed
+}
+
+// proposeAlias returns the first alias for a that isn't taken.
+func proposeAlias(a *Author, taken map[string]bool) string {
+	var initials strings.Builder
+	for _, word := range strings.Fields(a.Name) {
+		if r := []rune(word)[0]; unicode.IsLetter(r) {
+			initials.WriteRune(unicode.ToLower(r))
+		}
+	}
+	local := a.Email
+	if i := strings.LastIndex(local, "@"); i >= 0 {
+		local = local[:i]
+	}
+	if i := strings.Index(local, "+"); i >= 0 {
+		local = local[:i]
+	}
+	candidates := []string{strings.ToLower(a.GitHub), initials.String(), strings.ToLower(local)}
+	for _, alias := range candidates {
+		if alias != "" && !taken[alias] && validateTeammate(&Author{Alias: alias, Name: a.Name}) == nil {
+			return alias
+		}
+	}
+	base := candidates[1]
+	if base == "" {
+		base = "t"
+	}
+	for n := 2; ; n++ {
+		if alias := base + strconv.Itoa(n); !taken[alias] {
+			return alias
+		}
+	}
+}
+
+// parseNoreply returns the GitHub login and ID in a GitHub noreply email, like
+// 1234+lindsay@users.noreply.github.com or lindsay@users.noreply.github.com.
+func parseNoreply(email string) (string, int64) {
+	const domain = "@users.noreply.github.com"
+	if !strings.HasSuffix(strings.ToLower(email), domain) {
+		return "", 0
+	}
+	local := email[:len(email)-len(domain)]
+	if i := strings.Index(local, "+"); i >= 0 {
+		id, err := strconv.ParseInt(local[:i], 10, 64)
+		if err != nil {
+			return "", 0
+		}
+		return local[i+1:], id
+	}
+	return local, 0
+}
@@ -0,0 +1,52 @@
+package cfg
+
+import (
+	"reflect"
+	"testing"
+)
+
+const shortlog = `    42	Lindsay Bluth <lb@example.com>
+    17	Lindsay Bluth and Michael Bluth <git+lb+mb@example.com>
+     9	Tobias Fünke <tobias@example.com>
+     5	dependabot[bot] <49699333+dependabot[bot]@users.noreply.github.com>
+     4	Tobias Funke <TOBIAS@example.com>
+     3	Maeby Fünke <1234+maebyf@users.noreply.github.com>
+     2	Lucille Bluth <lucille@example.com>
+     1	Lucille Bluth <lucille@bluth.com>
+garbage
+`
+
+func TestParseShortlog(t *testing.T) {
+	contributors := ParseShortlog(shortlog)
+	if len(contributors) != 8 {
+		t.Fatalf("expected 8 contributors, got %d", len(contributors))
+	}
+	expected := &Contributor{Name: "Tobias Fünke", Email: "tobias@example.com", Commits: 9}
+	if !reflect.DeepEqual(contributors[2], expected) {
+		t.Fatalf("expected %v, got %v", expected, contributors[2])
+	}
+}
+
+func TestProposeTeammates(t *testing.T) {
+	config := &Config{
+		Author:    &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates: []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"}},
+	}
+	more := ParseShortlog("    30\tLarry Bluth <larry@example.com>\n    20\tLeo Bluth <leo@example.com>\n")
+	proposed := config.ProposeTeammates(append(ParseShortlog(shortlog), more...))
+
+	var got []Author
+	for _, a := range proposed {
+		got = append(got, *a)
+	}
+	expected := []Author{
+		{Name: "Larry Bluth", Alias: "larry", Email: "larry@example.com"},
+		{Name: "Leo Bluth", Alias: "leo", Email: "leo@example.com"},
+		{Name: "Tobias Fünke", Alias: "tf", Email: "tobias@example.com"},
+		{Name: "Maeby Fünke", Alias: "maebyf", Email: "1234+maebyf@users.noreply.github.com", GitHub: "maebyf", GitHubID: 1234},
+		{Name: "Lucille Bluth", Alias: "lucille", Email: "lucille@example.com"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,92 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultsDir is a directory holding a built-in config.yml and roster.yml,
+// for organizational forks which ship a pre-configured pair. Set it at build
+// time with
+//
+//	go build -ldflags "-X github.com/keeferrourke/pair/cfg.DefaultsDir=/etc/pair"
+//
+// or embed the files in cfg/defaults into the binary with the pair_defaults
+// build tag instead.
+var DefaultsDir string
+
+// The built-in config and roster embedded with the pair_defaults build tag.
+// Empty otherwise.
+var embeddedConfig, embeddedRoster []byte
+
+// Defaults returns the built-in config and roster, from DefaultsDir if it's
+// set or else embedded in the binary. Both are nil if there are none.
+func Defaults() (*Config, []*Author, error) {
+	configBuf, rosterBuf := embeddedConfig, embeddedRoster
+	if DefaultsDir != "" {
+		var err error
+		if configBuf, err = readOptional(filepath.Join(DefaultsDir, "config.yml")); err != nil {
+			return nil, nil, err
+		}
+		if rosterBuf, err = readOptional(filepath.Join(DefaultsDir, "roster.yml")); err != nil {
+			return nil, nil, err
+		}
+	}
+	var defaults *Config
+	var doc yaml.Node
+	if err := yaml.Unmarshal(configBuf, &doc); err != nil {
+		return nil, nil, err
+	}
+	if doc.Kind != 0 {
+		defaults = &Config{}
+		if err := doc.Decode(defaults); err != nil {
+			return nil, nil, err
+		}
+	}
+	var roster []*Author
+	if strings.TrimSpace(string(rosterBuf)) != "" {
+		var err error
+		if roster, err = ParseTeam(rosterBuf); err != nil {
+			return nil, nil, err
+		}
+	}
+	return defaults, roster, nil
+}
+
+// readOptional reads the file at path, which may not exist.
+func readOptional(path string) ([]byte, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return buf, err
+}
+
+// ApplyDefaults fills in the settings your config leaves unset from the
+// built-in defaults, and puts their teammates and roster beneath the
+// organization roster. Like environment overrides, defaults are never saved.
+func (c *Config) ApplyDefaults(defaults *Config, roster []*Author) {
+	if defaults != nil {
+		v, d := reflect.ValueOf(c).Elem(), reflect.ValueOf(defaults).Elem()
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if f.PkgPath != "" || name == "-" || name == "teammates" {
+				continue
+			}
+			if v.Field(i).IsZero() && !d.Field(i).IsZero() {
+				v.Field(i).Set(d.Field(i))
+				c.defaulted = append(c.defaulted, name)
+			}
+		}
+		roster = append(append([]*Author{}, defaults.Teammates...), roster...)
+	}
+	c.builtin = roster
+	c.Org = append(c.Org, roster...)
+}
@@ -0,0 +1,19 @@
+//go:build pair_defaults
+// +build pair_defaults
+
+package cfg
+
+import _ "embed"
+
+// Edit cfg/defaults/config.yml and cfg/defaults/roster.yml, then build with
+// -tags pair_defaults to ship them inside the binary.
+var (
+	//go:embed defaults/config.yml
+	defaultsConfig []byte
+	//go:embed defaults/roster.yml
+	defaultsRoster []byte
+)
+
+func init() {
+	embeddedConfig, embeddedRoster = defaultsConfig, defaultsRoster
+}
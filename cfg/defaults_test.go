@@ -0,0 +1,56 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaults(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "defaults")
+	defer os.RemoveAll(dir) // clean up
+	defer func(old string) { DefaultsDir = old }(DefaultsDir)
+	DefaultsDir = dir
+
+	if defaults, roster, err := Defaults(); defaults != nil || roster != nil || err != nil {
+		t.Fatalf("expected no defaults without files, got %v, %v, %v", defaults, roster, err)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, "config.yml"), []byte(`session_ttl: 2h
+policy:
+  stale: block
+teammates:
+  - name: Tobias Fünke
+    alias: tf
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "roster.yml"), []byte("bb: Buster Bluth\n"), 0644)
+	defaults, roster, err := Defaults()
+	if err != nil {
+		t.Fatalf("expected no error reading defaults, got %v", err)
+	}
+
+	f, _ := ioutil.TempFile("", "config-*.yml")
+	defer os.Remove(f.Name()) // clean up
+	ioutil.WriteFile(f.Name(), []byte("vcs: git\nsession_ttl: 8h\n"), 0644)
+	config, _ := NewFromFile(f.Name())
+	config.ApplyDefaults(defaults, roster)
+
+	if config.SessionTTL != "8h" {
+		t.Fatalf("expected your own settings to win, got %s", config.SessionTTL)
+	}
+	if config.OnStale() != Block {
+		t.Fatalf("expected the built-in policy to fill in, got %s", config.OnStale())
+	}
+	if config.lookupAlias("tf") == nil || config.lookupAlias("bb") == nil {
+		t.Fatalf("expected the built-in teammates and roster to be known, got %v", config.Roster())
+	}
+
+	if err := config.Save(); err != nil {
+		t.Fatalf("error saving config: %v", err)
+	}
+	if buf, _ := ioutil.ReadFile(f.Name()); strings.Contains(string(buf), "policy") || strings.Contains(string(buf), "tf") {
+		t.Fatalf("expected defaults not to be saved, got\n%s", buf)
+	}
+}
@@ -0,0 +1,94 @@
+package cfg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigDir returns the directory holding pair's configuration, following the
+// XDG base directory spec: $XDG_CONFIG_HOME/pair, or ~/.config/pair.
+func ConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "pair")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", "pair")
+}
+
+// DataDir returns the directory where pair keeps its state, following the XDG
+// base directory spec: $XDG_DATA_HOME/pair, or ~/.local/share/pair.
+func DataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "pair")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", "pair")
+}
+
+// CacheDir returns the directory where pair keeps data it can recreate,
+// following the XDG base directory spec: $XDG_CACHE_HOME/pair, or
+// ~/.cache/pair.
+func CacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "pair")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".cache", "pair")
+}
+
+// DefaultPath returns the location of the config file: $PAIR_CONFIG if set,
+// otherwise config.yml in ConfigDir.
+func DefaultPath() string {
+	if path := os.Getenv("PAIR_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(ConfigDir(), "config.yml")
+}
+
+// Read loads the config from DefaultPath on top of any built-in defaults (see
+// DefaultsDir) and the legacy pairs files, beneath the config of the
+// repository in the working directory, if it has one, and any overrides from
+// the environment (see ApplyEnv). See Effective for where each setting
+// ends up coming from.
+func Read() (*Config, error) {
+	return read(true)
+}
+
+// ReadLocal loads the config like Read, but without the legacy pairs files,
+// which may have to be fetched, for commands which must be quick and work
+// offline. It never runs git.
+func ReadLocal() (*Config, error) {
+	return read(false)
+}
+
+func read(legacy bool) (*Config, error) {
+	defaults, roster, err := Defaults()
+	if err != nil {
+		return nil, fmt.Errorf("built-in defaults: %v", err)
+	}
+	config, err := NewFromFile(DefaultPath())
+	if os.IsNotExist(err) && (defaults != nil || roster != nil) {
+		// A fork with built-in defaults works without any setup.
+		config, err = New(DefaultPath()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	config.ApplyDefaults(defaults, roster)
+	repo, err := ReadRepo()
+	if err != nil {
+		return nil, err
+	}
+	config.ApplyRepo(repo)
+	if legacy {
+		if err := config.ReadLegacyRoster(); err != nil {
+			// Offline, a remote pairs file's cached copy will do.
+			if _, ok := err.(*StaleTeamError); !ok {
+				return nil, fmt.Errorf("pairs file: %v", err)
+			}
+			config.warnings = append(config.warnings, fmt.Errorf("pairs file: %v", err))
+		}
+	}
+	if _, err := config.ApplyEnv(os.Environ()); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
@@ -0,0 +1,111 @@
+package cfg
+
+import "fmt"
+
+// Email sources, for EmailCandidate.
+const (
+	FromRoster   = "roster"
+	FromGitHub   = "github"
+	FromTemplate = "template"
+)
+
+// EmailCandidate is an address authors could commit as, for explaining how
+// ComposeEmail chose one.
+type EmailCandidate struct {
+	Source string // Where the address comes from: roster, github or template
+	Email  string // The address, or "" if there isn't one
+	Chosen bool   // Whether ComposeEmail picks it
+	Reason string // Why it is or isn't picked
+}
+
+// EmailCandidates lists every address considered for authors, marking the one
+// ComposeEmail chooses and why, for debugging attribution.
+func EmailCandidates(template string, authors []*Author) []EmailCandidate {
+	return emailCandidates(template, "+", authors)
+}
+
+func emailCandidates(template, separator string, authors []*Author) []EmailCandidate {
+	var candidates []EmailCandidate
+	derived, err := composeEmail(template, separator, withoutEmails(authors))
+	if len(authors) == 1 {
+		a := authors[0]
+		if a.Email == "" {
+			candidates = append(candidates, EmailCandidate{Source: FromRoster, Reason: "no email in the roster"})
+		} else {
+			candidates = append(candidates, EmailCandidate{Source: FromRoster, Email: a.Email, Chosen: true, Reason: "someone committing alone uses their own email"})
+		}
+		c := EmailCandidate{Source: FromTemplate, Email: derived, Chosen: a.Email == "" && err == nil}
+		switch {
+		case err != nil:
+			c.Reason = err.Error()
+		case c.Chosen:
+			c.Reason = "derived from " + template + " without a roster email"
+		default:
+			c.Reason = "unused, the roster email takes precedence"
+		}
+		return append(candidates, c)
+	}
+
+	for _, a := range authors {
+		if a.Email != "" {
+			candidates = append(candidates, EmailCandidate{Source: FromRoster, Email: a.Email, Reason: fmt.Sprintf("only used when %s commits alone", a.Alias)})
+		}
+	}
+	c := EmailCandidate{Source: FromTemplate, Email: derived, Chosen: err == nil}
+	if err != nil {
+		c.Reason = err.Error()
+	} else {
+		c.Reason = "a pair shares one plus-address derived from " + template
+	}
+	return append(candidates, c)
+}
+
+// EmailCandidates is like the package's EmailCandidates, but follows the
+// trailer and email styles, which can have a pair commit as its primary
+// author.
+func (c *Config) EmailCandidates(template string, authors []*Author) []EmailCandidate {
+	if c.TrailerStyle == CoAuthor && len(authors) > 1 {
+		authors = []*Author{c.Primary(authors)}
+	}
+	if c.EmailStyle != AuthorEmail && c.EmailStyle != GitHubNoreply {
+		return emailCandidates(template, c.separator(), authors)
+	}
+	source := FromRoster
+	if c.EmailStyle == GitHubNoreply {
+		source = FromGitHub
+	}
+	primary := c.Primary(authors)
+	var candidates []EmailCandidate
+	for _, a := range authors {
+		email := c.ownEmail(a)
+		switch {
+		case a == primary && email != "":
+			candidates = append(candidates, EmailCandidate{Source: source, Email: email, Chosen: true, Reason: fmt.Sprintf("email_style %s uses the primary author's own address", c.EmailStyle)})
+		case a == primary:
+			candidates = append(candidates, EmailCandidate{Source: source, Reason: fmt.Sprintf("no %s address for %s, the primary author", source, a.Alias)})
+		case email != "":
+			candidates = append(candidates, EmailCandidate{Source: source, Email: email, Reason: fmt.Sprintf("credited with a trailer, as %s isn't the primary author", a.Alias)})
+		}
+	}
+	derived, err := c.ComposeEmail(template, withoutEmails(authors))
+	candidate := EmailCandidate{Source: FromTemplate, Email: derived, Chosen: c.ownEmail(primary) == "" && err == nil}
+	switch {
+	case err != nil:
+		candidate.Reason = err.Error()
+	case candidate.Chosen:
+		candidate.Reason = "derived from " + template + " without the primary author's address"
+	default:
+		candidate.Reason = "unused, the primary author's address takes precedence"
+	}
+	return append(candidates, candidate)
+}
+
+// withoutEmails copies authors with only their aliases, so ComposeEmail
+// derives an address from the template for them.
+func withoutEmails(authors []*Author) []*Author {
+	stripped := make([]*Author, len(authors))
+	for i, a := range authors {
+		stripped[i] = &Author{Name: a.Name, Alias: a.Alias}
+	}
+	return stripped
+}
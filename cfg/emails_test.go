@@ -0,0 +1,56 @@
+package cfg
+
+import "testing"
+
+func TestEmailCandidates(t *testing.T) {
+	lb, gb := roster.Teammates[0], roster.Teammates[1]
+
+	candidates := EmailCandidates("git@example.com", []*Author{gb})
+	if len(candidates) != 2 || !candidates[0].Chosen || candidates[0].Email != "gb@example.com" {
+		t.Fatalf("expected the roster email to be chosen for someone alone, got %+v", candidates)
+	}
+	if candidates[1].Chosen || candidates[1].Email != "gb@example.com" || candidates[1].Source != FromTemplate {
+		t.Fatalf("expected the template address to be listed but unused, got %+v", candidates[1])
+	}
+
+	candidates = EmailCandidates("git@example.com", []*Author{lb})
+	if candidates[0].Chosen || !candidates[1].Chosen || candidates[1].Email != "lb@example.com" {
+		t.Fatalf("expected the template address without a roster email, got %+v", candidates)
+	}
+
+	candidates = EmailCandidates("git@example.com", []*Author{gb, lb})
+	if len(candidates) != 2 || candidates[0].Chosen || candidates[0].Email != "gb@example.com" {
+		t.Fatalf("expected roster emails to be unused by a pair, got %+v", candidates)
+	}
+	if !candidates[1].Chosen || candidates[1].Email != "git+gb+lb@example.com" {
+		t.Fatalf("expected the pair's plus-address to be chosen, got %+v", candidates[1])
+	}
+
+	candidates = EmailCandidates("nonsense", []*Author{gb, lb})
+	if last := candidates[len(candidates)-1]; last.Chosen || last.Reason != "invalid email address: nonsense" {
+		t.Fatalf("expected an invalid template to be explained, got %+v", last)
+	}
+}
+
+func TestConfigEmailCandidates(t *testing.T) {
+	lb, gb := roster.Teammates[0], roster.Teammates[1]
+	config := &Config{Author: roster.Author, Teammates: roster.Teammates, EmailStyle: AuthorEmail}
+
+	candidates := config.EmailCandidates("git@example.com", []*Author{gb, lb})
+	if len(candidates) != 2 || !candidates[0].Chosen || candidates[0].Email != "gb@example.com" {
+		t.Fatalf("expected the primary author's email to be chosen, got %+v", candidates)
+	}
+	if candidates[1].Chosen || candidates[1].Email != "git+gb+lb@example.com" {
+		t.Fatalf("expected the plus-address to be unused, got %+v", candidates[1])
+	}
+
+	candidates = config.EmailCandidates("git@example.com", []*Author{lb, gb})
+	if candidates[0].Chosen || !candidates[2].Chosen || candidates[2].Email != "git+lb+gb@example.com" {
+		t.Fatalf("expected the plus-address when the primary author has no email, got %+v", candidates)
+	}
+
+	config.EmailStyle = ""
+	if candidates := config.EmailCandidates("git@example.com", []*Author{gb, lb}); !candidates[len(candidates)-1].Chosen {
+		t.Fatalf("expected the plus-address by default, got %+v", candidates)
+	}
+}
@@ -0,0 +1,216 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables which override config keys.
+const EnvPrefix = "PAIR_"
+
+// EnvOverride is a config key set from the environment.
+type EnvOverride struct {
+	Key   string // The key overridden. e.g. author.email
+	Var   string // The variable it came from. e.g. PAIR_AUTHOR_EMAIL
+	Value string // The variable's value
+}
+
+// EnvVar returns the environment variable which overrides key, its path
+// upper-cased with underscores for dots: PAIR_AUTHOR_EMAIL for author.email,
+// or PAIR_GROUPS_FRONTEND for groups.frontend.
+func EnvVar(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+// fieldsSplitEnv are list variables split on whitespace rather than commas,
+// because their items may contain commas.
+var fieldsSplitEnv = map[string]bool{"PAIR_GIT_ARGS": true}
+
+// ApplyEnv overrides settings with the variables in environ, given as
+// KEY=value like os.Environ, so any setting can be changed for a single
+// invocation or in CI without editing files. Lists are comma-separated, and
+// settings which are lists of mappings, such as teammates, can't be
+// overridden. Empty variables are ignored. The overrides are never saved.
+func (c *Config) ApplyEnv(environ []string) ([]EnvOverride, error) {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if parts := strings.SplitN(kv, "=", 2); len(parts) == 2 && parts[1] != "" && strings.HasPrefix(parts[0], EnvPrefix) {
+			env[parts[0]] = parts[1]
+		}
+	}
+	overrides, err := applyEnv(reflect.ValueOf(c).Elem(), "", env)
+	c.env = append(c.env, overrides...)
+	return overrides, err
+}
+
+// applyEnv sets the fields of struct v, whose keys start with path, from env.
+func applyEnv(v reflect.Value, path string, env map[string]string) ([]EnvOverride, error) {
+	var overrides []EnvOverride
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if f.PkgPath != "" || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		key := path + name
+		field := v.Field(i)
+
+		switch {
+		case f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct:
+			value := reflect.New(f.Type.Elem())
+			if !field.IsNil() {
+				value = field
+			}
+			nested, err := applyEnv(value.Elem(), key+".", env)
+			if err != nil {
+				return nil, err
+			}
+			if len(nested) > 0 {
+				field.Set(value)
+			}
+			overrides = append(overrides, nested...)
+		case f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.Slice:
+			prefix := EnvVar(key) + "_"
+			var vars []string
+			for name := range env {
+				if strings.HasPrefix(name, prefix) {
+					vars = append(vars, name)
+				}
+			}
+			sort.Strings(vars)
+			for _, name := range vars {
+				if field.IsNil() {
+					field.Set(reflect.MakeMap(f.Type))
+				}
+				entry := strings.ToLower(strings.TrimPrefix(name, prefix))
+				field.SetMapIndex(reflect.ValueOf(entry), reflect.ValueOf(splitEnv(name, env[name])))
+				overrides = append(overrides, EnvOverride{Key: key + "." + entry, Var: name, Value: env[name]})
+			}
+		default:
+			name := EnvVar(key)
+			value, ok := env[name]
+			if !ok {
+				continue
+			}
+			switch f.Type.Kind() {
+			case reflect.String:
+				field.SetString(value)
+			case reflect.Bool:
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: expected true or false, got %s", name, value)
+				}
+				field.SetBool(b)
+			case reflect.Slice:
+				if f.Type.Elem().Kind() != reflect.String {
+					continue
+				}
+				field.Set(reflect.ValueOf(splitEnv(name, value)))
+			default:
+				continue
+			}
+			overrides = append(overrides, EnvOverride{Key: key, Var: name, Value: value})
+		}
+	}
+	return overrides, nil
+}
+
+// splitEnv splits the list in variable name.
+func splitEnv(name, value string) []string {
+	if fieldsSplitEnv[name] {
+		return strings.Fields(value)
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// withoutOverrides puts back the values from the file, or removes the keys,
+// which were overridden from the environment or filled in from the built-in
+// defaults in node, freshly encoded from c, so saving never writes them to
+// disk.
+func (c *Config) withoutOverrides(node *yaml.Node) {
+	var original *yaml.Node
+	if c.doc != nil && len(c.doc.Content) > 0 {
+		original = c.doc.Content[0]
+	}
+	keys := append(append([]string{}, c.defaulted...), c.fromRepo...)
+	for _, o := range c.env {
+		keys = append(keys, o.Key)
+	}
+	for _, key := range keys {
+		path := strings.Split(key, ".")
+		if value := nodeAt(original, path); value != nil {
+			setNodeAt(node, path, value)
+		} else {
+			removeNodeAt(node, path)
+		}
+	}
+}
+
+// nodeAt returns the value at path in the mapping node, or nil.
+func nodeAt(node *yaml.Node, path []string) *yaml.Node {
+	for _, key := range path {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		node = lookup(node.Content, key)
+	}
+	return node
+}
+
+// setNodeAt sets the value at path in the mapping node, adding any missing
+// keys.
+func setNodeAt(node *yaml.Node, path []string, value *yaml.Node) {
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		next := lookup(node.Content, key)
+		if i == len(path)-1 {
+			if next != nil {
+				*next = *value
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+			}
+			return
+		}
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, next)
+		}
+		node = next
+	}
+}
+
+// removeNodeAt removes the key at path from the mapping node.
+func removeNodeAt(node *yaml.Node, path []string) {
+	parent := nodeAt(node, path[:len(path)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return
+	}
+	key := path[len(path)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == key {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			break
+		}
+	}
+	if len(parent.Content) == 0 && len(path) > 1 {
+		// Don't leave behind an empty mapping, e.g. policy: {}.
+		removeNodeAt(node, path[:len(path)-1])
+	}
+}
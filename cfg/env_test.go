@@ -0,0 +1,88 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEnvVar(t *testing.T) {
+	if name := EnvVar("author.email"); name != "PAIR_AUTHOR_EMAIL" {
+		t.Fatalf("expected PAIR_AUTHOR_EMAIL, got %s", name)
+	}
+	if name := EnvVar("session_ttl"); name != "PAIR_SESSION_TTL" {
+		t.Fatalf("expected PAIR_SESSION_TTL, got %s", name)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	config := &Config{Vcs: "git", Author: &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"}}
+	overrides, err := config.ApplyEnv([]string{
+		"PAIR_AUTHOR_EMAIL=michael@bluth.com",
+		"PAIR_SESSION_TTL=2h",
+		"PAIR_TERMINAL_TITLE=true",
+		"PAIR_LOG=syslog, file:/tmp/pair.log",
+		"PAIR_POLICY_STALE=block",
+		"PAIR_GIT_ARGS=-c protocol.file.allow=always",
+		"PAIR_GROUPS_FRONTEND=lb,gb",
+		"PAIR_VCS=",
+		"PAIR_TEAMMATES=lb",
+		"HOME=/home/mb",
+	})
+	if err != nil {
+		t.Fatalf("expected no error applying the environment, got %v", err)
+	}
+	if config.Author.Email != "michael@bluth.com" || config.Author.Name != "Michael Bluth" {
+		t.Fatalf("expected only the author's email to change, got %+v", config.Author)
+	}
+	if config.SessionTTL != "2h" || !config.TerminalTitle || config.Vcs != "git" {
+		t.Fatalf("expected scalars to be overridden, and empty variables ignored, got %+v", config)
+	}
+	if !reflect.DeepEqual(config.Log, []string{"syslog", "file:/tmp/pair.log"}) {
+		t.Fatalf("expected a comma-separated list, got %q", config.Log)
+	}
+	if config.Policy == nil || config.Policy.Stale != Block {
+		t.Fatalf("expected the policy to be created, got %+v", config.Policy)
+	}
+	if config.Git == nil || !reflect.DeepEqual(config.Git.Args, []string{"-c", "protocol.file.allow=always"}) {
+		t.Fatalf("expected git args split on whitespace, got %+v", config.Git)
+	}
+	if !reflect.DeepEqual(config.Groups["frontend"], []string{"lb", "gb"}) {
+		t.Fatalf("expected a group from the environment, got %v", config.Groups)
+	}
+	if len(overrides) != 7 || overrides[0].Key != "author.email" || overrides[0].Var != "PAIR_AUTHOR_EMAIL" {
+		t.Fatalf("expected every override to be reported, got %+v", overrides)
+	}
+
+	if _, err := config.ApplyEnv([]string{"PAIR_TERMINAL_TITLE=maybe"}); err == nil {
+		t.Fatalf("expected an error for an invalid boolean")
+	}
+}
+
+func TestSaveWithoutEnv(t *testing.T) {
+	f, _ := ioutil.TempFile("", "config-*.yml")
+	defer os.Remove(f.Name()) // clean up
+	original := `vcs: git
+author:
+  name: Michael Bluth
+  alias: mb
+  email: mb@example.com
+teammates: []
+`
+	ioutil.WriteFile(f.Name(), []byte(original), 0644)
+	config, _ := NewFromFile(f.Name())
+	config.ApplyEnv([]string{"PAIR_AUTHOR_EMAIL=michael@bluth.com", "PAIR_LOCALE=fr_CA"})
+	config.AddTeammate(&Author{Name: "Lindsay Bluth", Alias: "lb"})
+	if err := config.Save(); err != nil {
+		t.Fatalf("error saving config: %v", err)
+	}
+
+	reread, _ := NewFromFile(f.Name())
+	if reread.Author.Email != "mb@example.com" || reread.Locale != "" {
+		t.Fatalf("expected overrides not to be saved, got %+v and locale %q", reread.Author, reread.Locale)
+	}
+	if len(reread.Teammates) != 1 {
+		t.Fatalf("expected other changes to be saved, got %v", reread.Teammates)
+	}
+}
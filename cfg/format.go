@@ -0,0 +1,157 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// NameData is what name and branch templates are executed with.
+type NameData struct {
+	Authors []*Author // Everyone in the identity, sorted by alias
+	Names   []string  // Their names, in the same order
+	Aliases []string  // Their aliases, in the same order
+	Count   int       // How many of them there are
+	Ticket  string    // What a branch is for, as given to pair branch. e.g. ONCALL-843; empty in names
+}
+
+// nameFuncs are the functions available to name templates, in addition to
+// the text/template builtins.
+var nameFuncs = template.FuncMap{
+	"join": strings.Join,
+	"first": func(name string) string {
+		if fields := strings.Fields(name); len(fields) > 0 {
+			return fields[0]
+		}
+		return name
+	},
+	"last": func(name string) string {
+		if fields := strings.Fields(name); len(fields) > 0 {
+			return fields[len(fields)-1]
+		}
+		return name
+	},
+	"sub": func(a, b int) int { return a - b },
+}
+
+// FormatName composes the name for the identity of authors using the
+// config's name_template, or ComposeName if there isn't one. For example,
+//
+//	{{range $i, $a := .Authors}}{{if $i}}; {{end}}{{last $a.Name}}, {{first $a.Name}}{{end}}
+//	{{join .Aliases "/"}} pairing
+//	{{if gt .Count 2}}{{index .Names 0}} + {{sub .Count 1}} others{{else}}{{join .Names " and "}}{{end}}
+func (c *Config) FormatName(authors []*Author) (string, error) {
+	if c.NameTemplate == "" {
+		return ComposeName(authors), nil
+	}
+	return formatNames("name_template", c.NameTemplate, authors, "")
+}
+
+// FormatBranch composes the name of a branch made by authors for ticket,
+// what it's for as given to pair branch, using the config's branch_template.
+// It's executed with the same data and functions as name_template, and .Ticket
+// too. A template using .Ticket gives the whole name; otherwise it gives a
+// prefix, followed by a slash and ticket. Without one, the prefix is their
+// aliases joined with "+". For example,
+//
+//	{{join .Aliases "-"}}
+//	pair/{{join .Aliases "+"}}
+//	{{join .Aliases "+"}}-{{.Ticket}}
+func (c *Config) FormatBranch(authors []*Author, ticket string) (string, error) {
+	var branch string
+	switch {
+	case c.BranchTemplate == "":
+		aliases := make([]string, len(authors))
+		for i, a := range authors {
+			aliases[i] = a.Alias
+		}
+		branch = strings.Join(aliases, "+") + "/" + ticket
+	case strings.Contains(c.BranchTemplate, ".Ticket"):
+		name, err := formatNames("branch_template", c.BranchTemplate, authors, ticket)
+		if err != nil {
+			return "", err
+		}
+		branch = strings.Trim(name, "/")
+	default:
+		prefix, err := formatNames("branch_template", c.BranchTemplate, authors, ticket)
+		if err != nil {
+			return "", err
+		}
+		branch = strings.Trim(prefix, "/") + "/" + ticket
+	}
+	if strings.ContainsAny(branch, " ~^:?*[\\") || strings.Contains(branch, "..") {
+		return "", fmt.Errorf("%q can't be a branch name", branch)
+	}
+	return branch, nil
+}
+
+// PairBranches returns those of branches which FormatBranch would name for
+// authors, whatever they're for.
+func (c *Config) PairBranches(authors []*Author, branches []string) ([]string, error) {
+	prefix, suffix, err := c.branchPattern(authors)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, branch := range branches {
+		if len(branch) > len(prefix)+len(suffix) && strings.HasPrefix(branch, prefix) && strings.HasSuffix(branch, suffix) {
+			matches = append(matches, branch)
+		}
+	}
+	return matches, nil
+}
+
+// TicketBranch returns the one of branches which FormatBranch would name for
+// authors and ticket, alone or followed by a hyphen and a description, such
+// as lb+mb/ONCALL-843-fix-login-timeout, or "" if there's none.
+func (c *Config) TicketBranch(authors []*Author, ticket string, branches []string) (string, error) {
+	prefix, suffix, err := c.branchPattern(authors)
+	if err != nil {
+		return "", err
+	}
+	for _, branch := range branches {
+		if !strings.HasPrefix(branch, prefix+ticket) || !strings.HasSuffix(branch, suffix) {
+			continue
+		}
+		rest := strings.TrimSuffix(strings.TrimPrefix(branch, prefix+ticket), suffix)
+		if rest == "" || strings.HasPrefix(rest, "-") {
+			return branch, nil
+		}
+	}
+	return "", nil
+}
+
+// branchPattern returns what comes before and after the ticket in the
+// branches FormatBranch names for authors.
+func (c *Config) branchPattern(authors []*Author) (prefix, suffix string, err error) {
+	const marker = "\x00"
+	pattern, err := c.FormatBranch(authors, marker)
+	if err != nil {
+		return "", "", err
+	}
+	i := strings.Index(pattern, marker)
+	if i < 0 {
+		return "", "", errors.New("branch_template doesn't use .Ticket")
+	}
+	return pattern[:i], pattern[i+len(marker):], nil
+}
+
+// formatNames executes the template called name with text, for authors and
+// ticket.
+func formatNames(name, text string, authors []*Author, ticket string) (string, error) {
+	t, err := template.New(name).Funcs(nameFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	data := NameData{Authors: authors, Count: len(authors), Ticket: ticket}
+	for _, a := range authors {
+		data.Names = append(data.Names, a.Name)
+		data.Aliases = append(data.Aliases, a.Alias)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
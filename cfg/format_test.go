@@ -0,0 +1,108 @@
+package cfg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatName(t *testing.T) {
+	authors := []*Author{
+		&Author{Name: "Lindsay Bluth", Alias: "lb"},
+		&Author{Name: "Michael Bluth", Alias: "mb"},
+		&Author{Name: "Tobias Fünke", Alias: "tf"},
+	}
+	templates := map[string]string{
+		"": "Lindsay Bluth and Michael Bluth and Tobias Fünke",
+		`{{range $i, $a := .Authors}}{{if $i}}; {{end}}{{last $a.Name}}, {{first $a.Name}}{{end}}`: "Bluth, Lindsay; Bluth, Michael; Fünke, Tobias",
+		`{{join .Aliases "/"}} pairing`: "lb/mb/tf pairing",
+		`{{if gt .Count 2}}{{index .Names 0}} + {{sub .Count 1}} others{{else}}{{join .Names " and "}}{{end}}`: "Lindsay Bluth + 2 others",
+	}
+	for tmpl, expected := range templates {
+		config := &Config{NameTemplate: tmpl}
+		name, err := config.FormatName(authors)
+		if err != nil || name != expected {
+			t.Fatalf("expected %q from %q, got %q, %v", expected, tmpl, name, err)
+		}
+	}
+
+	config := &Config{NameTemplate: "{{nope}}"}
+	if _, err := config.FormatName(authors); err == nil {
+		t.Fatalf("expected an error for an invalid template")
+	}
+}
+
+func TestFormatBranch(t *testing.T) {
+	authors := []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb"}, &Author{Name: "Michael Bluth", Alias: "mb"}}
+	templates := map[string]string{
+		"":                                  "lb+mb/ONCALL-843",
+		`{{join .Aliases "-"}}`:             "lb-mb/ONCALL-843",
+		`pair/{{join .Aliases "+"}}/`:       "pair/lb+mb/ONCALL-843",
+		`{{first (index .Names 0)}}`:        "Lindsay/ONCALL-843",
+		`{{.Ticket}}-{{join .Aliases "+"}}`: "ONCALL-843-lb+mb",
+	}
+	for tmpl, expected := range templates {
+		config := &Config{BranchTemplate: tmpl}
+		branch, err := config.FormatBranch(authors, "ONCALL-843")
+		if err != nil || branch != expected {
+			t.Fatalf("expected %q from %q, got %q, %v", expected, tmpl, branch, err)
+		}
+	}
+
+	config := &Config{BranchTemplate: `{{join .Names ", "}}`}
+	if _, err := config.FormatBranch(authors, "ONCALL-843"); err == nil {
+		t.Fatalf("expected an error for a prefix which can't go in a branch name")
+	}
+}
+
+func TestPairBranches(t *testing.T) {
+	authors := []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb"}, &Author{Name: "Michael Bluth", Alias: "mb"}}
+	branches := []string{"main", "lb+mb/ONCALL-843", "lb+mb/", "gb+lb+mb/ONCALL-9", "ONCALL-12-lb+mb", "lb+mb/fix/login"}
+	templates := map[string][]string{
+		"":                                  {"lb+mb/ONCALL-843", "lb+mb/fix/login"},
+		`{{.Ticket}}-{{join .Aliases "+"}}`: {"ONCALL-12-lb+mb"},
+	}
+	for tmpl, expected := range templates {
+		config := &Config{BranchTemplate: tmpl}
+		matches, err := config.PairBranches(authors, branches)
+		if err != nil || !reflect.DeepEqual(matches, expected) {
+			t.Fatalf("expected %v from %q, got %v, %v", expected, tmpl, matches, err)
+		}
+	}
+}
+
+func TestTicketBranch(t *testing.T) {
+	authors := []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb"}, &Author{Name: "Michael Bluth", Alias: "mb"}}
+	branches := []string{"main", "lb+mb/ONCALL-8430", "lb+mb/ONCALL-843-fix-login-timeout", "ONCALL-843-lb+mb"}
+	for tmpl, expected := range map[string]string{
+		"":                                  "lb+mb/ONCALL-843-fix-login-timeout",
+		`{{.Ticket}}-{{join .Aliases "+"}}`: "ONCALL-843-lb+mb",
+		`pair/{{join .Aliases "+"}}`:        "",
+	} {
+		config := &Config{BranchTemplate: tmpl}
+		if branch, err := config.TicketBranch(authors, "ONCALL-843", branches); err != nil || branch != expected {
+			t.Fatalf("expected %q from %q, got %q, %v", expected, tmpl, branch, err)
+		}
+	}
+}
+
+func TestVerifyIdentityWithNameTemplate(t *testing.T) {
+	config := &Config{
+		Author:       &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates:    []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb"}},
+		NameTemplate: `{{join .Aliases "/"}} pairing`,
+	}
+	if err := config.VerifyIdentity("lb/mb pairing", "git+lb+mb@example.com"); err != nil {
+		t.Fatalf("expected a templated identity to be valid, got %v", err)
+	}
+	if err := config.VerifyIdentity("mb pairing", "mb@example.com"); err != nil {
+		t.Fatalf("expected a templated solo identity to be valid, got %v", err)
+	}
+	if err := config.VerifyIdentity("Lindsay Bluth and Michael Bluth", "git+lb+mb@example.com"); err == nil {
+		t.Fatalf("expected a name not matching the template to be invalid")
+	}
+
+	config.EmailSeparator = "-"
+	if err := config.VerifyIdentity("lb/mb pairing", "git-lb-mb@example.com"); err != nil {
+		t.Fatalf("expected aliases to be found with the email_separator, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package cfg
+
+import (
+	"net/mail"
+	"sort"
+	"strings"
+)
+
+// Guess works out who has been pairing from recent commits, newest first,
+// each given as the identities on it: the commit author, such as
+// "Lindsay Bluth and Michael Bluth <git+lb+mb@example.com>", and any
+// co-authors from trailers. It returns whoever made the most of the commits
+// together, preferring the more recent commits on a tie, or nil if none of
+// the commits are by anyone in the roster.
+func (c *Config) Guess(commits [][]string) []*Author {
+	counts := make(map[string]int)
+	var best []*Author
+	bestKey := ""
+	for _, identities := range commits {
+		authors := c.Recognize(identities)
+		if len(authors) == 0 {
+			continue
+		}
+		aliases := make([]string, len(authors))
+		for i, a := range authors {
+			aliases[i] = a.Alias
+		}
+		key := strings.Join(aliases, "+")
+		counts[key]++
+		if best == nil || counts[key] > counts[bestKey] {
+			best, bestKey = authors, key
+		}
+	}
+	return best
+}
+
+// Recognize returns everyone in the roster among identities like
+// "Name <email>", such as the author and co-authors of a commit, sorted by
+// alias.
+func (c *Config) Recognize(identities []string) []*Author {
+	seen := make(map[string]bool)
+	var authors []*Author
+	for _, identity := range identities {
+		for _, a := range c.identityMatches(identity) {
+			if !seen[a.Alias] {
+				seen[a.Alias] = true
+				authors = append(authors, a)
+			}
+		}
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].Alias < authors[j].Alias })
+	return authors
+}
+
+// identityMatches finds who in the roster an identity like "Name <email>"
+// belongs to: by name, including names joined by " and ", by email, or by
+// the aliases in a pair email like git+lb+mb@example.com.
+func (c *Config) identityMatches(identity string) []*Author {
+	addr, err := mail.ParseAddress(identity)
+	if err != nil {
+		return nil
+	}
+
+	var authors []*Author
+	for _, name := range strings.Split(addr.Name, " and ") {
+		if a := c.Lookup(name); a != nil {
+			authors = append(authors, a)
+		}
+	}
+	if len(authors) > 0 {
+		return authors
+	}
+
+	everyone := c.Roster()
+	if c.Author != nil {
+		everyone = append([]*Author{c.Author}, everyone...)
+	}
+	for _, a := range everyone {
+		if a.Email != "" && strings.EqualFold(a.Email, addr.Address) {
+			return []*Author{a}
+		}
+	}
+
+	local := addr.Address[:strings.LastIndex(addr.Address, "@")]
+	if parts := strings.Split(local, c.separator()); len(parts) > 1 {
+		if resolved, err := c.Resolve(parts[1:]); err == nil {
+			return resolved
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package cfg
+
+import "testing"
+
+func TestGuess(t *testing.T) {
+	commits := [][]string{
+		{"Buster Bluth <buster@example.com>"},
+		{"Michael Bluth <mb@example.com>", "Co-authored-by: ignored"},
+		{"Michael Bluth <mb@example.com>", "Lindsay Bluth <lindsay@example.com>"},
+		{"Pair <git+gb+mb@example.com>"},
+		{"Lindsay Bluth and Michael Bluth <git+lb+mb@example.com>"},
+		{"gb <gb@example.com>", "Michael Bluth <mb@example.com>"},
+	}
+
+	authors := roster.Guess(commits)
+	if len(authors) != 2 || authors[0].Alias != "lb" || authors[1].Alias != "mb" {
+		t.Fatalf("expected lb and mb, who made the most commits together, got %v", authors)
+	}
+
+	if authors := roster.Guess(commits[:1]); authors != nil {
+		t.Fatalf("expected no guess without commits by the roster, got %v", authors)
+	}
+	if authors := roster.Guess(commits[3:4]); len(authors) != 2 || authors[0].Alias != "gb" {
+		t.Fatalf("expected the aliases in a pair email to be used, got %v", authors)
+	}
+}
@@ -0,0 +1,445 @@
+package cfg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Resolve looks up each alias among you and your teammates, returning the
+// authors sorted by alias with duplicates removed. The name of a group stands
+// for every alias in it, and an identity like "Guest Contributor
+// <guest@example.com>" stands for a guest who isn't in the roster. Unless
+// c.Strict, an alias that's nobody's stands for whoever MatchAlias finds.
+func (c *Config) Resolve(aliases []string) ([]*Author, error) {
+	seen := make(map[string]bool)
+	var authors []*Author
+	for _, alias := range c.expandGroups(aliases) {
+		a := c.lookupAlias(alias)
+		if guest, ok := ParseGuest(alias); ok {
+			if known := c.lookupAlias(guest.Alias); known != nil && !strings.EqualFold(known.Email, guest.Email) {
+				return nil, fmt.Errorf("guest %s would have the same alias as %s (%s); add them to the roster instead", alias, known.Name, known.Alias)
+			}
+			a = guest
+		}
+		if a == nil && c.Strict {
+			return nil, errors.New("no such username: " + alias)
+		}
+		if a == nil {
+			match, err := MatchAlias(alias, c.aliasNames())
+			if err != nil {
+				return nil, err
+			}
+			a = c.lookupAlias(match)
+		}
+		if seen[a.Alias] {
+			continue
+		}
+		seen[a.Alias] = true
+		authors = append(authors, a)
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].Alias < authors[j].Alias })
+	return authors, nil
+}
+
+func (c *Config) expandGroups(aliases []string) []string {
+	var expanded []string
+	for _, alias := range aliases {
+		if group, ok := c.group(alias); ok && c.lookupAlias(alias) == nil {
+			expanded = append(expanded, group...)
+		} else {
+			expanded = append(expanded, alias)
+		}
+	}
+	return expanded
+}
+
+// HasAlias reports whether alias is someone in the roster, or a group.
+func (c *Config) HasAlias(alias string) bool {
+	_, group := c.group(alias)
+	return group || c.lookupAlias(alias) != nil
+}
+
+func (c *Config) lookupAlias(alias string) *Author {
+	if a := c.lookupLocal(alias); a != nil {
+		return a
+	}
+	if a := c.lookupRepo(alias); a != nil {
+		return a
+	}
+	if a := c.lookupLegacy(alias); a != nil {
+		return a
+	}
+	for _, a := range c.Org {
+		if a.Alias == alias {
+			return a
+		}
+	}
+	return nil
+}
+
+func (c *Config) lookupLocal(alias string) *Author {
+	if c.Author != nil && c.Author.Alias == alias {
+		return c.Author
+	}
+	for _, a := range c.Teammates {
+		if a.Alias == alias {
+			return a
+		}
+	}
+	return nil
+}
+
+// group returns the members of the named group, defined in your config or
+// else in the repository's.
+func (c *Config) group(name string) ([]string, bool) {
+	if group, ok := c.Groups[name]; ok {
+		return group, true
+	}
+	if c.Repo != nil {
+		group, ok := c.Repo.Groups[name]
+		return group, ok
+	}
+	return nil, false
+}
+
+// lookupRepo finds alias among the teammates in the repository's config.
+func (c *Config) lookupRepo(alias string) *Author {
+	for _, a := range c.repoTeammates() {
+		if a.Alias == alias {
+			return a
+		}
+	}
+	return nil
+}
+
+func (c *Config) repoTeammates() []*Author {
+	if c.Repo == nil {
+		return nil
+	}
+	return c.Repo.Teammates
+}
+
+// ParseGuest parses an identity like "Guest Contributor <guest@example.com>"
+// given in place of an alias, for pairing with someone who isn't in the
+// roster. The guest's alias is the user part of their email, e.g. guest.
+func ParseGuest(identity string) (*Author, bool) {
+	if !strings.Contains(identity, "<") {
+		return nil, false
+	}
+	addr, err := mail.ParseAddress(identity)
+	if err != nil || addr.Name == "" {
+		return nil, false
+	}
+	alias := strings.ToLower(addr.Address[:strings.LastIndex(addr.Address, "@")])
+	alias = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return -1
+	}, alias)
+	if alias == "" {
+		return nil, false
+	}
+	return &Author{Name: addr.Name, Alias: alias, Email: addr.Address}, true
+}
+
+// SplitAliases expands aliases given on the command line: comma-separated
+// lists like "lb,gb" are split, and "-" is replaced by the aliases read from
+// stdin, one per line, so pair composes with pickers such as fzf. Anything
+// with a "<" is taken to be a guest and left whole, since names may contain
+// commas.
+func SplitAliases(args []string, stdin io.Reader) ([]string, error) {
+	var aliases []string
+	for _, arg := range args {
+		if arg != "-" {
+			aliases = append(aliases, splitAlias(arg)...)
+			continue
+		}
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			aliases = append(aliases, splitAlias(scanner.Text())...)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("unable to read aliases from stdin: %v", err)
+		}
+	}
+	return aliases, nil
+}
+
+func splitAlias(arg string) []string {
+	if strings.Contains(arg, "<") {
+		return []string{strings.TrimSpace(arg)}
+	}
+	var aliases []string
+	for _, alias := range strings.Split(arg, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// With resolves the authors pairing with you: you plus each of aliases.
+func (c *Config) With(aliases []string) ([]*Author, error) {
+	if c.Author == nil {
+		return nil, errors.New("author can't be nil")
+	}
+	authors, err := c.Resolve(aliases)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range authors {
+		if a == c.Author {
+			return authors, nil
+		}
+	}
+	authors = append(authors, c.Author)
+	sort.Slice(authors, func(i, j int) bool { return authors[i].Alias < authors[j].Alias })
+	return authors, nil
+}
+
+// WithInOrder is like With, but keeps authors in the order of aliases, for
+// when the order says who drives. You come last if you're not among them.
+func (c *Config) WithInOrder(aliases []string) ([]*Author, error) {
+	if c.Author == nil {
+		return nil, errors.New("author can't be nil")
+	}
+	seen := make(map[string]bool)
+	var authors []*Author
+	for _, alias := range aliases {
+		resolved, err := c.Resolve([]string{alias})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resolved {
+			if !seen[a.Alias] {
+				seen[a.Alias] = true
+				authors = append(authors, a)
+			}
+		}
+	}
+	if !seen[c.Author.Alias] {
+		authors = append(authors, c.Author)
+	}
+	return authors, nil
+}
+
+// EmailTemplate returns the address that pair email addresses are derived
+// from: $PAIR_EMAIL if set, otherwise git@ at the domain of your own email.
+func (c *Config) EmailTemplate() (string, error) {
+	if template := os.Getenv("PAIR_EMAIL"); template != "" {
+		return template, nil
+	}
+	if c.Author == nil || !strings.Contains(c.Author.Email, "@") {
+		return "", errors.New("author.email is required to derive pair emails; set $PAIR_EMAIL")
+	}
+	return "git@" + c.Author.Email[strings.LastIndex(c.Author.Email, "@")+1:], nil
+}
+
+// Trailer styles, which decide how the authors of a pair are credited.
+const (
+	// Combined makes the pair the author, with every name and a
+	// plus-addressed email. This is the default.
+	Combined = "combined"
+	// CoAuthor keeps a single author and credits the others with
+	// Co-authored-by trailers, which GitHub and GitLab recognize.
+	CoAuthor = "coauthor"
+)
+
+// Email styles, which decide the address a pair commits as.
+const (
+	// PlusAddress derives one address for the pair from the email template,
+	// listing every alias. This is the default.
+	PlusAddress = "plus"
+	// AuthorEmail uses the primary author's own email, so hosts such as
+	// GitHub attribute commits to someone. The plus-address is only used
+	// when they have no email.
+	AuthorEmail = "author"
+	// GitHubNoreply is like AuthorEmail, but with GitHub noreply addresses
+	// instead of anyone's own email. Those without a GitHub login get
+	// addresses derived from the email template.
+	GitHubNoreply = "github-noreply"
+)
+
+// Attributions, which decide whether a pair commits as the committer as well
+// as the author.
+const (
+	// AttributeBoth makes the pair both the author and the committer. This
+	// is the default.
+	AttributeBoth = "both"
+	// AttributeAuthorOnly makes the pair the author, leaving you, the
+	// machine's owner, as the committer.
+	AttributeAuthorOnly = "author-only"
+)
+
+// Committer returns the committer name and email for commits by a pair when
+// it isn't the pair itself: you, under AttributeAuthorOnly. ok is false
+// otherwise.
+func (c *Config) Committer() (name, email string, ok bool) {
+	if c.Attribution != AttributeAuthorOnly || c.Author == nil {
+		return "", "", false
+	}
+	email = c.ownEmail(c.Author)
+	if email == "" {
+		email = c.Author.Email
+	}
+	return c.Author.Name, email, true
+}
+
+// Identity returns the git author name and email for authors, according to
+// the trailer style. With CoAuthor, that's the primary author (see Primary).
+// With the AuthorEmail style a pair's combined name keeps the primary
+// author's email. Any rotation's lead comes first.
+func (c *Config) Identity(authors []*Author) (string, string, error) {
+	if len(authors) == 0 {
+		return "", "", errors.New("no authors")
+	}
+	authors = c.Lead(authors)
+	if c.TrailerStyle == CoAuthor {
+		authors = []*Author{c.Primary(authors)}
+	}
+	email := ""
+	if c.EmailStyle == AuthorEmail || c.EmailStyle == GitHubNoreply {
+		email = c.ownEmail(c.Primary(authors))
+	}
+	if email == "" {
+		template, err := c.EmailTemplate()
+		if err != nil {
+			return "", "", err
+		}
+		if c.EmailStyle == GitHubNoreply {
+			authors = withoutEmails(authors)
+		}
+		if email, err = c.ComposeEmail(template, authors); err != nil {
+			return "", "", err
+		}
+	}
+	name, err := c.FormatName(authors)
+	if err != nil {
+		return "", "", fmt.Errorf("name_template: %v", err)
+	}
+	return name, email, nil
+}
+
+// UsesTrailers reports whether co-authors are only credited by
+// Co-authored-by trailers, which is so with the CoAuthor trailer style or the
+// AuthorEmail and GitHubNoreply email styles.
+func (c *Config) UsesTrailers() bool {
+	return c.TrailerStyle == CoAuthor || c.EmailStyle == AuthorEmail || c.EmailStyle == GitHubNoreply
+}
+
+// ownEmail returns the email a commits with according to the email style:
+// their GitHub noreply address with GitHubNoreply, otherwise their own.
+func (c *Config) ownEmail(a *Author) string {
+	if c.EmailStyle == GitHubNoreply {
+		return a.NoreplyEmail()
+	}
+	return a.Email
+}
+
+// WithEmails returns authors with an email for each, for crediting them in
+// trailers: their own (see ownEmail), or else one derived from the email
+// template. Authors are copied rather than changed. Without a template,
+// anyone missing an email is left without one.
+func (c *Config) WithEmails(authors []*Author) []*Author {
+	template, err := c.EmailTemplate()
+	filled := make([]*Author, len(authors))
+	for i, a := range authors {
+		email := c.ownEmail(a)
+		if email == "" && err == nil {
+			email, _ = ComposeEmail(template, withoutEmails([]*Author{a}))
+		}
+		filled[i] = a
+		if email != a.Email {
+			copied := *a
+			copied.Email = email
+			filled[i] = &copied
+		}
+	}
+	return filled
+}
+
+// Primary returns the author credited as the author of commits by authors
+// when co-authors are credited with trailers: the rotation's lead if there
+// is one, otherwise you, if you're one of them, otherwise the first of them.
+func (c *Config) Primary(authors []*Author) *Author {
+	if c.Rotation != nil && c.Rotation.Every != "" {
+		if led := leading(authors, c.Rotation.Lead); led[0].Alias == c.Rotation.Lead {
+			return led[0]
+		}
+	}
+	if c.Author != nil {
+		for _, a := range authors {
+			if a.Alias == c.Author.Alias {
+				return a
+			}
+		}
+	}
+	return authors[0]
+}
+
+// ComposeName joins the names of authors with " and ".
+// For example, "Lindsay Bluth and Michael Bluth".
+func ComposeName(authors []*Author) string {
+	names := make([]string, len(authors))
+	for i, a := range authors {
+		names[i] = a.Name
+	}
+	return strings.Join(names, " and ")
+}
+
+// ComposeLabel joins the labels of authors with " and ", for display only.
+// For example, "Lindsay (she/her) and Michael Bluth".
+func ComposeLabel(authors []*Author) string {
+	labels := make([]string, len(authors))
+	for i, a := range authors {
+		labels[i] = a.Label()
+	}
+	return strings.Join(labels, " and ")
+}
+
+// ComposeEmail derives the email for authors from template. A single author
+// keeps their own email; a pair gets a plus-address listing every alias.
+// For example, "git+lb+mb@example.com".
+func ComposeEmail(template string, authors []*Author) (string, error) {
+	return composeEmail(template, "+", authors)
+}
+
+// ComposeEmail is like the package's ComposeEmail, but joins aliases with
+// the config's email_separator.
+func (c *Config) ComposeEmail(template string, authors []*Author) (string, error) {
+	return composeEmail(template, c.separator(), authors)
+}
+
+// separator returns what joins the aliases in a pair's email:
+// email_separator, or "+" if it isn't set.
+func (c *Config) separator() string {
+	if c.EmailSeparator == "" {
+		return "+"
+	}
+	return c.EmailSeparator
+}
+
+func composeEmail(template, separator string, authors []*Author) (string, error) {
+	if len(authors) == 1 && authors[0].Email != "" {
+		return authors[0].Email, nil
+	}
+	parts := strings.Split(template, "@")
+	if len(parts) != 2 {
+		return "", errors.New("invalid email address: " + template)
+	}
+	aliases := make([]string, len(authors))
+	for i, a := range authors {
+		aliases[i] = a.Alias
+	}
+	if len(authors) == 1 {
+		return fmt.Sprintf("%s@%s", aliases[0], parts[1]), nil
+	}
+	return fmt.Sprintf("%s%s%s@%s", parts[0], separator, strings.Join(aliases, separator), parts[1]), nil
+}
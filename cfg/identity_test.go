@@ -0,0 +1,223 @@
+package cfg
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var roster = &Config{
+	Author: &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+	Teammates: []*Author{
+		&Author{Name: "Lindsay Bluth", Alias: "lb"},
+		&Author{Name: "George Bluth", Alias: "gb", Email: "gb@example.com"},
+	},
+}
+
+func TestWith(t *testing.T) {
+	authors, err := roster.With([]string{"lb", "gb", "lb"})
+	if err != nil {
+		t.Fatalf("expected no error resolving teammates, got %v", err)
+	}
+	if name := ComposeName(authors); name != "George Bluth and Lindsay Bluth and Michael Bluth" {
+		t.Fatalf("expected sorted, de-duplicated names including you, got %s", name)
+	}
+
+	authors, err = roster.With([]string{"mb"})
+	if err != nil || len(authors) != 1 {
+		t.Fatalf("expected pairing with yourself to be just you, got %v, %v", authors, err)
+	}
+
+	if _, err := roster.With([]string{"bb"}); err == nil {
+		t.Fatalf("expected an error for an unknown alias")
+	}
+}
+
+func TestResolveGroup(t *testing.T) {
+	config := &Config{
+		Author:    roster.Author,
+		Teammates: roster.Teammates,
+		Groups:    map[string][]string{"family": {"gb", "lb"}},
+	}
+	authors, err := config.Resolve([]string{"family", "lb"})
+	if err != nil {
+		t.Fatalf("expected no error resolving a group, got %v", err)
+	}
+	if len(authors) != 2 || authors[0].Alias != "gb" || authors[1].Alias != "lb" {
+		t.Fatalf("expected the group to expand to its members, got %v", authors)
+	}
+}
+
+func TestSplitAliases(t *testing.T) {
+	stdin := strings.NewReader("gb\n\n  lb  \nBluth, Buster <buster@example.com>\n")
+	aliases, err := SplitAliases([]string{"mb,lb", "-", "tb, ,"}, stdin)
+	if err != nil {
+		t.Fatalf("expected no error splitting aliases, got %v", err)
+	}
+	expected := []string{"mb", "lb", "gb", "lb", "Bluth, Buster <buster@example.com>", "tb"}
+	if !reflect.DeepEqual(aliases, expected) {
+		t.Fatalf("expected %q, got %q", expected, aliases)
+	}
+}
+
+func TestEmailTemplate(t *testing.T) {
+	os.Unsetenv("PAIR_EMAIL")
+	if template, _ := roster.EmailTemplate(); template != "git@example.com" {
+		t.Fatalf("expected template derived from your email, got %s", template)
+	}
+	os.Setenv("PAIR_EMAIL", "pair@bluth.com")
+	defer os.Unsetenv("PAIR_EMAIL")
+	if template, _ := roster.EmailTemplate(); template != "pair@bluth.com" {
+		t.Fatalf("expected $PAIR_EMAIL to take precedence, got %s", template)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	os.Unsetenv("PAIR_EMAIL")
+	authors, _ := roster.With([]string{"lb", "gb"})
+	name, email, err := roster.Identity(authors)
+	if err != nil {
+		t.Fatalf("expected no error composing the identity, got %v", err)
+	}
+	if name != "George Bluth and Lindsay Bluth and Michael Bluth" || email != "git+gb+lb+mb@example.com" {
+		t.Fatalf("expected a combined identity by default, got %s <%s>", name, email)
+	}
+
+	config := &Config{Author: roster.Author, Teammates: roster.Teammates, TrailerStyle: CoAuthor}
+	name, email, err = config.Identity(authors)
+	if err != nil {
+		t.Fatalf("expected no error composing the identity, got %v", err)
+	}
+	if name != "Michael Bluth" || email != "mb@example.com" {
+		t.Fatalf("expected you to stay the author with trailers, got %s <%s>", name, email)
+	}
+
+	authors, _ = config.Resolve([]string{"lb", "gb"})
+	if primary := config.Primary(authors); primary.Alias != "gb" {
+		t.Fatalf("expected the first author to be primary when you aren't pairing, got %s", primary.Alias)
+	}
+
+	config = &Config{Author: roster.Author, Teammates: roster.Teammates, EmailStyle: AuthorEmail}
+	authors, _ = config.With([]string{"gb"})
+	name, email, err = config.Identity(authors)
+	if err != nil {
+		t.Fatalf("expected no error composing the identity, got %v", err)
+	}
+	if name != "George Bluth and Michael Bluth" || email != "mb@example.com" {
+		t.Fatalf("expected the combined name with your own email, got %s <%s>", name, email)
+	}
+	if _, email, _ = config.Identity([]*Author{roster.Teammates[0], roster.Teammates[1]}); email != "git+lb+gb@example.com" {
+		t.Fatalf("expected the plus-address when the primary author has no email, got %s", email)
+	}
+}
+
+func TestGitHubNoreply(t *testing.T) {
+	os.Unsetenv("PAIR_EMAIL")
+	mb := &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com", GitHub: "michael", GitHubID: 1234}
+	lb := &Author{Name: "Lindsay Bluth", Alias: "lb", Email: "lindsay@bluth.com", GitHub: "lindsay"}
+	gb := &Author{Name: "George Bluth", Alias: "gb", Email: "george@bluth.com"}
+	config := &Config{Author: mb, Teammates: []*Author{lb, gb}, EmailStyle: GitHubNoreply}
+
+	if _, email, _ := config.Identity([]*Author{lb, mb}); email != "1234+michael@users.noreply.github.com" {
+		t.Fatalf("expected your noreply address, got %s", email)
+	}
+	if _, email, _ := config.Identity([]*Author{gb}); email != "gb@example.com" {
+		t.Fatalf("expected an address derived from the template without a GitHub login, got %s", email)
+	}
+	authors := config.WithEmails([]*Author{lb, gb})
+	if authors[0].Email != "lindsay@users.noreply.github.com" || authors[1].Email != "gb@example.com" {
+		t.Fatalf("expected noreply addresses for trailers, got %s and %s", authors[0].Email, authors[1].Email)
+	}
+	if err := config.VerifyIdentity("Michael Bluth", "1234+michael@users.noreply.github.com"); err != nil {
+		t.Fatalf("expected your noreply address to be valid alone, got %v", err)
+	}
+}
+
+func TestWithEmails(t *testing.T) {
+	os.Unsetenv("PAIR_EMAIL")
+	lb, gb := roster.Teammates[0], roster.Teammates[1]
+	authors := roster.WithEmails([]*Author{lb, gb})
+	if authors[0].Email != "lb@example.com" || authors[1] != gb {
+		t.Fatalf("expected a derived email only for lb, got %+v", authors)
+	}
+	if lb.Email != "" {
+		t.Fatalf("expected the roster to be left alone, got %s", lb.Email)
+	}
+}
+
+func TestCommitter(t *testing.T) {
+	config := &Config{Author: &Author{Alias: "mb", Name: "Michael Bluth", Email: "mb@example.com", GitHub: "mbluth"}}
+	if _, _, ok := config.Committer(); ok {
+		t.Fatalf("expected the pair to be the committer by default")
+	}
+	config.Attribution = AttributeAuthorOnly
+	if name, email, ok := config.Committer(); !ok || name != "Michael Bluth" || email != "mb@example.com" {
+		t.Fatalf("expected you to be the committer, got %s <%s>, %v", name, email, ok)
+	}
+	config.EmailStyle = GitHubNoreply
+	if _, email, _ := config.Committer(); email != "mbluth@users.noreply.github.com" {
+		t.Fatalf("expected your noreply address, got %s", email)
+	}
+}
+
+func ExampleComposeEmail() {
+	mb := &Author{Alias: "mb", Email: "mb@example.com"}
+	lb := &Author{Alias: "lb"}
+
+	email, _ := ComposeEmail("git@example.com", []*Author{mb})
+	fmt.Println(email)
+	email, _ = ComposeEmail("git@example.com", []*Author{lb})
+	fmt.Println(email)
+	email, _ = ComposeEmail("git@example.com", []*Author{lb, mb})
+	fmt.Println(email)
+
+	// Output:
+	// mb@example.com
+	// lb@example.com
+	// git+lb+mb@example.com
+}
+
+func TestComposeEmailSeparator(t *testing.T) {
+	config := &Config{EmailSeparator: "-"}
+	lb, mb := &Author{Alias: "lb"}, &Author{Alias: "mb", Email: "mb@example.com"}
+	if email, _ := config.ComposeEmail("git@example.com", []*Author{lb, mb}); email != "git-lb-mb@example.com" {
+		t.Fatalf("expected aliases joined with the email_separator, got %s", email)
+	}
+	if email, _ := config.ComposeEmail("git@example.com", []*Author{mb}); email != "mb@example.com" {
+		t.Fatalf("expected someone alone to keep their email, got %s", email)
+	}
+}
+
+func ExampleComposeLabel() {
+	authors := []*Author{
+		&Author{Name: "Lindsay Bluth Fünke", Alias: "lb", DisplayName: "Lindsay", Pronouns: "she/her"},
+		&Author{Name: "Michael Bluth", Alias: "mb"},
+	}
+	fmt.Println(ComposeLabel(authors))
+	fmt.Println(ComposeName(authors))
+	// Output:
+	// Lindsay (she/her) and Michael Bluth
+	// Lindsay Bluth Fünke and Michael Bluth
+}
+
+func TestWithGuest(t *testing.T) {
+	authors, err := roster.With([]string{"lb", "Guest Contributor <Guest+pair@ex.com>"})
+	if err != nil {
+		t.Fatalf("expected a guest to be accepted, got %v", err)
+	}
+	if ComposeName(authors) != "Guest Contributor and Lindsay Bluth and Michael Bluth" {
+		t.Fatalf("expected the guest alongside the roster, got %s", ComposeName(authors))
+	}
+	if authors[0].Alias != "guestpair" || authors[0].Email != "Guest+pair@ex.com" {
+		t.Fatalf("expected the guest's alias from their email, got %v", authors[0])
+	}
+
+	if _, err := roster.With([]string{"Lindsay Imposter <lb@ex.com>"}); err == nil {
+		t.Fatalf("expected an error for a guest sharing an alias with the roster")
+	}
+	if _, ok := ParseGuest("lb"); ok {
+		t.Fatalf("expected an alias not to be a guest")
+	}
+}
@@ -0,0 +1,229 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RemoteTTL is how long the cached copy of a remote pairs file is used before
+// it's fetched again.
+const RemoteTTL = time.Hour
+
+// LegacyPaths returns the locations of the legacy pairs files, YAML maps of
+// usernames to full names: $PAIR_FILE if set, otherwise ~/.pairs. Like $PATH,
+// $PAIR_FILE may list several files, e.g. a personal roster followed by a
+// shared team roster, and any of them may be an http(s) URL.
+func LegacyPaths() []string {
+	if paths := SplitLegacyPaths(os.Getenv("PAIR_FILE")); len(paths) > 0 {
+		return paths
+	}
+	return []string{filepath.Join(os.Getenv("HOME"), ".pairs")}
+}
+
+// portPrefix matches the port and path of a URL split at its port's colon.
+var portPrefix = regexp.MustCompile(`^[0-9]+(/|$)`)
+
+// SplitLegacyPaths splits a list of pairs files like filepath.SplitList,
+// keeping URLs whole despite the colons in them.
+func SplitLegacyPaths(list string) []string {
+	var paths []string
+	for _, path := range filepath.SplitList(list) {
+		if n := len(paths); n > 0 {
+			previous := paths[n-1]
+			scheme := previous == "http" || previous == "https"
+			if scheme && strings.HasPrefix(path, "//") || IsRemote(previous) && strings.Count(previous, "/") == 2 && portPrefix.MatchString(path) {
+				paths[n-1] += ":" + path
+				continue
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// IsRemote reports whether path is the URL of a remote pairs file, which is
+// fetched rather than read and can't be changed.
+func IsRemote(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// LegacyPath returns the location of the first legacy pairs file, which is
+// the one changes are written to.
+func LegacyPath() string {
+	return LegacyPaths()[0]
+}
+
+// Conflict records a username defined differently by two pairs files.
+type Conflict struct {
+	Alias     string // The username. e.g. lb
+	Name      string // The name that won. e.g. Lindsay Bluth
+	Path      string // The file the winning name came from
+	Overrides string // The name that lost. e.g. Lindsay Fünke
+	Previous  string // The file the losing name came from
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s is %s in %s, overriding %s in %s", c.Alias, c.Name, c.Path, c.Overrides, c.Previous)
+}
+
+// ReadLegacyFiles reads and merges the legacy pairs files at paths, later
+// files overriding earlier ones. Files which don't exist are skipped, unless
+// none of them do. A *StaleTeamError is returned alongside the merged files
+// when a remote one came from the cache.
+func ReadLegacyFiles(paths []string) (map[string]string, []Conflict, error) {
+	merged := make(map[string]string)
+	from := make(map[string]string)
+	var conflicts []Conflict
+	var missing, stale error
+	found := false
+	for _, path := range paths {
+		authors, err := ReadLegacy(path)
+		if os.IsNotExist(err) {
+			missing = err
+			continue
+		}
+		if _, ok := err.(*StaleTeamError); ok {
+			stale, err = err, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", path, err)
+		}
+		found = true
+		for alias, name := range authors {
+			if previous, ok := merged[alias]; ok && previous != name {
+				conflicts = append(conflicts, Conflict{Alias: alias, Name: name, Path: path, Overrides: previous, Previous: from[alias]})
+			}
+			merged[alias] = name
+			from[alias] = path
+		}
+	}
+	if !found && missing != nil {
+		return nil, nil, missing
+	}
+	return merged, conflicts, stale
+}
+
+// ReadLegacy reads the legacy pairs file at path, fetching it if it's remote
+// (see FetchLegacy). A git-duet authors file (see ParseDuet) is read as one.
+func ReadLegacy(path string) (map[string]string, error) {
+	if IsRemote(path) {
+		return FetchLegacy(path, false)
+	}
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	authors := make(map[string]string)
+	if duet, err := ParseDuet(buf); err == nil {
+		for _, a := range duet {
+			authors[a.Alias] = a.Name
+		}
+		return authors, nil
+	}
+	if err := yaml.Unmarshal(buf, &authors); err != nil {
+		return nil, err
+	}
+	return authors, nil
+}
+
+// FetchLegacy returns the remote pairs file at url, from the cache if it was
+// fetched within RemoteTTL and refresh isn't set. Otherwise it's downloaded
+// and checked before replacing the cached copy, which is returned along with
+// a *StaleTeamError when the download fails, so pairing keeps working
+// offline.
+func FetchLegacy(url string, refresh bool) (map[string]string, error) {
+	if info, err := os.Stat(TeamCachePath(url)); err == nil && !refresh && time.Since(info.ModTime()) < RemoteTTL {
+		if buf, err := ioutil.ReadFile(TeamCachePath(url)); err == nil {
+			if authors, err := parseRemoteLegacy(buf); err == nil {
+				return authors, nil
+			}
+		}
+	}
+	var authors map[string]string
+	err := fetchCached(url, func(buf []byte) (err error) {
+		authors, err = parseRemoteLegacy(buf)
+		return err
+	})
+	return authors, err
+}
+
+// parseRemoteLegacy parses a remote pairs file, which unlike a local one
+// mustn't be empty, so an error page served in its place isn't taken for a
+// roster with nobody in it.
+func parseRemoteLegacy(buf []byte) (map[string]string, error) {
+	authors := make(map[string]string)
+	if err := yaml.Unmarshal(buf, &authors); err != nil {
+		return nil, fmt.Errorf("expected a map of usernames to full names: %v", err)
+	}
+	if len(authors) == 0 {
+		return nil, errors.New("expected a map of usernames to full names, but it's empty")
+	}
+	for alias, name := range authors {
+		if alias == "" || name == "" {
+			return nil, fmt.Errorf("expected a full name for %q", alias)
+		}
+	}
+	return authors, nil
+}
+
+// WriteLegacy writes authors to the legacy pairs file at path. Comments and
+// ordering in an existing file are preserved.
+func WriteLegacy(path string, authors map[string]string) error {
+	var updated yaml.Node
+	if err := updated.Encode(authors); err != nil {
+		return err
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&updated}}
+	if buf, err := ioutil.ReadFile(path); err == nil {
+		if _, err := ParseDuet(buf); err == nil {
+			return errors.New("it's a git-duet authors file; change it with git-duet, or move to a config with pair migrate")
+		}
+		var old yaml.Node
+		if yaml.Unmarshal(buf, &old) == nil && len(old.Content) > 0 {
+			old.Content[0] = merge(old.Content[0], &updated)
+			doc = &old
+		}
+	}
+	buf, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append([]byte("---\n"), buf...), 0644)
+}
+
+// MergeLegacyTeam fills in authors with everyone from the organization roster
+// at $PAIR_TEAM_URL, if set, without overriding any existing username. Like
+// FetchTeam, it falls back to the cached roster when offline.
+func MergeLegacyTeam(authors map[string]string) error {
+	url := os.Getenv("PAIR_TEAM_URL")
+	if url == "" {
+		return nil
+	}
+	org, err := FetchTeam(url)
+	for _, a := range org {
+		if _, ok := authors[a.Alias]; !ok {
+			authors[a.Alias] = a.Name
+		}
+	}
+	return err
+}
+
+// AddLegacy adds a username to a legacy pairs map, refusing duplicates.
+func AddLegacy(authors map[string]string, alias, name string) error {
+	if err := validateTeammate(&Author{Alias: alias, Name: name}); err != nil {
+		return err
+	}
+	if existing, ok := authors[alias]; ok {
+		return fmt.Errorf("alias %s is already taken by %s", alias, existing)
+	}
+	authors[alias] = name
+	return nil
+}
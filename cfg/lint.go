@@ -0,0 +1,141 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownKey is a key in a config file which pair doesn't recognize, most
+// likely a typo. Unknown keys are otherwise silently ignored.
+type UnknownKey struct {
+	Key        string // Where the key is. e.g. teammates[0].emial
+	Line       int    // Line number in the file
+	Suggestion string // The nearest valid key, if any is close. e.g. email
+}
+
+func (u UnknownKey) String() string {
+	if u.Suggestion == "" {
+		return fmt.Sprintf("line %d: unknown key %s", u.Line, u.Key)
+	}
+	return fmt.Sprintf("line %d: unknown key %s, did you mean %s?", u.Line, u.Key, u.Suggestion)
+}
+
+// Warnings returns the problems reading the config which weren't fatal, such
+// as a remote pairs file coming from the cache, for the caller to print.
+func (c *Config) Warnings() []error {
+	return c.warnings
+}
+
+// UnknownKeys returns the keys in the file the config was read from which
+// don't match any setting, in the order they appear.
+func (c *Config) UnknownKeys() []UnknownKey {
+	if c.doc == nil || len(c.doc.Content) == 0 {
+		return nil
+	}
+	return unknownKeys(c.doc.Content[0], reflect.TypeOf(Config{}), "")
+}
+
+// unknownKeys walks node alongside the type it decodes into, collecting keys
+// which have no matching field.
+func unknownKeys(node *yaml.Node, t reflect.Type, path string) []UnknownKey {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.AliasNode {
+		return nil
+	}
+	var unknown []UnknownKey
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			name := key.Value
+			if path != "" {
+				name = path + "." + key.Value
+			}
+			field, ok := fields[key.Value]
+			if !ok {
+				unknown = append(unknown, UnknownKey{Key: name, Line: key.Line, Suggestion: nearest(key.Value, fields)})
+				continue
+			}
+			unknown = append(unknown, unknownKeys(value, field, name)...)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			unknown = append(unknown, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			unknown = append(unknown, unknownKeys(node.Content[i+1], t.Elem(), path+"."+node.Content[i].Value)...)
+		}
+	}
+	return unknown
+}
+
+// yamlFields maps the YAML keys of struct type t to the types of their
+// fields, leaving out fields which aren't serialized.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if f.PkgPath != "" || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// nearest returns the key in fields closest to key, if it's close enough to
+// be a plausible typo.
+func nearest(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", len(key)/3+1
+	for name := range fields {
+		if d := distance(key, name); d <= bestDistance && (best == "" || d < bestDistance || name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minimum(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minimum(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
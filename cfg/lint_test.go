@@ -0,0 +1,57 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	cases := map[string]string{
+		`vcs: git
+author:
+  name: Michael Bluth
+  alias: mb
+temmates:
+  - name: Lindsay Bluth
+abcd: 1234
+`: "line 5: unknown key temmates, did you mean teammates?\nline 7: unknown key abcd",
+		`teammates:
+  - name: Lindsay Bluth
+    alias: lb
+    emial: lb@example.com
+groups:
+  family: [lb, mb]
+policy:
+  stale: warn
+  stael: block
+`: "line 4: unknown key teammates[0].emial, did you mean email?\nline 9: unknown key policy.stael, did you mean stale?",
+		testConfigs["fullConfig"]: "",
+	}
+	for yml, expected := range cases {
+		f, err := ioutil.TempFile("", "config-*.yml")
+		if err != nil {
+			t.Fatalf("unable to create temporary file: %v", err)
+		}
+		defer os.Remove(f.Name()) // clean up
+		f.WriteString(yml)
+		f.Close()
+
+		config, err := NewFromFile(f.Name())
+		if err != nil {
+			t.Fatalf("expected unknown keys not to fail loading, got %v", err)
+		}
+		var got []string
+		for _, u := range config.UnknownKeys() {
+			got = append(got, u.String())
+		}
+		if strings.Join(got, "\n") != expected {
+			t.Fatalf("expected %q, got %q", expected, got)
+		}
+	}
+
+	if u := New("/tmp/cfg.yml").UnknownKeys(); u != nil {
+		t.Fatalf("expected no unknown keys in a new config, got %v", u)
+	}
+}
@@ -0,0 +1,102 @@
+package cfg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnknownAliasError is returned for an alias which isn't anyone's, nor
+// unambiguously the start of anyone's alias or name.
+type UnknownAliasError struct {
+	Alias       string
+	Suggestions []string // Who it might have meant, best first. e.g. "lb (Lindsay Bluth)"
+}
+
+func (e *UnknownAliasError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return "no such username: " + e.Alias
+	}
+	return fmt.Sprintf("no such username: %s; did you mean %s?", e.Alias, strings.Join(e.Suggestions, ", "))
+}
+
+// MatchAlias works out which of names, a map of aliases to full names,
+// input stands for when it isn't an alias itself: the only alias it starts,
+// ignoring case, or failing that, the only name with a word it starts. So
+// "lind" stands for lb if lb is Lindsay Bluth and nobody else matches. Any
+// other input is an *UnknownAliasError suggesting the closest aliases.
+func MatchAlias(input string, names map[string]string) (string, error) {
+	if _, ok := names[input]; ok {
+		return input, nil
+	}
+	lower := strings.ToLower(input)
+	var byAlias, byName []string
+	for alias, name := range names {
+		if strings.HasPrefix(strings.ToLower(alias), lower) {
+			byAlias = append(byAlias, alias)
+		}
+		for _, word := range strings.Fields(strings.ToLower(name)) {
+			if strings.HasPrefix(word, lower) {
+				byName = append(byName, alias)
+				break
+			}
+		}
+	}
+	switch {
+	case len(byAlias) == 1:
+		return byAlias[0], nil
+	case len(byAlias) == 0 && len(byName) == 1:
+		return byName[0], nil
+	}
+
+	candidates := append(byAlias, byName...)
+	if len(candidates) == 0 {
+		for alias, name := range names {
+			if closeness(lower, alias, name) <= len(input)/3+1 {
+				candidates = append(candidates, alias)
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if da, db := closeness(lower, a, names[a]), closeness(lower, b, names[b]); da != db {
+			return da < db
+		}
+		return a < b
+	})
+	err := &UnknownAliasError{Alias: input}
+	seen := make(map[string]bool)
+	for _, alias := range candidates {
+		if !seen[alias] && len(err.Suggestions) < 3 {
+			seen[alias] = true
+			err.Suggestions = append(err.Suggestions, fmt.Sprintf("%s (%s)", alias, names[alias]))
+		}
+	}
+	return "", err
+}
+
+// closeness returns the Levenshtein distance from input to the nearest of
+// alias and the words of name.
+func closeness(input, alias, name string) int {
+	best := distance(input, strings.ToLower(alias))
+	for _, word := range strings.Fields(strings.ToLower(name)) {
+		if d := distance(input, word); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// aliasNames maps the alias of everyone you could pair with, you and the
+// roster, to their name.
+func (c *Config) aliasNames() map[string]string {
+	names := make(map[string]string)
+	everyone := c.Roster()
+	if c.Author != nil {
+		everyone = append(everyone, c.Author)
+	}
+	for _, a := range everyone {
+		names[a.Alias] = a.Name
+	}
+	return names
+}
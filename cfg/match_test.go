@@ -0,0 +1,54 @@
+package cfg
+
+import "testing"
+
+func TestMatchAlias(t *testing.T) {
+	names := map[string]string{
+		"mb": "Michael Bluth",
+		"lb": "Lindsay Bluth",
+		"gb": "George Bluth",
+		"gm": "George Michael Bluth",
+	}
+	tests := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{"lb", "lb", ""},
+		{"l", "lb", ""},
+		{"lind", "lb", ""},
+		{"LINDSAY", "lb", ""},
+		{"mich", "", "no such username: mich; did you mean gm (George Michael Bluth), mb (Michael Bluth)?"},
+		{"geo", "", "no such username: geo; did you mean gb (George Bluth), gm (George Michael Bluth)?"},
+		{"g", "", "no such username: g; did you mean gb (George Bluth), gm (George Michael Bluth)?"},
+		{"lindsey", "", "no such username: lindsey; did you mean lb (Lindsay Bluth)?"},
+		{"tobias", "", "no such username: tobias"},
+	}
+	for _, test := range tests {
+		alias, err := MatchAlias(test.input, names)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("expected %s to fail with %q, got %s, %v", test.input, test.err, alias, err)
+			}
+			continue
+		}
+		if err != nil || alias != test.expected {
+			t.Errorf("expected %s to match %s, got %s, %v", test.input, test.expected, alias, err)
+		}
+	}
+}
+
+func TestWithPrefix(t *testing.T) {
+	authors, err := roster.With([]string{"lind"})
+	if err != nil {
+		t.Fatalf("expected a unique prefix to resolve, got %v", err)
+	}
+	if name := ComposeName(authors); name != "Lindsay Bluth and Michael Bluth" {
+		t.Fatalf("expected lind to stand for Lindsay Bluth, got %s", name)
+	}
+
+	strict := &Config{Author: roster.Author, Teammates: roster.Teammates, Strict: true}
+	if _, err := strict.With([]string{"lind"}); err == nil {
+		t.Fatalf("expected a prefix to be an error when strict")
+	}
+}
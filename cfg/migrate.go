@@ -0,0 +1,125 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// duetFile is the format of git-duet's authors file. e.g.
+//
+//	authors:
+//	  lb: Lindsay Bluth; lindsay
+//	  mb: Michael Bluth
+//	email:
+//	  domain: example.com
+//	email_addresses:
+//	  mb: michael@bluth.com
+type duetFile struct {
+	Authors        map[string]string       `yaml:"authors"`
+	Email          struct{ Domain string } `yaml:"email"`
+	EmailAddresses map[string]string       `yaml:"email_addresses"`
+	EmailTemplate  string                  `yaml:"email_template"`
+}
+
+// DuetPath returns where git-duet keeps its authors: $GIT_DUET_AUTHORS_FILE,
+// or else ~/.git-authors.
+func DuetPath() string {
+	if path := os.Getenv("GIT_DUET_AUTHORS_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".git-authors")
+}
+
+// ParseDuet parses a git-duet authors file into authors, sorted by alias,
+// working out their emails the way git-duet does: from email_addresses, the
+// email_template, the username after the semicolon at the domain, or else
+// their first initial and last name at the domain.
+func ParseDuet(buf []byte) ([]*Author, error) {
+	var duet duetFile
+	if err := yaml.Unmarshal(buf, &duet); err != nil {
+		return nil, err
+	}
+	if len(duet.Authors) == 0 {
+		return nil, errors.New("expected a git-duet authors file with a map of authors")
+	}
+	var tmpl *template.Template
+	if duet.EmailTemplate != "" {
+		var err error
+		if tmpl, err = template.New("email_template").Parse(duet.EmailTemplate); err != nil {
+			return nil, fmt.Errorf("email_template: %v", err)
+		}
+	}
+	var authors []*Author
+	for initials, entry := range duet.Authors {
+		name, username := splitEntry(entry)
+		a := &Author{Alias: initials, Name: name, Email: duet.EmailAddresses[initials]}
+		if a.Email == "" && tmpl != nil {
+			var b strings.Builder
+			data := struct{ Initials, Name, Username string }{initials, name, username}
+			if err := tmpl.Execute(&b, data); err != nil {
+				return nil, fmt.Errorf("email_template: %v", err)
+			}
+			a.Email = b.String()
+		}
+		if a.Email == "" && duet.Email.Domain != "" {
+			if username == "" {
+				words := strings.Fields(strings.ToLower(name))
+				if len(words) > 1 {
+					username = string([]rune(words[0])[:1]) + "." + strings.Join(words[1:], "")
+				}
+			}
+			if username != "" {
+				a.Email = username + "@" + duet.Email.Domain
+			}
+		}
+		authors = append(authors, a)
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].Alias < authors[j].Alias })
+	return authors, nil
+}
+
+// ParseTogether converts git-together's git config, the keys in its
+// git-together section without the section name (see vcs.ConfigSection),
+// into authors sorted by alias. Entries like authors.lb = "Lindsay Bluth;
+// lindsay" get the username at the domain as their email, unless it's an
+// email already.
+func ParseTogether(section map[string]string) ([]*Author, error) {
+	domain := section["domain"]
+	var authors []*Author
+	for key, entry := range section {
+		if !strings.HasPrefix(key, "authors.") {
+			continue
+		}
+		name, username := splitEntry(entry)
+		a := &Author{Alias: strings.TrimPrefix(key, "authors."), Name: name, Email: username}
+		if username != "" && !strings.Contains(username, "@") {
+			if domain == "" {
+				return nil, fmt.Errorf("git-together.domain is needed for %s's email", a.Alias)
+			}
+			a.Email = username + "@" + domain
+		}
+		authors = append(authors, a)
+	}
+	if len(authors) == 0 {
+		return nil, errors.New("expected git-together.authors in your git config")
+	}
+	sort.Slice(authors, func(i, j int) bool { return authors[i].Alias < authors[j].Alias })
+	return authors, nil
+}
+
+// splitEntry splits an author entry like "Lindsay Bluth; lindsay", as
+// git-duet and git-together have them, into the name and username.
+func splitEntry(entry string) (name, username string) {
+	fields := strings.SplitN(entry, ";", 2)
+	if len(fields) == 2 {
+		username = strings.TrimSpace(fields[1])
+	}
+	return strings.TrimSpace(fields[0]), username
+}
@@ -0,0 +1,91 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const duetAuthors = `---
+authors:
+  lb: Lindsay Bluth; lindsay
+  mb: Michael Bluth
+  gm: George Michael Bluth
+  bb: Buster
+email:
+  domain: example.com
+email_addresses:
+  mb: michael@bluth.com
+`
+
+func TestParseDuet(t *testing.T) {
+	authors, err := ParseDuet([]byte(duetAuthors))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []*Author{
+		{Alias: "bb", Name: "Buster"},
+		{Alias: "gm", Name: "George Michael Bluth", Email: "g.michaelbluth@example.com"},
+		{Alias: "lb", Name: "Lindsay Bluth", Email: "lindsay@example.com"},
+		{Alias: "mb", Name: "Michael Bluth", Email: "michael@bluth.com"},
+	}
+	if !reflect.DeepEqual(authors, expected) {
+		t.Fatalf("expected %v, got %v", expected, authors)
+	}
+
+	templated := "authors:\n  lb: Lindsay Bluth; lindsay\nemail_template: '{{.Username}}+{{.Initials}}@bluth.com'\n"
+	if authors, err := ParseDuet([]byte(templated)); err != nil || authors[0].Email != "lindsay+lb@bluth.com" {
+		t.Fatalf("expected the email_template to be used, got %v, %v", authors, err)
+	}
+	if _, err := ParseDuet([]byte("lb: Lindsay Bluth\n")); err == nil {
+		t.Fatalf("expected a pairs file not to be taken for a git-duet authors file")
+	}
+}
+
+func TestParseTogether(t *testing.T) {
+	authors, err := ParseTogether(map[string]string{
+		"domain":     "example.com",
+		"authors.lb": "Lindsay Bluth; lindsay",
+		"authors.mb": "Michael Bluth; michael@bluth.com",
+		"with":       "lb",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []*Author{
+		{Alias: "lb", Name: "Lindsay Bluth", Email: "lindsay@example.com"},
+		{Alias: "mb", Name: "Michael Bluth", Email: "michael@bluth.com"},
+	}
+	if !reflect.DeepEqual(authors, expected) {
+		t.Fatalf("expected %v, got %v", expected, authors)
+	}
+	if _, err := ParseTogether(map[string]string{"authors.lb": "Lindsay Bluth; lindsay"}); err == nil {
+		t.Fatalf("expected an error for a username without a domain")
+	}
+}
+
+func TestReadDuetRoster(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "migrate")
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, ".git-authors")
+	ioutil.WriteFile(path, []byte(duetAuthors), 0644)
+	defer os.Setenv("PAIR_FILE", os.Getenv("PAIR_FILE"))
+	os.Setenv("PAIR_FILE", path)
+
+	names, err := ReadLegacy(path)
+	if err != nil || names["lb"] != "Lindsay Bluth" {
+		t.Fatalf("expected a git-duet authors file to read as a pairs file, got %v, %v", names, err)
+	}
+	config := &Config{Author: roster.Author}
+	if err := config.ReadLegacyRoster(); err != nil {
+		t.Fatalf("expected no error reading the git-duet authors file, got %v", err)
+	}
+	if a := config.lookupAlias("lb"); a == nil || a.Email != "lindsay@example.com" {
+		t.Fatalf("expected git-duet's email for lb, got %v", a)
+	}
+	if err := WriteLegacy(path, names); err == nil {
+		t.Fatalf("expected an error writing over a git-duet authors file")
+	}
+}
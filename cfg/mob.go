@@ -0,0 +1,46 @@
+package cfg
+
+import "errors"
+
+// Mob describes a mob: three or more people taking turns at the keyboard in
+// a fixed order. Serialized to YAML. It's usually set in RepoFile, so
+// everyone in the mob shares the order.
+type Mob struct {
+	Order []string `yaml:"order"` // Aliases in the order they drive. e.g. [lb, mb, gb]
+}
+
+// MobOrder resolves the authors of a mob in the order they drive, which is
+// the order of aliases, or of mob.order if there are none. Unlike Resolve,
+// the order is kept. You drive last if you're not in it already.
+func (c *Config) MobOrder(aliases []string) ([]*Author, error) {
+	if len(aliases) == 0 && c.Mob != nil {
+		aliases = c.Mob.Order
+	}
+	if len(aliases) == 0 {
+		return nil, errors.New("no mob.order is set; list the aliases in the order they drive")
+	}
+	seen := make(map[string]bool)
+	var authors []*Author
+	add := func(a *Author) {
+		if !seen[a.Alias] {
+			seen[a.Alias] = true
+			authors = append(authors, a)
+		}
+	}
+	for _, alias := range c.expandGroups(aliases) {
+		resolved, err := c.Resolve([]string{alias})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resolved {
+			add(a)
+		}
+	}
+	if c.Author != nil {
+		add(c.Author)
+	}
+	if len(authors) < 2 {
+		return nil, errors.New("a mob needs at least two people")
+	}
+	return authors, nil
+}
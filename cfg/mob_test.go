@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestMobOrder(t *testing.T) {
+	config := &Config{
+		Author:    roster.Author,
+		Teammates: roster.Teammates,
+		Mob:       &Mob{Order: []string{"lb", "gb"}},
+	}
+	authors, err := config.MobOrder([]string{"gb", "mb", "lb", "gb"})
+	if err != nil {
+		t.Fatalf("expected no error resolving a mob, got %v", err)
+	}
+	if label := ComposeLabel(authors); label != "George Bluth and Michael Bluth and Lindsay Bluth" {
+		t.Fatalf("expected the mob in the order given, got %s", label)
+	}
+
+	authors, err = config.MobOrder(nil)
+	if err != nil {
+		t.Fatalf("expected no error resolving mob.order, got %v", err)
+	}
+	if label := ComposeLabel(authors); label != "Lindsay Bluth and George Bluth and Michael Bluth" {
+		t.Fatalf("expected mob.order with you driving last, got %s", label)
+	}
+
+	if _, err := config.MobOrder([]string{"mb"}); err == nil {
+		t.Fatalf("expected an error for a mob of one")
+	}
+	config.Mob = nil
+	if _, err := config.MobOrder(nil); err == nil {
+		t.Fatalf("expected an error without mob.order")
+	}
+}
+
+func TestSaveMobOrder(t *testing.T) {
+	f, _ := ioutil.TempFile("", "pair-*.yml")
+	defer os.Remove(f.Name()) // clean up
+	ioutil.WriteFile(f.Name(), []byte("# Shared with the team.\nvcs: git\nteammates: []\n"), 0644)
+	repo, err := NewFromFile(f.Name())
+	if err != nil {
+		t.Fatalf("error in NewFromFile: %v", err)
+	}
+	repo.Mob = &Mob{Order: []string{"lb", "gb"}}
+	if err := repo.Save(); err != nil {
+		t.Fatalf("error saving config: %v", err)
+	}
+
+	buf, _ := ioutil.ReadFile(f.Name())
+	expected := `# Shared with the team.
+vcs: git
+teammates: []
+mob:
+  order:
+    - lb
+    - gb
+`
+	if string(buf) != expected {
+		t.Fatalf("expected only the mob order to be added, got\n%s", buf)
+	}
+}
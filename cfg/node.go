@@ -0,0 +1,137 @@
+package cfg
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// merge folds the freshly encoded node updated into old, the node read from
+// disk, and returns the result. Anything whose value hasn't changed is kept
+// verbatim, so comments, quoting styles and anchors survive; mappings keep
+// their original key order with new keys appended; sequences of mappings are
+// matched up by their alias key so editing one teammate doesn't disturb the
+// comments on another.
+func merge(old, updated *yaml.Node) *yaml.Node {
+	if sameValue(old, updated) {
+		return old
+	}
+	if old.Kind != updated.Kind {
+		updated.HeadComment, updated.LineComment = old.HeadComment, old.LineComment
+		return updated
+	}
+	switch old.Kind {
+	case yaml.MappingNode:
+		old.Content = mergeMapping(old.Content, updated.Content)
+	case yaml.SequenceNode:
+		old.Content = mergeSequence(old.Content, updated.Content)
+	default:
+		old.Value, old.Tag = updated.Value, updated.Tag
+	}
+	return old
+}
+
+// rebase returns updated, a config read as base and since changed in memory,
+// with whatever it didn't change taken from current, the document on disk
+// now. Mappings are rebased key by key, so saving one setting doesn't undo
+// another changed on disk in the meantime; anything else changed in both
+// places takes the value from updated.
+func rebase(base, current, updated *yaml.Node) *yaml.Node {
+	if sameValue(base, updated) {
+		return current
+	}
+	if base.Kind != yaml.MappingNode || current.Kind != yaml.MappingNode || updated.Kind != yaml.MappingNode {
+		return updated
+	}
+	rebased := *updated
+	rebased.Content = nil
+	for i := 0; i+1 < len(updated.Content); i += 2 {
+		key, value := updated.Content[i], updated.Content[i+1]
+		old, now := lookup(base.Content, key.Value), lookup(current.Content, key.Value)
+		switch {
+		case old != nil && now != nil:
+			value = rebase(old, now, value)
+		case old != nil && sameValue(old, value):
+			// Removed on disk.
+			continue
+		}
+		rebased.Content = append(rebased.Content, key, value)
+	}
+	for i := 0; i+1 < len(current.Content); i += 2 {
+		key := current.Content[i].Value
+		if lookup(base.Content, key) == nil && lookup(updated.Content, key) == nil {
+			// Added on disk.
+			rebased.Content = append(rebased.Content, current.Content[i], current.Content[i+1])
+		}
+	}
+	return &rebased
+}
+
+// mergeMapping merges the key/value pairs of a mapping node. Keys missing
+// from updated are dropped, and new keys without a value, such as author in
+// a repository's config, aren't added.
+func mergeMapping(old, updated []*yaml.Node) []*yaml.Node {
+	var merged []*yaml.Node
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(old); i += 2 {
+		key := old[i].Value
+		value := lookup(updated, key)
+		if value == nil {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, old[i], merge(old[i+1], value))
+	}
+	for i := 0; i+1 < len(updated); i += 2 {
+		if !seen[updated[i].Value] && updated[i+1].Tag != "!!null" {
+			merged = append(merged, updated[i], updated[i+1])
+		}
+	}
+	return merged
+}
+
+// mergeSequence merges the items of a sequence node, matching mappings by
+// their alias and anything else by position.
+func mergeSequence(old, updated []*yaml.Node) []*yaml.Node {
+	byAlias := map[string]*yaml.Node{}
+	for _, item := range old {
+		if alias := lookup(item.Content, "alias"); item.Kind == yaml.MappingNode && alias != nil {
+			byAlias[alias.Value] = item
+		}
+	}
+	merged := make([]*yaml.Node, len(updated))
+	for i, item := range updated {
+		if alias := lookup(item.Content, "alias"); item.Kind == yaml.MappingNode && alias != nil {
+			if prev, ok := byAlias[alias.Value]; ok {
+				merged[i] = merge(prev, item)
+				continue
+			}
+			merged[i] = item
+		} else if i < len(old) {
+			merged[i] = merge(old[i], item)
+		} else {
+			merged[i] = item
+		}
+	}
+	return merged
+}
+
+// lookup finds the value for key in the contents of a mapping node.
+func lookup(content []*yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Value == key {
+			return content[i+1]
+		}
+	}
+	return nil
+}
+
+// sameValue reports whether two nodes decode to the same value, resolving
+// aliases and ignoring presentation.
+func sameValue(a, b *yaml.Node) bool {
+	var va, vb interface{}
+	if a.Decode(&va) != nil || b.Decode(&vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
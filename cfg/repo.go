@@ -0,0 +1,68 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RepoFile is the name of the config file checked into a repository.
+const RepoFile = ".pair.yml"
+
+// FindRepoFile looks for RepoFile in dir and then its parents, the way git
+// looks for .git, so it's found from any subdirectory. The search stops at
+// the root of the repository, the first directory containing .git, so a
+// RepoFile outside the repository is never picked up. It returns the empty
+// string if there is no RepoFile.
+func FindRepoFile(dir string) string {
+	for {
+		path := filepath.Join(dir, RepoFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ReadRepo loads the RepoFile found from the working directory, or returns
+// nil if there isn't one.
+func ReadRepo() (*Config, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	path := FindRepoFile(wd)
+	if path == "" {
+		return nil, nil
+	}
+	return NewFromFile(path)
+}
+
+// Starter returns the contents of a new repository config for vcs, with
+// comments explaining each setting.
+func Starter(vcs string) string {
+	return `# Pairing configuration for this repository. See https://github.com/keeferrourke/pair.
+vcs: ` + vcs + `
+
+# How strictly the hooks enforce the pairing rules: warn or block.
+policy:
+  stale: warn
+  trailers: warn
+
+# How long a pair lasts before it's considered stale, e.g. 8h. Empty never
+# goes stale.
+# session_ttl: 8h
+
+# Everyone who commits to this repository. For example:
+#   - name: Lindsay Bluth
+#     alias: lb
+#     email: lindsay@example.com
+teammates: []
+`
+}
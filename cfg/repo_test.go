@@ -0,0 +1,72 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStarter(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "repo")
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, RepoFile)
+	ioutil.WriteFile(path, []byte(Starter("git")), 0644)
+
+	config, err := NewFromFile(path)
+	if err != nil {
+		t.Fatalf("expected the starter config to parse, got %v", err)
+	}
+	if config.Vcs != "git" || config.OnStale() != Warn || len(config.Teammates) != 0 {
+		t.Fatalf("expected a git config with a warn policy and no teammates, got %+v", config)
+	}
+}
+
+func TestFindRepoFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pair-repo")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	// dir/.pair.yml is outside the repository at dir/repo and must be ignored
+	// until the repository has its own.
+	repo := filepath.Join(dir, "repo")
+	deep := filepath.Join(repo, "cmd", "pair")
+	os.MkdirAll(deep, 0755)
+	os.Mkdir(filepath.Join(repo, ".git"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, RepoFile), []byte("vcs: git\n"), 0644)
+	if path := FindRepoFile(deep); path != "" {
+		t.Fatalf("expected the search to stop at the repository root, got %s", path)
+	}
+
+	ioutil.WriteFile(filepath.Join(repo, RepoFile), []byte("vcs: git\n"), 0644)
+	if path := FindRepoFile(deep); path != filepath.Join(repo, RepoFile) {
+		t.Fatalf("expected %s from a subdirectory, got %s", filepath.Join(repo, RepoFile), path)
+	}
+}
+
+func TestRepoTeammates(t *testing.T) {
+	config := &Config{
+		Author:    roster.Author,
+		Teammates: roster.Teammates,
+	}
+	config.ApplyRepo(&Config{
+		Teammates: []*Author{{Name: "Lindsay Fünke", Alias: "lb"}, {Name: "Buster Bluth", Alias: "bb"}},
+		Groups:    map[string][]string{"brothers": {"bb", "gb"}},
+		Policy:    &Policy{Stale: Block},
+	})
+	authors, err := config.With([]string{"brothers"})
+	if err != nil {
+		t.Fatalf("expected the repository's teammates and groups to resolve, got %v", err)
+	}
+	if ComposeName(authors) != "Buster Bluth and George Bluth and Michael Bluth" {
+		t.Fatalf("expected bb from the repository config, got %s", ComposeName(authors))
+	}
+	if a := config.lookupAlias("lb"); a.Name != "Lindsay Bluth" {
+		t.Fatalf("expected your own teammates to shadow the repository's, got %s", a.Name)
+	}
+	if config.OnStale() != Block {
+		t.Fatalf("expected the repository's policy to win, got %s", config.OnStale())
+	}
+}
@@ -0,0 +1,308 @@
+package cfg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// repoShared are the settings a repository's config can override: how the
+// repository's pairs are named, credited and held to its rules. Anything
+// else, such as which git to run or where tokens are sent, stays yours, so
+// cloning a repository never hands it control of them. Its teammates and
+// groups still count, beneath your own.
+var repoShared = map[string]bool{
+	"vcs":             true,
+	"session_ttl":     true,
+	"idle_timeout":    true,
+	"policy":          true,
+	"mob":             true,
+	"rotation":        true,
+	"name_template":   true,
+	"trailer_style":   true,
+	"email_style":     true,
+	"email_separator": true,
+	"attribution":     true,
+	"branch_template": true,
+	"default_branch":  true,
+	"terminal_title":  true,
+	"strict":          true,
+}
+
+// ApplyRepo makes repo, the config checked into the repository (see
+// FindRepoFile), the config's Repo, and lets the settings it sets override
+// your own, if they're among repoShared. Like environment overrides, they're never saved to your config.
+func (c *Config) ApplyRepo(repo *Config) {
+	c.Repo = repo
+	if repo == nil {
+		return
+	}
+	c.fromRepo = append(c.fromRepo, applyRepo(reflect.ValueOf(c).Elem(), reflect.ValueOf(repo).Elem(), "")...)
+}
+
+// applyRepo sets the fields of struct v, whose keys start with path, which
+// are set in r, returning their keys. Settings such as policy are merged
+// key by key rather than replaced outright.
+func applyRepo(v, r reflect.Value, path string) []string {
+	var keys []string
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if f.PkgPath != "" || name == "-" || path == "" && !repoShared[name] || r.Field(i).IsZero() {
+			continue
+		}
+		if f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
+			field := v.Field(i)
+			if field.IsNil() {
+				field.Set(reflect.New(f.Type.Elem()))
+			} else {
+				// Copy, so the repository's settings don't leak into a
+				// struct shared with something else.
+				copied := reflect.New(f.Type.Elem())
+				copied.Elem().Set(field.Elem())
+				field.Set(copied)
+			}
+			keys = append(keys, applyRepo(field.Elem(), r.Field(i).Elem(), path+name+".")...)
+			continue
+		}
+		v.Field(i).Set(r.Field(i))
+		keys = append(keys, path+name)
+	}
+	return keys
+}
+
+// ReadLegacyRoster adds the people in the legacy pairs files (see
+// LegacyPaths) to the roster, beneath your teammates and the repository's,
+// so aliases from before you had a config keep working. Later files take
+// precedence, and files which don't exist are skipped. A *StaleTeamError
+// means a remote pairs file came from the cache.
+func (c *Config) ReadLegacyRoster() error {
+	c.legacy = nil
+	seen := make(map[string]int)
+	var stale error
+	for _, path := range LegacyPaths() {
+		authors, err := readLegacyAuthors(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if _, ok := err.(*StaleTeamError); ok {
+			stale, err = err, nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		for _, a := range authors {
+			entry := legacyAuthor{Author: a, Path: path}
+			if i, ok := seen[a.Alias]; ok {
+				c.legacy[i] = entry
+				continue
+			}
+			seen[a.Alias] = len(c.legacy)
+			c.legacy = append(c.legacy, entry)
+		}
+	}
+	return stale
+}
+
+// readLegacyAuthors reads the legacy pairs file at path like ReadLegacy, as
+// authors sorted by alias. Only those from a git-duet authors file have
+// emails.
+func readLegacyAuthors(path string) ([]*Author, error) {
+	if !IsRemote(path) {
+		if buf, err := ioutil.ReadFile(path); err == nil {
+			if authors, err := ParseDuet(buf); err == nil {
+				return authors, nil
+			}
+		}
+	}
+	names, err := ReadLegacy(path)
+	if names == nil {
+		return nil, err
+	}
+	aliases := make([]string, 0, len(names))
+	for alias := range names {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	authors := make([]*Author, len(aliases))
+	for i, alias := range aliases {
+		authors[i] = &Author{Name: names[alias], Alias: alias}
+	}
+	return authors, err
+}
+
+// legacyAuthor is someone in a legacy pairs file, and which file.
+type legacyAuthor struct {
+	*Author
+	Path string
+}
+
+func (c *Config) lookupLegacy(alias string) *Author {
+	for _, a := range c.legacy {
+		if a.Alias == alias {
+			return a.Author
+		}
+	}
+	return nil
+}
+
+func (c *Config) legacyTeammates() []*Author {
+	authors := make([]*Author, len(c.legacy))
+	for i, a := range c.legacy {
+		authors[i] = a.Author
+	}
+	return authors
+}
+
+// BuiltinSource is the source of settings from the built-in defaults.
+const BuiltinSource = "built-in defaults"
+
+// Setting is an effective setting and where its value came from.
+type Setting struct {
+	Key    string // Where the setting is. e.g. policy.stale, or teammates.lb
+	Value  string // Its value, lists in flow style. e.g. block, or [syslog, journald]
+	Source string // A file, an environment variable like $PAIR_SESSION_TTL, or BuiltinSource
+}
+
+// Effective returns every setting in effect once your config, the
+// repository's, the legacy pairs files, the built-in defaults and the
+// environment are merged, along with where each came from. Teammates are
+// listed by alias, before anyone they shadow.
+func (c *Config) Effective() ([]Setting, error) {
+	var node yaml.Node
+	if err := node.Encode(c); err != nil {
+		return nil, err
+	}
+	var settings []Setting
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch key {
+		case "teammates":
+			settings = append(settings, c.effectiveTeammates()...)
+		case "groups":
+			// Listed last, since the repository may have groups when you
+			// don't.
+		default:
+			settings = append(settings, c.effective(key, value)...)
+		}
+	}
+	return append(settings, c.effectiveGroups()...), nil
+}
+
+// effective flattens the setting at key into one Setting for each scalar or
+// list in it.
+func (c *Config) effective(key string, value *yaml.Node) []Setting {
+	switch value.Kind {
+	case yaml.MappingNode:
+		var settings []Setting
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			settings = append(settings, c.effective(key+"."+value.Content[i].Value, value.Content[i+1])...)
+		}
+		return settings
+	case yaml.SequenceNode:
+		if len(value.Content) == 0 {
+			return nil
+		}
+		return []Setting{{Key: key, Value: flow(value), Source: c.source(key)}}
+	case yaml.ScalarNode:
+		if value.Tag == "!!null" || value.Value == "" {
+			return nil
+		}
+		return []Setting{{Key: key, Value: value.Value, Source: c.source(key)}}
+	}
+	return nil
+}
+
+// flow formats a sequence of scalars in YAML flow style.
+func flow(node *yaml.Node) string {
+	values := make([]string, len(node.Content))
+	for i, item := range node.Content {
+		values[i] = item.Value
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+// source returns where the setting at key came from.
+func (c *Config) source(key string) string {
+	for _, o := range c.env {
+		if o.Key == key || strings.HasPrefix(key, o.Key+".") {
+			return "$" + o.Var
+		}
+	}
+	for _, k := range c.fromRepo {
+		if k == key || strings.HasPrefix(key, k+".") {
+			return c.Repo.Path
+		}
+	}
+	top := strings.Split(key, ".")[0]
+	for _, k := range c.defaulted {
+		if k == top {
+			return BuiltinSource
+		}
+	}
+	return c.Path
+}
+
+// effectiveTeammates lists everyone who can be paired with by alias, and
+// where they're defined, in the order aliases are looked up.
+func (c *Config) effectiveTeammates() []Setting {
+	var settings []Setting
+	seen := make(map[string]bool)
+	if c.Author != nil {
+		seen[c.Author.Alias] = true // Listed under author
+	}
+	add := func(authors []*Author, source func(i int) string) {
+		for i, a := range authors {
+			if seen[a.Alias] {
+				continue
+			}
+			seen[a.Alias] = true
+			value := a.Name
+			if a.Email != "" {
+				value += " <" + a.Email + ">"
+			}
+			settings = append(settings, Setting{Key: "teammates." + a.Alias, Value: value, Source: source(i)})
+		}
+	}
+	add(c.Teammates, func(int) string { return c.Path })
+	if c.Repo != nil {
+		add(c.Repo.Teammates, func(int) string { return c.Repo.Path })
+	}
+	add(c.legacyTeammates(), func(i int) string { return c.legacy[i].Path })
+	add(c.builtin, func(int) string { return BuiltinSource })
+	return settings
+}
+
+// effectiveGroups lists your groups and the repository's.
+func (c *Config) effectiveGroups() []Setting {
+	var settings []Setting
+	add := func(groups map[string][]string, source string, shadowed func(string) bool) {
+		names := make([]string, 0, len(groups))
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if shadowed(name) {
+				continue
+			}
+			key := "groups." + name
+			from := source
+			if env := c.source(key); strings.HasPrefix(env, "$") {
+				from = env
+			}
+			settings = append(settings, Setting{Key: key, Value: "[" + strings.Join(groups[name], ", ") + "]", Source: from})
+		}
+	}
+	add(c.Groups, c.Path, func(string) bool { return false })
+	if c.Repo != nil {
+		add(c.Repo.Groups, c.Repo.Path, func(name string) bool { _, ok := c.Groups[name]; return ok })
+	}
+	return settings
+}
@@ -0,0 +1,141 @@
+package cfg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyRepo(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "resolve")
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, "config.yml")
+	ioutil.WriteFile(path, []byte("vcs: git\nauthor:\n  name: Michael Bluth\n  alias: mb\n  email: mb@example.com\nsession_ttl: 8h\n"), 0644)
+	config, err := NewFromFile(path)
+	if err != nil {
+		t.Fatalf("expected the config to parse, got %v", err)
+	}
+
+	repo := &Config{
+		Path:       filepath.Join(dir, RepoFile),
+		Author:     &Author{Name: "Buster Bluth", Alias: "bb"},
+		SessionTTL: "2h",
+		Policy:     &Policy{Stale: Block},
+	}
+	config.Policy = &Policy{Trailers: Block}
+	config.ApplyRepo(repo)
+	if config.SessionTTL != "2h" || config.OnStale() != Block || config.OnMissingTrailers() != Block {
+		t.Fatalf("expected the repository's settings to win, merged key by key, got %+v", config)
+	}
+	config.Policy.Trailers = ""
+	if config.Author.Alias != "mb" {
+		t.Fatalf("expected the repository not to change who you are, got %s", config.Author.Alias)
+	}
+
+	if err := config.Save(); err != nil {
+		t.Fatalf("expected no error saving, got %v", err)
+	}
+	buf, _ := ioutil.ReadFile(path)
+	if !strings.Contains(string(buf), "session_ttl: 8h") || strings.Contains(string(buf), "policy") {
+		t.Fatalf("expected the repository's settings not to be saved, got\n%s", buf)
+	}
+}
+
+func TestApplyRepoKeepsPersonalSettings(t *testing.T) {
+	config := &Config{
+		Author:  &Author{Name: "Michael Bluth", Alias: "mb"},
+		Git:     &Git{Path: "/usr/bin/git"},
+		Tracker: &Tracker{Kind: TrackJira, Site: "https://bluth.atlassian.net"},
+	}
+	repo := &Config{
+		Git:           &Git{Path: "./evil.sh"},
+		Tracker:       &Tracker{Kind: TrackJira, Site: "https://attacker.example.com"},
+		Directory:     &Directory{URL: "ldap://attacker.example.com"},
+		TeamRosterURL: "https://attacker.example.com/roster.yml",
+		SlackWebhook:  "https://attacker.example.com/hook",
+		Log:           []string{"file:/tmp/pair.log"},
+		SessionTTL:    "2h",
+	}
+	config.ApplyRepo(repo)
+	if config.Git.Path != "/usr/bin/git" {
+		t.Fatalf("expected the repository not to set git.path, got %s", config.Git.Path)
+	}
+	if config.Tracker.Site != "https://bluth.atlassian.net" {
+		t.Fatalf("expected the repository not to set tracker.site, got %s", config.Tracker.Site)
+	}
+	if config.Directory != nil || config.TeamRosterURL != "" || config.SlackWebhook != "" || len(config.Log) != 0 {
+		t.Fatalf("expected the repository not to set directory, team_url, slack_webhook or log, got %+v", config)
+	}
+	if config.SessionTTL != "2h" {
+		t.Fatalf("expected the repository to set session_ttl, got %q", config.SessionTTL)
+	}
+}
+
+func TestReadLegacyRoster(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "resolve")
+	defer os.RemoveAll(dir) // clean up
+	personal, team := filepath.Join(dir, "pairs"), filepath.Join(dir, "team")
+	ioutil.WriteFile(personal, []byte("lb: Lindsay Fünke\nbb: Buster Bluth\n"), 0644)
+	ioutil.WriteFile(team, []byte("bb: Byron Bluth\n"), 0644)
+	defer os.Setenv("PAIR_FILE", os.Getenv("PAIR_FILE"))
+	os.Setenv("PAIR_FILE", strings.Join([]string{personal, team, filepath.Join(dir, "missing")}, string(filepath.ListSeparator)))
+
+	config := &Config{Author: roster.Author, Teammates: roster.Teammates}
+	if err := config.ReadLegacyRoster(); err != nil {
+		t.Fatalf("expected no error reading the pairs files, got %v", err)
+	}
+	if a := config.lookupAlias("bb"); a == nil || a.Name != "Byron Bluth" {
+		t.Fatalf("expected the later pairs file to win, got %v", a)
+	}
+	if a := config.lookupAlias("lb"); a.Name != "Lindsay Bluth" {
+		t.Fatalf("expected your teammates to shadow the pairs file, got %s", a.Name)
+	}
+}
+
+func TestEffective(t *testing.T) {
+	config := &Config{
+		Path:      "/home/mb/.config/pair/config.yml",
+		Vcs:       "git",
+		Author:    roster.Author,
+		Teammates: roster.Teammates,
+		Log:       []string{"syslog"},
+	}
+	config.ApplyDefaults(&Config{IdleTimeout: "4h"}, nil)
+	config.ApplyRepo(&Config{
+		Path:      "/src/app/.pair.yml",
+		Policy:    &Policy{Stale: Block},
+		Teammates: []*Author{{Name: "Buster Bluth", Alias: "bb"}},
+		Groups:    map[string][]string{"brothers": {"bb", "gb"}},
+	})
+	config.ApplyEnv([]string{"PAIR_SESSION_TTL=2h"})
+
+	settings, err := config.Effective()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	sources := make(map[string]string)
+	for _, s := range settings {
+		sources[s.Key+"="+s.Value] = s.Source
+	}
+	expected := map[string]string{
+		"vcs=git":                    config.Path,
+		"author.alias=mb":            config.Path,
+		"teammates.lb=Lindsay Bluth": config.Path,
+		"teammates.bb=Buster Bluth":  "/src/app/.pair.yml",
+		"log=[syslog]":               config.Path,
+		"idle_timeout=4h":            BuiltinSource,
+		"policy.stale=block":         "/src/app/.pair.yml",
+		"session_ttl=2h":             "$PAIR_SESSION_TTL",
+		"groups.brothers=[bb, gb]":   "/src/app/.pair.yml",
+	}
+	for setting, source := range expected {
+		if sources[setting] != source {
+			t.Fatalf("expected %s from %s, got %q in %+v", setting, source, sources[setting], settings)
+		}
+	}
+	if _, ok := sources["teammates.mb=Michael Bluth"]; ok {
+		t.Fatalf("expected you to be listed under author only")
+	}
+}
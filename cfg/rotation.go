@@ -0,0 +1,65 @@
+package cfg
+
+import "sort"
+
+// Rotation takes turns putting each author of a pair first: first in the
+// author name, and the one whose email a commit has with the author and
+// github-noreply email styles, since that's who hosts credit. Serialized to
+// YAML. Whose turn it is, Lead, is kept in RepoFile so the turns are per
+// repository.
+type Rotation struct {
+	Every string `yaml:"every"`          // When the lead changes: RotatePair or RotateCommit
+	Lead  string `yaml:"lead,omitempty"` // Alias of whoever leads now. Kept up to date by pair
+}
+
+// When the lead of a rotation changes.
+const (
+	// RotatePair hands the lead over each time the pair is set.
+	RotatePair = "pair"
+	// RotateCommit hands the lead over after every commit, from the
+	// post-commit hook.
+	RotateCommit = "commit"
+)
+
+// Rotates reports whether the lead rotates every time the given event, one of
+// RotatePair or RotateCommit, happens.
+func (c *Config) Rotates(every string) bool {
+	return c.Rotation != nil && c.Rotation.Every == every
+}
+
+// Lead returns authors with the rotation's lead first and the others
+// following in turn. Without a rotation, or a lead among authors, they're
+// returned as they are.
+func (c *Config) Lead(authors []*Author) []*Author {
+	if c.Rotation == nil || c.Rotation.Every == "" {
+		return authors
+	}
+	return leading(authors, c.Rotation.Lead)
+}
+
+// NextLead returns the alias of whoever leads authors after the rotation's
+// current lead: the next of them in alias order, or the first if the current
+// lead isn't one of them.
+func (c *Config) NextLead(authors []*Author) string {
+	sorted := append([]*Author{}, authors...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Alias < sorted[j].Alias })
+	if c.Rotation != nil {
+		for i, a := range sorted {
+			if a.Alias == c.Rotation.Lead {
+				return sorted[(i+1)%len(sorted)].Alias
+			}
+		}
+	}
+	return sorted[0].Alias
+}
+
+// leading returns authors starting from the one with alias, the ones before
+// it moved to the end, or authors as they are if none has alias.
+func leading(authors []*Author, alias string) []*Author {
+	for i, a := range authors {
+		if a.Alias == alias {
+			return append(append([]*Author{}, authors[i:]...), authors[:i]...)
+		}
+	}
+	return authors
+}
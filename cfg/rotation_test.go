@@ -0,0 +1,66 @@
+package cfg
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRotation(t *testing.T) {
+	os.Setenv("PAIR_EMAIL", "git@example.com")
+	defer os.Unsetenv("PAIR_EMAIL")
+	config := &Config{
+		Author:    roster.Author,
+		Teammates: roster.Teammates,
+		Rotation:  &Rotation{Every: RotatePair},
+	}
+	authors, _ := config.With([]string{"lb", "gb"})
+
+	var names []string
+	for i := 0; i < 4; i++ {
+		config.Rotation.Lead = config.NextLead(authors)
+		name, email, err := config.Identity(authors)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		names = append(names, name+" <"+email+">")
+	}
+	expected := []string{
+		"George Bluth and Lindsay Bluth and Michael Bluth <git+gb+lb+mb@example.com>",
+		"Lindsay Bluth and Michael Bluth and George Bluth <git+lb+mb+gb@example.com>",
+		"Michael Bluth and George Bluth and Lindsay Bluth <git+mb+gb+lb@example.com>",
+		"George Bluth and Lindsay Bluth and Michael Bluth <git+gb+lb+mb@example.com>",
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected turn %d to be %s, got %s", i+1, expected[i], names[i])
+		}
+	}
+
+	config.Rotation.Lead = "lb"
+	if lead := config.NextLead([]*Author{config.Author}); lead != "mb" {
+		t.Errorf("expected a lead who isn't pairing to be skipped, got %s", lead)
+	}
+	config.EmailStyle = AuthorEmail
+	config.Rotation.Lead = "gb"
+	if _, email, _ := config.Identity(authors); email != "gb@example.com" {
+		t.Errorf("expected the lead's own email, got %s", email)
+	}
+	if err := config.VerifyIdentity("George Bluth and Lindsay Bluth and Michael Bluth", "gb@example.com"); err != nil {
+		t.Errorf("expected an identity led by someone else to be valid, got %v", err)
+	}
+}
+
+func TestVerifyIdentityWithRotatedNameTemplate(t *testing.T) {
+	config := &Config{
+		Author:       &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates:    []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb"}},
+		NameTemplate: `{{join .Aliases "/"}} pairing`,
+		Rotation:     &Rotation{Every: RotateCommit, Lead: "mb"},
+	}
+	if err := config.VerifyIdentity("mb/lb pairing", "git+mb+lb@example.com"); err != nil {
+		t.Fatalf("expected a rotated templated identity to be valid, got %v", err)
+	}
+	if err := config.VerifyIdentity("lb/mb pairing", "git+lb+mb@example.com"); err != nil {
+		t.Fatalf("expected an identity from the previous turn to be valid, got %v", err)
+	}
+}
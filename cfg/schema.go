@@ -0,0 +1,153 @@
+package cfg
+
+import "reflect"
+
+// SchemaID identifies the config schema.
+const SchemaID = "https://github.com/keeferrourke/pair/config.schema.json"
+
+// descriptions are shown by editors when completing or hovering over a key.
+var descriptions = map[string]string{
+	"vcs":             "What VCS are you using? e.g. git",
+	"author":          "Who's machine is this?",
+	"teammates":       "Who's working with you?",
+	"groups":          "Named sets of aliases. e.g. frontend: [lb, gb]",
+	"session_ttl":     "How long until a pair goes stale? e.g. 8h",
+	"idle_timeout":    "How long without commits until you're unpaired? e.g. 4h",
+	"policy":          "How strictly are the rules enforced?",
+	"stale":           "Using a pair past session_ttl or its pair with --for: warn, block commits, or revert to just you.",
+	"trailers":        "Committing while pairing without a Co-authored-by trailer for each co-author: warn or block.",
+	"mob":             "Who takes turns driving? Usually set in the repository's config.",
+	"order":           "Aliases in the order they drive. e.g. [lb, mb, gb]",
+	"rotation":        "Who takes turns leading the author string, and whose email a pair commits as?",
+	"every":           "When the lead changes: pair, each time the pair is set, or commit, after every commit.",
+	"lead":            "Alias of whoever leads now. Kept up to date by pair in the repository's config.",
+	"name_template":   "How are pair names composed? A Go template over .Authors, .Names, .Aliases and .Count.",
+	"email_separator": "What joins the aliases in a pair's email? + if empty. e.g. -",
+	"branch_template": "How are branches named? A Go template like name_template, giving a prefix, or the whole name if it uses .Ticket. e.g. {{join .Aliases \"-\"}}/{{.Ticket}}",
+	"default_branch":  "What do new branches start from? Detected from origin/HEAD, else main or master, if unset. e.g. trunk",
+	"trailer_style":   "How are co-authors credited? combined names and email, or coauthor for Co-authored-by trailers.",
+	"email_style":     "What email does a pair commit as? plus for a plus-address listing every alias, author for the primary author's own, or github-noreply for their GitHub noreply address.",
+	"attribution":     "Is the pair the committer too? both, or author-only to stay the committer yourself.",
+	"terminal_title":  "Should the terminal title show the pair?",
+	"strict":          "Must aliases be typed exactly? Otherwise a unique prefix of an alias or name will do.",
+	"log":             "Where else are changes logged? syslog, journald or file:PATH.",
+	"team_url":        "Where's the organization roster?",
+	"locale":          "Which language are messages in? e.g. fr_CA",
+	"slack_webhook":   "Where do reminders get posted? A Slack incoming webhook URL.",
+	"git":             "How is git run? For systems with several git installs or wrapper scripts.",
+	"path":            "Git binary. e.g. /opt/git/bin/git",
+	"args":            "Arguments passed to git before every command. e.g. [-c, protocol.file.allow=always]",
+	"directory":       "Where are unknown aliases looked up? An LDAP or Active Directory server.",
+	"url":             "Directory server. e.g. ldaps://ldap.example.com",
+	"base_dn":         "Where people are in the directory. e.g. ou=people,dc=example,dc=com",
+	"bind_dn":         "Who to search the directory as, with the password from pair auth set ldap. Anonymous if empty.",
+	"alias_attribute": "Directory attribute holding the alias, uid if empty. e.g. sAMAccountName",
+	"name_attribute":  "Directory attribute holding the name, cn if empty. e.g. displayName",
+	"email_attribute": "Directory attribute holding the email, mail if empty.",
+	"tracker":         "Where are tickets in branch names looked up, to add their titles? Jira or GitHub Issues, with the token from pair auth.",
+	"kind":            "Which issue tracker: jira or github.",
+	"site":            "Jira site, or GitHub Enterprise API root. e.g. https://example.atlassian.net",
+	"repo":            "GitHub repository whose issues are tickets, the origin remote's if empty. e.g. bluth/banana-stand",
+	"name":            "Author name. e.g. Lindsay Bluth",
+	"alias":           "Nickname. e.g. lb",
+	"email":           "Email address. e.g. lindsay@example.com",
+	"status":          "Availability. e.g. away",
+	"until":           "Last day of the status. e.g. 2026-11-01",
+	"timezone":        "Where are they? An IANA time zone. e.g. Europe/Berlin",
+	"hours":           "Working hours, local. e.g. 9-17",
+	"display_name":    "Preferred name. e.g. Lindsay",
+	"github":          "GitHub login, for noreply emails. e.g. lindsay",
+	"github_id":       "GitHub's numeric ID for the login, filled in by pair teammates sync.",
+	"pronouns":        "e.g. she/her",
+	"signingkey":      "GPG key id or SSH public key.",
+}
+
+// constraints narrow the values allowed for some keys.
+var constraints = map[string]map[string]interface{}{
+	"stale":         {"enum": []string{Warn, Block, Revert}},
+	"trailers":      {"enum": []string{Warn, Block}},
+	"trailer_style": {"enum": []string{Combined, CoAuthor}},
+	"email_style":   {"enum": []string{PlusAddress, AuthorEmail, GitHubNoreply}},
+	"attribution":   {"enum": []string{AttributeBoth, AttributeAuthorOnly}},
+	"every":         {"enum": []string{RotatePair, RotateCommit}},
+	"kind":          {"enum": []string{TrackJira, TrackGitHub}},
+	"until":         {"pattern": `^\d{4}-\d{2}-\d{2}$`},
+	"hours":         {"pattern": `^\d{1,2}-\d{1,2}$`},
+	"session_ttl":   {"pattern": `^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`},
+	"idle_timeout":  {"pattern": `^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+$`},
+}
+
+// Schema returns a JSON Schema describing the config file and RepoFile, for
+// editors which validate and complete YAML, such as yaml-language-server.
+// Like UnknownKeys, it's derived from the Config type, so it can't drift.
+func Schema() map[string]interface{} {
+	schema := schemaFor(reflect.TypeOf(Config{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["$id"] = SchemaID
+	schema["title"] = "pair config"
+	return schema
+}
+
+// RosterSchema returns a JSON Schema describing an organization roster, as
+// accepted by ParseTeam: a teammates list, or a legacy map of usernames to
+// full names.
+func RosterSchema() map[string]interface{} {
+	author := schemaFor(reflect.TypeOf(Author{}))
+	return map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   "pair roster",
+		"oneOf": []interface{}{
+			map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"teammates": map[string]interface{}{"type": "array", "items": author}},
+				"required":   []string{"teammates"},
+			},
+			map[string]interface{}{
+				"type":                 "object",
+				"description":          "Usernames mapped to full names. e.g. lb: Lindsay Bluth",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+}
+
+// schemaFor builds the schema for values of type t.
+func schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := yamlFields(t)
+		properties := make(map[string]interface{})
+		for name, field := range fields {
+			property := schemaFor(field)
+			if d, ok := descriptions[name]; ok {
+				property["description"] = d
+			}
+			for k, v := range constraints[name] {
+				property[k] = v
+			}
+			properties[name] = property
+		}
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if t == reflect.TypeOf(Author{}) {
+			schema["required"] = []string{"name", "alias"}
+		}
+		return schema
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
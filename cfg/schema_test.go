@@ -0,0 +1,38 @@
+package cfg
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	buf, err := json.Marshal(Schema())
+	if err != nil {
+		t.Fatalf("expected the schema to encode, got %v", err)
+	}
+	schema := string(buf)
+	for _, expected := range []string{
+		`"$id":"` + SchemaID + `"`,
+		`"teammates":{"description":"Who's working with you?","items":{"additionalProperties":false`,
+		`"stale":{"description":"Using a pair past session_ttl or its pair with --for: warn, block commits, or revert to just you.","enum":["warn","block","revert"]`,
+		`"required":["name","alias"]`,
+	} {
+		if !strings.Contains(schema, expected) {
+			t.Fatalf("expected the schema to contain %s, got %s", expected, schema)
+		}
+	}
+	if strings.Contains(schema, `"Path"`) || strings.Contains(schema, `"doc"`) {
+		t.Fatalf("expected fields which aren't serialized to be left out, got %s", schema)
+	}
+}
+
+func TestRosterSchema(t *testing.T) {
+	buf, err := json.Marshal(RosterSchema())
+	if err != nil {
+		t.Fatalf("expected the roster schema to encode, got %v", err)
+	}
+	if !strings.Contains(string(buf), `"oneOf"`) {
+		t.Fatalf("expected both roster formats, got %s", buf)
+	}
+}
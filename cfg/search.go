@@ -0,0 +1,33 @@
+package cfg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Search returns the authors whose name, display name, alias or email
+// contains pattern, ignoring case. When regex is set, pattern is a regular
+// expression instead, still matched case-insensitively.
+func Search(authors []*Author, pattern string, regex bool) ([]*Author, error) {
+	match := func(field string) bool {
+		return strings.Contains(strings.ToLower(field), strings.ToLower(pattern))
+	}
+	if regex {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, err
+		}
+		match = re.MatchString
+	}
+
+	var found []*Author
+	for _, a := range authors {
+		for _, field := range []string{a.Alias, a.Name, a.DisplayName, a.Email} {
+			if field != "" && match(field) {
+				found = append(found, a)
+				break
+			}
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,22 @@
+package cfg
+
+import "testing"
+
+func TestSearch(t *testing.T) {
+	authors := []*Author{
+		&Author{Name: "Lindsay Bluth", Alias: "lb", Email: "lindsay@example.com"},
+		&Author{Name: "Tobias Fünke", Alias: "tf"},
+		&Author{Name: "Buster Bluth", Alias: "bb", DisplayName: "Buster"},
+	}
+	found, _ := Search(authors, "BL", false)
+	if len(found) != 2 || found[0].Alias != "lb" || found[1].Alias != "bb" {
+		t.Fatalf("expected a case-insensitive substring match on names, got %v", found)
+	}
+	found, _ = Search(authors, "^t", true)
+	if len(found) != 1 || found[0].Alias != "tf" {
+		t.Fatalf("expected a regex match, got %v", found)
+	}
+	if _, err := Search(authors, "(", true); err == nil {
+		t.Fatalf("expected an error for an invalid regex")
+	}
+}
@@ -0,0 +1,100 @@
+package cfg
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// snippetPrefix marks a shared snippet and the version of its encoding.
+const snippetPrefix = "pair1:"
+
+// Snippet is a compact, copy-pasteable bundle of roster entries, optionally
+// forming a group.
+type Snippet struct {
+	Group   string    `json:"group,omitempty"`
+	Authors []*Author `json:"authors"`
+}
+
+// Encode returns the snippet as a single line of text, such as
+// "pair1:eyJhdXRob3JzIjpb...". Availability is left out since it's only
+// meaningful in the sharer's roster.
+func (s *Snippet) Encode() (string, error) {
+	shared := Snippet{Group: s.Group}
+	for _, a := range s.Authors {
+		copy := *a
+		copy.Status, copy.Until = "", ""
+		shared.Authors = append(shared.Authors, &copy)
+	}
+	buf, err := json.Marshal(shared)
+	if err != nil {
+		return "", err
+	}
+	return snippetPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// DecodeSnippet parses text produced by Snippet.Encode. Surrounding
+// whitespace, and anything before the prefix such as a URL, is ignored.
+func DecodeSnippet(text string) (*Snippet, error) {
+	text = strings.TrimSpace(text)
+	i := strings.Index(text, snippetPrefix)
+	if i < 0 {
+		return nil, errors.New("not a pair snippet; expected it to start with " + snippetPrefix)
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(text[i+len(snippetPrefix):])
+	if err != nil {
+		return nil, errors.New("pair snippet is damaged: " + err.Error())
+	}
+	var s Snippet
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return nil, errors.New("pair snippet is damaged: " + err.Error())
+	}
+	if len(s.Authors) == 0 {
+		return nil, errors.New("pair snippet has nobody in it")
+	}
+	return &s, nil
+}
+
+// Import adds the authors in s to the teammates, skipping anyone already in
+// the roster exactly as shared, and adds its group if it has one. It returns
+// the authors it added; conflicting entries are reported as errors.
+func (c *Config) Import(s *Snippet) ([]*Author, []error) {
+	var added []*Author
+	var problems []error
+	var members []string
+	for _, a := range s.Authors {
+		if existing := c.lookupLocal(a.Alias); existing != nil && existing.Name == a.Name && existing.Email == a.Email {
+			members = append(members, a.Alias)
+			continue
+		}
+		if err := c.AddTeammate(a); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		added = append(added, a)
+		members = append(members, a.Alias)
+	}
+	if s.Group != "" {
+		if c.Groups == nil {
+			c.Groups = make(map[string][]string)
+		}
+		group := c.Groups[s.Group]
+		for _, alias := range members {
+			if !contains(group, alias) {
+				group = append(group, alias)
+			}
+		}
+		c.Groups[s.Group] = group
+	}
+	return added, problems
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
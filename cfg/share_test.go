@@ -0,0 +1,56 @@
+package cfg
+
+import "testing"
+
+func TestSnippetRoundTrip(t *testing.T) {
+	s := &Snippet{
+		Group: "family",
+		Authors: []*Author{
+			&Author{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com", Status: Away},
+			&Author{Name: "George Bluth", Alias: "gb", Pronouns: "he/him"},
+		},
+	}
+	text, err := s.Encode()
+	if err != nil {
+		t.Fatalf("expected no error encoding, got %v", err)
+	}
+	decoded, err := DecodeSnippet("https://example.com/share#" + text + "\n")
+	if err != nil {
+		t.Fatalf("expected no error decoding, got %v", err)
+	}
+	if decoded.Group != "family" || len(decoded.Authors) != 2 || decoded.Authors[1].Pronouns != "he/him" {
+		t.Fatalf("expected the snippet to survive a round trip, got %+v", decoded)
+	}
+	if decoded.Authors[0].Status != "" {
+		t.Fatalf("expected availability to be left out")
+	}
+	for _, bad := range []string{"", "lb: Lindsay Bluth", "pair1:!!!", "pair1:e30"} {
+		if _, err := DecodeSnippet(bad); err == nil {
+			t.Fatalf("expected %q not to decode", bad)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	config := &Config{
+		Author:    &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates: []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb"}},
+	}
+	added, problems := config.Import(&Snippet{
+		Group: "family",
+		Authors: []*Author{
+			&Author{Name: "Lindsay Bluth", Alias: "lb"},
+			&Author{Name: "George Bluth", Alias: "gb"},
+			&Author{Name: "Gob Bluth", Alias: "mb"},
+		},
+	})
+	if len(added) != 1 || added[0].Alias != "gb" {
+		t.Fatalf("expected only gb to be added, got %v", added)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected the conflicting mb to be reported, got %v", problems)
+	}
+	if members := config.Groups["family"]; len(members) != 2 || members[0] != "lb" || members[1] != "gb" {
+		t.Fatalf("expected the group to hold lb and gb, got %v", members)
+	}
+}
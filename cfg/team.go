@@ -0,0 +1,208 @@
+package cfg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/internal/httpx"
+	"gopkg.in/yaml.v3"
+)
+
+// TeamURL returns the location of the organization roster: $PAIR_TEAM_URL if
+// set, otherwise team_url from the config. An empty URL means there is none.
+func (c *Config) TeamURL() string {
+	if url := os.Getenv("PAIR_TEAM_URL"); url != "" {
+		return url
+	}
+	return c.TeamRosterURL
+}
+
+// LoadTeam fetches the organization roster, if there is one, making its
+// members available beneath your own teammates. Teammates without a status of
+// their own pick up the one in the organization roster. A *StaleTeamError
+// means the members came from the cache.
+func (c *Config) LoadTeam() error {
+	url := c.TeamURL()
+	if url == "" {
+		return nil
+	}
+	org, err := FetchTeam(url)
+	c.Org = org
+	for _, a := range c.builtin {
+		if !hasAlias(org, a.Alias) {
+			c.Org = append(c.Org, a)
+		}
+	}
+	for _, a := range org {
+		if local := c.lookupLocal(a.Alias); local != nil && local.Status == "" {
+			local.Status, local.Until = a.Status, a.Until
+		}
+	}
+	return err
+}
+
+// Roster returns your teammates, then the teammates in the repository's
+// config and then the members of the organization roster, leaving out anyone
+// whose alias is already taken.
+func (c *Config) Roster() []*Author {
+	roster := append([]*Author{}, c.Teammates...)
+	for _, a := range c.repoTeammates() {
+		if c.lookupLocal(a.Alias) == nil {
+			roster = append(roster, a)
+		}
+	}
+	for _, a := range c.legacyTeammates() {
+		if c.lookupLocal(a.Alias) == nil && c.lookupRepo(a.Alias) == nil {
+			roster = append(roster, a)
+		}
+	}
+	for _, a := range c.Org {
+		if c.lookupLocal(a.Alias) == nil && c.lookupRepo(a.Alias) == nil && c.lookupLegacy(a.Alias) == nil {
+			roster = append(roster, a)
+		}
+	}
+	return roster
+}
+
+// SyncTeammates adds authors, such as the members of a GitHub organization,
+// to your teammates. Teammates you already have keep their details, except
+// for a missing name, email or GitHub login and ID, and anyone who'd
+// duplicate your alias or someone's email is skipped. Only your GitHub login
+// and ID are filled in. It returns how many teammates were added and how many
+// filled in.
+func (c *Config) SyncTeammates(authors []*Author) (added, updated int) {
+	for _, a := range authors {
+		local := c.lookupLocal(a.Alias)
+		if local == nil {
+			if c.AddTeammate(a) == nil {
+				added++
+			}
+			continue
+		}
+		before := *local
+		if local != c.Author {
+			if local.Name == "" {
+				local.Name = a.Name
+			}
+			if local.Email == "" {
+				local.Email = a.Email
+			}
+		}
+		if local.GitHub == "" {
+			local.GitHub = a.GitHub
+		}
+		if local.GitHubID == 0 && strings.EqualFold(local.GitHub, a.GitHub) {
+			local.GitHubID = a.GitHubID
+		}
+		if *local != before {
+			updated++
+		}
+	}
+	return added, updated
+}
+
+func hasAlias(authors []*Author, alias string) bool {
+	for _, a := range authors {
+		if a.Alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// StaleTeamError is returned alongside the cached copy of an organization
+// roster or remote pairs file when the original couldn't be fetched.
+type StaleTeamError struct {
+	URL     string    // Where the roster should have come from
+	Fetched time.Time // When the cached copy was last fetched
+	Err     error     // Why fetching failed
+}
+
+func (e *StaleTeamError) Error() string {
+	return fmt.Sprintf("using the copy of %s cached %s: %v", e.URL, e.Fetched.Format("2006-01-02 15:04"), e.Err)
+}
+
+// TeamCachePath returns where the last copy of the roster or pairs file at
+// url is kept.
+func TeamCachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(CacheDir(), "team", hex.EncodeToString(sum[:8])+".yml")
+}
+
+// FetchTeam downloads the organization roster at url, caching it. When it
+// can't be downloaded, the cached copy is returned along with a
+// *StaleTeamError so pairing keeps working offline.
+func FetchTeam(url string) ([]*Author, error) {
+	var team []*Author
+	err := fetchCached(url, func(buf []byte) (err error) {
+		team, err = ParseTeam(buf)
+		return err
+	})
+	return team, err
+}
+
+// fetchCached downloads url into its cache (see TeamCachePath), once parse
+// accepts it. When it can't be downloaded, the cached copy is parsed instead
+// and a *StaleTeamError returned.
+func fetchCached(url string, parse func([]byte) error) error {
+	cache := TeamCachePath(url)
+	buf, err := download(url)
+	if err == nil {
+		if err := parse(buf); err != nil {
+			return err
+		}
+		if os.MkdirAll(filepath.Dir(cache), 0755) == nil {
+			ioutil.WriteFile(cache, buf, 0644)
+		}
+		return nil
+	}
+
+	info, statErr := os.Stat(cache)
+	if statErr != nil {
+		return err
+	}
+	cached, readErr := ioutil.ReadFile(cache)
+	if readErr != nil || parse(cached) != nil {
+		return err
+	}
+	return &StaleTeamError{URL: url, Fetched: info.ModTime(), Err: err}
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := httpx.New(10 * time.Second).Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// ParseTeam parses an organization roster. It's either a config-style
+// document with a teammates list, or a legacy map of usernames to full names.
+func ParseTeam(buf []byte) ([]*Author, error) {
+	var doc struct {
+		Teammates []*Author `yaml:"teammates"`
+	}
+	if err := yaml.Unmarshal(buf, &doc); err == nil && len(doc.Teammates) > 0 {
+		return doc.Teammates, nil
+	}
+	var legacy map[string]string
+	if err := yaml.Unmarshal(buf, &legacy); err != nil {
+		return nil, fmt.Errorf("team roster is neither a list of teammates nor a map of usernames: %v", err)
+	}
+	var team []*Author
+	for alias, name := range legacy {
+		team = append(team, &Author{Alias: alias, Name: name})
+	}
+	return team, nil
+}
@@ -0,0 +1,149 @@
+package cfg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTeam(t *testing.T) {
+	team, err := ParseTeam([]byte("teammates:\n  - name: Tobias Fünke\n    alias: tf\n    email: tf@example.com\n"))
+	if err != nil || len(team) != 1 || team[0].Email != "tf@example.com" {
+		t.Fatalf("expected a teammates list to parse, got %v, %v", team, err)
+	}
+	team, err = ParseTeam([]byte("---\ntf: Tobias Fünke\n"))
+	if err != nil || len(team) != 1 || team[0].Name != "Tobias Fünke" {
+		t.Fatalf("expected a legacy map to parse, got %v, %v", team, err)
+	}
+	if _, err := ParseTeam([]byte("- just\n- a list\n")); err == nil {
+		t.Fatalf("expected an error for an unrecognized roster")
+	}
+}
+
+func TestLoadTeam(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "cache")
+	defer os.RemoveAll(dir) // clean up
+	os.Setenv("XDG_CACHE_HOME", dir)
+	defer os.Unsetenv("XDG_CACHE_HOME")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "---\nlb: Lindsay Fünke\ntf: Tobias Fünke\n")
+	}))
+	defer server.Close()
+
+	config := &Config{
+		Author:        &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates:     []*Author{&Author{Name: "Lindsay Bluth", Alias: "lb"}},
+		TeamRosterURL: server.URL,
+	}
+	if err := config.LoadTeam(); err != nil {
+		t.Fatalf("expected no error loading the team roster, got %v", err)
+	}
+	authors, err := config.With([]string{"lb", "tf"})
+	if err != nil {
+		t.Fatalf("expected organization aliases to resolve, got %v", err)
+	}
+	if ComposeName(authors) != "Lindsay Bluth and Michael Bluth and Tobias Fünke" {
+		t.Fatalf("expected local teammates to shadow the organization, got %s", ComposeName(authors))
+	}
+	if roster := config.Roster(); len(roster) != 2 {
+		t.Fatalf("expected shadowed organization members to be left out, got %v", roster)
+	}
+}
+
+func TestFetchTeamOffline(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "cache")
+	defer os.RemoveAll(dir) // clean up
+	os.Setenv("XDG_CACHE_HOME", dir)
+	defer os.Unsetenv("XDG_CACHE_HOME")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "---\ntf: Tobias Fünke\n")
+	}))
+	url := server.URL + "/roster.yml"
+	if _, err := FetchTeam(url); err != nil {
+		t.Fatalf("expected no error fetching the roster, got %v", err)
+	}
+	server.Close()
+
+	team, err := FetchTeam(url)
+	if _, ok := err.(*StaleTeamError); !ok {
+		t.Fatalf("expected a stale roster error once offline, got %v", err)
+	}
+	if len(team) != 1 || team[0].Alias != "tf" {
+		t.Fatalf("expected the cached roster, got %v", team)
+	}
+
+	if _, err := FetchTeam(server.URL + "/never-fetched.yml"); err == nil {
+		t.Fatalf("expected an error with nothing cached")
+	}
+}
+
+func TestReadWarnsAboutStalePairsFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "cache")
+	defer os.RemoveAll(dir) // clean up
+	os.Setenv("XDG_CACHE_HOME", dir)
+	defer os.Unsetenv("XDG_CACHE_HOME")
+	path := filepath.Join(dir, "config.yml")
+	ioutil.WriteFile(path, []byte("vcs: git\nauthor:\n  name: Michael Bluth\n  alias: mb\n  email: mb@example.com\n"), 0644)
+	defer os.Setenv("PAIR_CONFIG", os.Getenv("PAIR_CONFIG"))
+	os.Setenv("PAIR_CONFIG", path)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "---\ntf: Tobias Fünke\n")
+	}))
+	defer os.Setenv("PAIR_FILE", os.Getenv("PAIR_FILE"))
+	os.Setenv("PAIR_FILE", server.URL+"/pairs.yml")
+	config, err := Read()
+	if err != nil || len(config.Warnings()) != 0 {
+		t.Fatalf("expected no warnings online, got %v and %v", err, config.Warnings())
+	}
+	server.Close()
+	old := time.Now().Add(-24 * time.Hour)
+	os.Chtimes(TeamCachePath(server.URL+"/pairs.yml"), old, old)
+
+	config, err = Read()
+	if err != nil {
+		t.Fatalf("expected the cached pairs file to do offline, got %v", err)
+	}
+	if len(config.Warnings()) != 1 {
+		t.Fatalf("expected a warning about the cached pairs file, got %v", config.Warnings())
+	}
+	if a := config.lookupAlias("tf"); a == nil {
+		t.Fatal("expected tf from the cached pairs file")
+	}
+}
+
+func TestSyncTeammates(t *testing.T) {
+	config := &Config{
+		Author: &Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates: []*Author{
+			&Author{Name: "Lindsay Bluth", Alias: "lb"},
+			&Author{Name: "George Bluth", Alias: "gb", Email: "gb@example.com"},
+		},
+	}
+	added, updated := config.SyncTeammates([]*Author{
+		{Name: "mb", Alias: "mb", Email: "1+mb@users.noreply.github.com", GitHub: "mb", GitHubID: 1},
+		{Name: "lindsay", Alias: "lb", Email: "2+lb@users.noreply.github.com", GitHub: "lb", GitHubID: 2},
+		{Name: "George Sr.", Alias: "gb", Email: "3+gb@users.noreply.github.com"},
+		{Name: "Tobias Fünke", Alias: "tf", Email: "4+tf@users.noreply.github.com"},
+		{Name: "Impostor", Alias: "gob", Email: "GB@example.com"},
+	})
+	if added != 1 || updated != 2 {
+		t.Fatalf("expected 1 teammate added and 2 filled in, got %d and %d", added, updated)
+	}
+	if config.Author.Email != "mb@example.com" || config.Author.NoreplyEmail() != "1+mb@users.noreply.github.com" {
+		t.Fatalf("expected only your GitHub login and ID to be filled in, got %v", config.Author)
+	}
+	if lb := config.lookupLocal("lb"); lb.Name != "Lindsay Bluth" || lb.Email != "2+lb@users.noreply.github.com" || lb.GitHubID != 2 {
+		t.Fatalf("expected only lb's missing email and GitHub ID to be filled in, got %v", lb)
+	}
+	if len(config.Teammates) != 3 || config.Teammates[2].Alias != "tf" {
+		t.Fatalf("expected tf to be added and gob skipped, got %v", config.Teammates)
+	}
+}
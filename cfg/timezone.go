@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHours are the working hours assumed for authors who don't set any.
+const DefaultHours = "9-17"
+
+// Location returns the author's timezone, or time.Local if they haven't set
+// one.
+func (a *Author) Location() (*time.Location, error) {
+	if a.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(a.Timezone)
+}
+
+// WorkingHours returns the hours of the day the author starts and stops
+// working, in their own timezone.
+func (a *Author) WorkingHours() (int, int, error) {
+	hours := a.Hours
+	if hours == "" {
+		hours = DefaultHours
+	}
+	parts := strings.Split(hours, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected a range of hours like %s, got %s", DefaultHours, hours)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("expected a range of hours like %s, got %s", DefaultHours, hours)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("expected a range of hours like %s, got %s", DefaultHours, hours)
+	}
+	if start < 0 || end > 24 || start >= end {
+		return 0, 0, fmt.Errorf("hours must be within 0-24 and start before they end, got %s", hours)
+	}
+	return start, end, nil
+}
+
+// LocalTime returns now in the author's timezone.
+func (a *Author) LocalTime(now time.Time) time.Time {
+	if loc, err := a.Location(); err == nil {
+		return now.In(loc)
+	}
+	return now
+}
+
+// OffHours reports whether now is well outside the author's working hours:
+// more than an hour before they start or after they stop.
+func (a *Author) OffHours(now time.Time) bool {
+	start, end, err := a.WorkingHours()
+	if err != nil {
+		return false
+	}
+	local := a.LocalTime(now)
+	hour := float64(local.Hour()) + float64(local.Minute())/60
+	return hour < float64(start-1) || hour >= float64(end+1)
+}
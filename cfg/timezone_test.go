@@ -0,0 +1,41 @@
+package cfg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkingHours(t *testing.T) {
+	a := &Author{Alias: "lb"}
+	if start, end, err := a.WorkingHours(); start != 9 || end != 17 || err != nil {
+		t.Fatalf("expected default hours of 9-17, got %d-%d, %v", start, end, err)
+	}
+	for _, hours := range []string{"9", "nine-five", "17-9", "0-25"} {
+		a.Hours = hours
+		if _, _, err := a.WorkingHours(); err == nil {
+			t.Fatalf("expected hours %s to be invalid", hours)
+		}
+	}
+}
+
+func TestOffHours(t *testing.T) {
+	a := &Author{Alias: "lb", Timezone: "Asia/Tokyo", Hours: "10-18"}
+	if _, err := a.Location(); err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+	// 01:00 UTC is 10:00 in Tokyo.
+	if a.OffHours(time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 10:00 in Tokyo to be within working hours")
+	}
+	// 09:30 UTC is 18:30 in Tokyo, within the hour of grace.
+	if a.OffHours(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected 18:30 in Tokyo to be close enough to working hours")
+	}
+	// 15:00 UTC is midnight in Tokyo.
+	if !a.OffHours(time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected midnight in Tokyo to be off hours")
+	}
+	if got := a.LocalTime(time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)).Format("15:04"); got != "00:00" {
+		t.Fatalf("expected local time of 00:00, got %s", got)
+	}
+}
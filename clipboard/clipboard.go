@@ -0,0 +1,61 @@
+// Package clipboard copies text to the system clipboard using whichever
+// clipboard tool the platform provides.
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// candidates lists clipboard commands in order of preference, per platform.
+var candidates = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux": {
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		{"clip.exe"},
+	},
+}
+
+// lookPath is exec.LookPath, replaceable in tests.
+var lookPath = exec.LookPath
+
+// Command returns the command line used to copy to the clipboard on goos,
+// or an error if none of the known tools are installed. Wayland's wl-copy is
+// only used in a Wayland session.
+func Command(goos string) ([]string, error) {
+	list, ok := candidates[goos]
+	if !ok {
+		list = candidates["linux"]
+	}
+	for _, c := range list {
+		if c[0] == "wl-copy" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		if _, err := lookPath(c[0]); err == nil {
+			return c, nil
+		}
+	}
+	var names []string
+	for _, c := range list {
+		names = append(names, c[0])
+	}
+	return nil, errors.New("no clipboard tool found; install one of " + strings.Join(names, ", "))
+}
+
+// Copy places text on the system clipboard.
+func Copy(text string) error {
+	args, err := Command(runtime.GOOS)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,44 @@
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func fakePath(installed ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, i := range installed {
+			if i == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	defer func(original func(string) (string, error)) { lookPath = original }(lookPath)
+	defer os.Setenv("WAYLAND_DISPLAY", os.Getenv("WAYLAND_DISPLAY"))
+	os.Unsetenv("WAYLAND_DISPLAY")
+
+	lookPath = fakePath("pbcopy")
+	if c, err := Command("darwin"); err != nil || c[0] != "pbcopy" {
+		t.Fatalf("expected pbcopy on macOS, got %v, %v", c, err)
+	}
+
+	lookPath = fakePath("wl-copy", "xclip")
+	if c, err := Command("linux"); err != nil || strings.Join(c, " ") != "xclip -selection clipboard" {
+		t.Fatalf("expected xclip outside of Wayland, got %v, %v", c, err)
+	}
+	os.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	if c, err := Command("linux"); err != nil || c[0] != "wl-copy" {
+		t.Fatalf("expected wl-copy under Wayland, got %v, %v", c, err)
+	}
+
+	lookPath = fakePath()
+	if _, err := Command("linux"); err == nil {
+		t.Fatalf("expected an error with no clipboard tool installed")
+	}
+}
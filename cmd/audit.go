@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/keeferrourke/pair/audit"
+	"github.com/keeferrourke/pair/i18n"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Audit provides the `pair audit` command. Shows every change pair has made
+// to git configuration.
+var Audit = cli.Command{
+	Name:  "audit",
+	Usage: "Show the changes pair has made to git configuration.",
+	Action: func(cx *cli.Context) error {
+		entries, err := audit.Read(audit.Path())
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read the audit log: %v", err), 1)
+		}
+		for _, e := range entries {
+			where := e.Action
+			if e.File != "" {
+				where += " --file " + e.File
+			}
+			fmt.Fprintf(cx.App.Writer, "%s  %s@%s  %s %s: %q -> %q\n",
+				e.Time.Format("2006-01-02 15:04:05"), e.User, e.Host, where, e.Key, e.Old, e.New)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/keeferrourke/pair/auth"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/secret"
+	"golang.org/x/term"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Auth provides the `pair auth` command. Manages the API tokens used for
+// GitHub, GitLab, Jira and Slack, kept in the system keychain where there is
+// one. On its own, lists each provider and where its token comes from.
+var Auth = cli.Command{
+	Name:  "auth",
+	Usage: "Manage API tokens for GitHub, GitLab, Jira and Slack.",
+	Action: func(cx *cli.Context) error {
+		store := secret.Default()
+		w := table(cx.App.Writer)
+		defer w.Flush()
+		for _, p := range auth.Providers {
+			_, source, err := p.Token(store)
+			switch {
+			case err != nil:
+				source = err.Error()
+			case source == "":
+				source = i18n.T("none")
+			}
+			fmt.Fprintf(w, "%s\t%s\n", p.Name, source)
+		}
+		return nil
+	},
+	Subcommands: []cli.Command{
+		{
+			Name:      "set",
+			Usage:     "Store a token, read from stdin.",
+			ArgsUsage: "<provider>",
+			Action:    authSet,
+		},
+		{
+			Name:      "test",
+			Usage:     "Check a token with its provider.",
+			ArgsUsage: "<provider>",
+			Action:    authTest,
+		},
+		{
+			Name:      "remove",
+			Aliases:   []string{"rm"},
+			Usage:     "Forget a stored token.",
+			ArgsUsage: "<provider>",
+			Action:    authRemove,
+		},
+	},
+}
+
+// authProvider returns the provider named by the only argument.
+func authProvider(cx *cli.Context) (*auth.Provider, error) {
+	if cx.NArg() != 1 {
+		return nil, cli.NewExitError(i18n.T("error: expected a provider: github, gitlab, jira or slack"), 1)
+	}
+	p, err := auth.Lookup(cx.Args().First())
+	if err != nil {
+		return nil, cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	return p, nil
+}
+
+func authSet(cx *cli.Context) error {
+	p, err := authProvider(cx)
+	if err != nil {
+		return err
+	}
+	token, err := readToken(cx, p)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read the token: %v", err), 1)
+	}
+	if token == "" {
+		return cli.NewExitError(i18n.T("error: no token given"), 1)
+	}
+	if err := secret.Default().Set(p.Name, token); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to store the token: %v", err), 1)
+	}
+	fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Stored the %s token. Check it with pair auth test %s.", p.Name, p.Name))
+	return nil
+}
+
+// readToken reads a token from stdin, without echoing it on a terminal.
+func readToken(cx *cli.Context, p *auth.Provider) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if line == "" && err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+	fmt.Fprintf(cx.App.ErrWriter, "%s\n%s ", i18n.T(p.Usage), i18n.Sprintf("%s token:", p.Name))
+	buf, err := term.ReadPassword(fd)
+	fmt.Fprintln(cx.App.ErrWriter)
+	return strings.TrimSpace(string(buf)), err
+}
+
+func authTest(cx *cli.Context) error {
+	p, err := authProvider(cx)
+	if err != nil {
+		return err
+	}
+	token, source, err := p.Token(secret.Default())
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read the token: %v", err), 1)
+	}
+	if token == "" {
+		return cli.NewExitError(i18n.Sprintf("error: no %s token; store one with pair auth set %s", p.Name, p.Name), 1)
+	}
+	spinner := progress(cx.App.ErrWriter, i18n.Sprintf("Checking the %s token", p.Name))
+	who, err := p.Test(token)
+	spinner.Stop()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %s token (%s): %v", p.Name, source, err), 1)
+	}
+	fmt.Fprintln(cx.App.Writer, i18n.Sprintf("The %s token (%s) works, authenticating as %s.", p.Name, source, who))
+	return nil
+}
+
+func authRemove(cx *cli.Context) error {
+	p, err := authProvider(cx)
+	if err != nil {
+		return err
+	}
+	switch err := secret.Default().Delete(p.Name); {
+	case err == secret.ErrNotFound:
+		return cli.NewExitError(i18n.Sprintf("error: no %s token is stored", p.Name), 1)
+	case err != nil:
+		return cli.NewExitError(i18n.Sprintf("error: unable to remove the token: %v", err), 1)
+	}
+	fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Removed the %s token.", p.Name))
+	return nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keeferrourke/pair/auth"
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/github"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/tracker"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// branchAuthors returns who the current pair's branches are named for: the
+// session's authors, or when not pairing just you, if you have an alias.
+func branchAuthors(config *cfg.Config) []*cfg.Author {
+	if s, err := session.Current(); err == nil && len(s.Authors) > 0 {
+		return s.Authors
+	}
+	if config.Author == nil || config.Author.Alias == "" {
+		return nil
+	}
+	return []*cfg.Author{config.Author}
+}
+
+// listBranches prints the branches named for authors, marking the checked
+// out one.
+func listBranches(cx *cli.Context, config *cfg.Config, repo vcs.VCS, authors []*cfg.Author) error {
+	branches, err := repo.Branches()
+	if err == nil {
+		branches, err = config.PairBranches(authors, branches)
+	}
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to list branches: %v", err), 1)
+	}
+	current := repo.CurrentBranch()
+	for _, branch := range branches {
+		marker := " "
+		if branch == current {
+			marker = "*"
+		}
+		fmt.Fprintf(cx.App.Writer, "%s %s\n", marker, branch)
+	}
+	return nil
+}
+
+// cleanupBranches deletes the branches named for authors which are merged
+// into base, except the checked out one, or with --dry-run only prints them.
+func cleanupBranches(cx *cli.Context, config *cfg.Config, repo vcs.VCS, authors []*cfg.Author, base string) error {
+	merged, err := repo.MergedBranches(base)
+	if err == nil {
+		merged, err = config.PairBranches(authors, merged)
+	}
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to list branches merged into %s: %v", base, err), 1)
+	}
+	current := repo.CurrentBranch()
+	failed := 0
+	for _, branch := range merged {
+		if branch == current || branch == base {
+			continue
+		}
+		if cx.Bool("dry-run") {
+			fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Would delete branch %s", branch))
+			continue
+		}
+		if err := repo.DeleteBranch(branch); err != nil {
+			warnf(cx.App.ErrWriter, "%v", err)
+			failed++
+			continue
+		}
+		fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Deleted branch %s", branch))
+	}
+	if failed > 0 {
+		return cli.NewExitError(i18n.Sprintf("error: unable to delete %d branches", failed), 1)
+	}
+	return nil
+}
+
+// ticketBranch works out the branch for ticket when it's one of the
+// tracker's, such as ONCALL-843. It returns an existing branch for the
+// ticket to switch back to, or else what to name a new one: the ticket and
+// its title, like ONCALL-843-fix-login-timeout. Offline, or when the title
+// can't be fetched, that's just the ticket.
+func ticketBranch(cx *cli.Context, config *cfg.Config, repo vcs.VCS, authors []*cfg.Author, ticket string) (existing, name string) {
+	t := *config.Tracker
+	if t.Kind == cfg.TrackGitHub && t.Repo == "" {
+		if owner, repoName, err := github.ParseRemote(vcs.ConfigValue("remote.origin.url")); err == nil {
+			t.Repo = owner + "/" + repoName
+		}
+	}
+	tr, err := tracker.New(&t, auth.Token(t.Kind))
+	if err != nil {
+		warnf(cx.App.ErrWriter, "tracker: %v", err)
+		return "", ticket
+	}
+	if !tr.IsTicket(ticket) {
+		return "", ticket
+	}
+
+	named := config
+	if cx.Bool("no-prefix") {
+		named = &cfg.Config{BranchTemplate: "{{.Ticket}}"}
+	}
+	if branches, err := repo.Branches(); err == nil {
+		existing, err = named.TicketBranch(authors, strings.TrimPrefix(ticket, "#"), branches)
+		if existing != "" || err != nil {
+			return existing, ticket
+		}
+	}
+
+	spinner := progress(cx.App.ErrWriter, i18n.Sprintf("Fetching the title of %s", ticket))
+	title, err := tr.Title(ticket)
+	spinner.Stop()
+	if err != nil {
+		warnf(cx.App.ErrWriter, "tracker: unable to fetch %s, naming the branch without its title: %v", ticket, err)
+	}
+	return "", tracker.Branch(ticket, title)
+}
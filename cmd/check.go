@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/keeferrourke/pair/compliance"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Check provides the `pair check` command. Reports which commits in a range
+// were paired, optionally as a JSON or JUnit artifact for CI.
+var Check = cli.Command{
+	Name:      "check",
+	Usage:     "Report pairing compliance for a range of commits.",
+	ArgsUsage: "[<base>..<head>]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "report",
+			Usage: "Report format: text, json or junit.",
+			Value: "text",
+		},
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "Write the report to this file instead of stdout.",
+		},
+		cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Exit with an error if any commit wasn't paired.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		revRange := "HEAD"
+		if cx.NArg() > 0 {
+			revRange = cx.Args().First()
+		}
+		commits, err := vcs.Commits(revRange)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read commits in %s: %v", revRange, err), 1)
+		}
+		report := compliance.Analyze(revRange, commits)
+
+		var w io.Writer = cx.App.Writer
+		if path := cx.String("out"); path != "" {
+			f, err := os.Create(path)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to create report: %v", err), 1)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		switch cx.String("report") {
+		case "text":
+			err = report.WriteText(w)
+		case "json":
+			err = report.WriteJSON(w)
+		case "junit":
+			err = report.WriteJUnit(w)
+		default:
+			return cli.NewExitError(i18n.Sprintf("error: unknown report format: %s", cx.String("report")), 1)
+		}
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to write report: %v", err), 1)
+		}
+
+		if cx.Bool("strict") && report.Paired < report.Total {
+			return cli.NewExitError(i18n.Sprintf("error: %d of %d commits weren't paired", report.Total-report.Paired, report.Total), 1)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/shell"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// CI provides the `pair ci` command. Prints shell exports crediting a pair as
+// the author of commits made by automation, with the bot as committer, e.g.
+// `eval "$(pair ci)"` in a merge queue or backport job.
+var CI = cli.Command{
+	Name:  "ci",
+	Usage: "Print shell exports for a bot committing on behalf of a pair.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:   "aliases",
+			Usage:  "Comma-separated aliases of the pair to credit.",
+			EnvVar: "PAIR_CI_ALIASES",
+		},
+		cli.StringFlag{
+			Name:   "bot",
+			Usage:  "Committer identity, as Name <email>.",
+			EnvVar: "PAIR_CI_BOT",
+		},
+		shellFlag,
+	},
+	Action: func(cx *cli.Context) error {
+		if cx.String("bot") == "" {
+			return cli.NewExitError(i18n.T("error: set --bot or $PAIR_CI_BOT to the committer, as Name <email>"), 1)
+		}
+		config, err := readCIConfig()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		aliases, err := cfg.SplitAliases([]string{cx.String("aliases")}, os.Stdin)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		author, committer, err := config.CI(aliases, cx.String("bot"))
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		exports, err := shell.Exports(shellName(cx), []string{
+			"GIT_AUTHOR_NAME=" + author.Name,
+			"GIT_AUTHOR_EMAIL=" + author.Email,
+			"GIT_COMMITTER_NAME=" + committer.Name,
+			"GIT_COMMITTER_EMAIL=" + committer.Email,
+		})
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		fmt.Fprint(cx.App.Writer, exports)
+		return nil
+	},
+}
+
+// readCIConfig reads your config, or just the repository's when there isn't
+// one, as is usual on a build machine.
+func readCIConfig() (*cfg.Config, error) {
+	config, err := cfg.Read()
+	if err == nil || !os.IsNotExist(err) {
+		return config, err
+	}
+	config = cfg.New(cfg.DefaultPath())
+	repo, err := cfg.ReadRepo()
+	if err != nil {
+		return nil, err
+	}
+	config.ApplyRepo(repo)
+	if _, err := config.ApplyEnv(os.Environ()); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
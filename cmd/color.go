@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/tui"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// noColorFlag turns off colored output, which is otherwise used whenever the
+// output is a terminal and $NO_COLOR is unset.
+var noColorFlag = cli.BoolFlag{
+	Name:  "no-color",
+	Usage: "Don't color output, even on a terminal.",
+}
+
+// noColor is set by the global --no-color flag.
+var noColor bool
+
+// colored reports whether output to w should be colored.
+func colored(w io.Writer) bool {
+	return !noColor && tui.ColorEnabled(w)
+}
+
+// paint colors text with the SGR code if output to w is colored.
+func paint(w io.Writer, code, text string) string {
+	if !colored(w) {
+		return text
+	}
+	return tui.Paint(code, text)
+}
+
+// cell paints a table cell, padding cells which aren't highlighted to the
+// same width so tabwriter keeps the columns aligned.
+func cell(w io.Writer, highlight bool, text string) string {
+	if !colored(w) {
+		return text
+	}
+	if highlight {
+		return tui.Paint(tui.Bold, text)
+	}
+	return tui.Paint(tui.Plain, text)
+}
+
+// warnf prints a warning to w, with the "warning:" prefix in yellow.
+func warnf(w io.Writer, format string, args ...interface{}) {
+	fmt.Fprintf(w, "%s %s\n", paint(w, tui.Yellow, i18n.T("warning:")), i18n.Sprintf(format, args...))
+}
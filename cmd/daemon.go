@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/service"
+	"github.com/keeferrourke/pair/session"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// timerInterval is how often the daemon checks whether the pair has gone
+// idle or expired.
+const timerInterval = time.Minute
+
+// Daemon provides the `pair daemon` command. Answers queries about the
+// current pair on a unix socket until interrupted, so prompts and editors can
+// ask without starting pair on every render, and reverts or marks the pair
+// once it's idle or expired, like `pair idle`.
+var Daemon = cli.Command{
+	Name:    "daemon",
+	Aliases: []string{"serve"},
+	Usage:   "Answer prompt queries about the current pair on a unix socket, and run the session timers.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "socket",
+			Usage: "Where to listen (default: pair.sock in $XDG_RUNTIME_DIR).",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.ReadLocal()
+		if err != nil {
+			// Without a config there's no TTL, but the pair can still be
+			// reported.
+			config = cfg.New(cfg.DefaultPath())
+		}
+		server, err := service.NewServer(config)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		path := cx.String("socket")
+		if path == "" {
+			path = service.SocketPath()
+		}
+		l, err := service.Listen(path)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to listen: %v", err), 1)
+		}
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			l.Close() // also removes the socket
+		}()
+		go runTimers(cx)
+		fmt.Fprintln(cx.App.ErrWriter, i18n.Sprintf("Listening on %s", path))
+		server.Serve(l)
+		return nil
+	},
+}
+
+// runTimers checks every timerInterval whether the pair is due to be
+// reverted, rereading the local config files each time so changes to them
+// apply without ever waiting on a remote pairs file.
+func runTimers(cx *cli.Context) {
+	for now := range time.Tick(timerInterval) {
+		config, err := cfg.ReadLocal()
+		if err != nil {
+			continue // Without a config there are no timers
+		}
+		reason, err := revertIfDue(cx.App.ErrWriter, config, now)
+		if err != nil {
+			warnf(cx.App.ErrWriter, "%v", err)
+		} else if reason != "" {
+			fmt.Fprintln(cx.App.ErrWriter, i18n.Sprintf("Reverted to %s: %s", config.Author.Name, reason))
+		}
+	}
+}
+
+// currentSession returns the session from pair daemon if it's running, or
+// else reads it from its file.
+func currentSession() (*session.Session, error) {
+	if s, err := service.CurrentSession(service.SocketPath()); err == nil {
+		return s, nil
+	}
+	return session.Current()
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/internal/block"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/shell"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Direnv provides the `pair direnv` command. Maintains a block in the
+// repository's .envrc exporting the current pair, so direnv applies it on
+// entering the repository and reverts it on leaving.
+var Direnv = cli.Command{
+	Name:  "direnv",
+	Usage: "Export the current pair from this repository's .envrc.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "remove",
+			Usage: "Remove the pair block from .envrc.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		root, err := vcs.Git("rev-parse", "--show-toplevel")
+		if err != nil {
+			return cli.NewExitError(i18n.T("error: not in a git repository"), 1)
+		}
+		path := filepath.Join(root, ".envrc")
+		buf, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read .envrc: %v", err), 1)
+		}
+
+		var envrc string
+		if cx.Bool("remove") {
+			envrc = block.Remove(string(buf))
+		} else {
+			s, err := session.Current()
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+			}
+			if s.ID == "" {
+				return cli.NewExitError(i18n.T("error: no pairing session is active"), 1)
+			}
+			exports, err := shell.Exports("bash", s.Environment())
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			envrc = block.Replace(string(buf), exports)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(envrc), 0644); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to write .envrc: %v", err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "Updated %s; run `direnv allow` to apply it\n", path)
+		return nil
+	},
+}
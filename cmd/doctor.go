@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/signing"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// check diagnoses one part of the pairing setup, returning any problems.
+type check struct {
+	name string
+	run  func() []error
+}
+
+var checks = []check{
+	{"signing", checkSigning},
+	{"signingkey", checkRosterKey},
+}
+
+// Doctor provides the `pair doctor` command. Runs every check and reports
+// the problems found.
+var Doctor = cli.Command{
+	Name:  "doctor",
+	Usage: "Diagnose problems with the pairing setup.",
+	Action: func(cx *cli.Context) error {
+		failed := false
+		for _, c := range checks {
+			problems := c.run()
+			if len(problems) == 0 {
+				fmt.Fprintf(cx.App.Writer, "%s: ok\n", c.name)
+				continue
+			}
+			failed = true
+			for _, p := range problems {
+				fmt.Fprintf(cx.App.Writer, "%s: %v\n", c.name, p)
+			}
+		}
+		if failed {
+			return cli.NewExitError("", 1)
+		}
+		return nil
+	},
+}
+
+// checkSigning verifies that the configured signing key can sign commits as
+// the current pair. It is skipped when signing isn't configured.
+func checkSigning() []error {
+	config := signing.Config{
+		Format:         vcs.ConfigValue("gpg.format"),
+		Program:        vcs.ConfigValue("gpg.program"),
+		Key:            vcs.ConfigValue("user.signingkey"),
+		AllowedSigners: vcs.ConfigValue("gpg.ssh.allowedSignersFile"),
+	}
+	if config.Key == "" && vcs.ConfigValue("commit.gpgsign") != "true" {
+		return nil
+	}
+
+	s, err := session.Current()
+	if err != nil {
+		return []error{err}
+	}
+	email := s.Email
+	if email == "" {
+		email = vcs.ConfigValue("user.email")
+	}
+	return signing.Verify(config, []string{email}, time.Now())
+}
+
+// checkRosterKey verifies that the signing key git uses is the one recorded
+// for you in the roster, if one is.
+func checkRosterKey() []error {
+	config, err := cfg.Read()
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return []error{err}
+	}
+	if config.Author == nil || config.Author.SigningKey == "" {
+		return nil
+	}
+	key := vcs.ConfigValue("user.signingkey")
+	if strings.HasPrefix(key, "key::") {
+		key = strings.TrimPrefix(key, "key::")
+	} else if signing.IsSSHKey(config.Author.SigningKey) && key != "" {
+		if buf, err := ioutil.ReadFile(key); err == nil {
+			key = string(buf)
+		}
+	}
+	if !sameKey(key, config.Author.SigningKey) {
+		return []error{fmt.Errorf("user.signingkey %s isn't the signingkey in the roster, %s", vcs.ConfigValue("user.signingkey"), config.Author.SigningKey)}
+	}
+	return nil
+}
+
+// sameKey compares signing keys, ignoring SSH key comments and the case and
+// length of GPG key ids.
+func sameKey(a, b string) bool {
+	if signing.IsSSHKey(a) || signing.IsSSHKey(b) {
+		fa, fb := strings.Fields(a), strings.Fields(b)
+		return len(fa) >= 2 && len(fb) >= 2 && fa[0] == fb[0] && fa[1] == fb[1]
+	}
+	a, b = strings.ToUpper(strings.TrimPrefix(a, "0x")), strings.ToUpper(strings.TrimPrefix(b, "0x"))
+	return a != "" && b != "" && (strings.HasSuffix(a, b) || strings.HasSuffix(b, a))
+}
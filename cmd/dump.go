@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// dumpConfig implements `pair config dump`. Prints the config in effect, with
+// the repository's config, built-in defaults and environment overrides merged
+// in, or just your own config file with --global. With --effective, each
+// setting is listed along with where it came from.
+func dumpConfig(cx *cli.Context) error {
+	var config *cfg.Config
+	var err error
+	if cx.GlobalBool("global") {
+		config, err = cfg.NewFromFile(cfg.DefaultPath())
+	} else {
+		config, err = cfg.Read()
+	}
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+	}
+
+	if !cx.Bool("effective") {
+		enc := yaml.NewEncoder(cx.App.Writer)
+		enc.SetIndent(2)
+		if err := enc.Encode(config); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		return enc.Close()
+	}
+
+	settings, err := config.Effective()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	w := table(cx.App.Writer)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Emails provides the `pair emails` command. Explains which email `pair with`
+// would commit as for the given aliases, listing every candidate address and
+// why it was or wasn't chosen, for when attribution comes out wrong.
+var Emails = cli.Command{
+	Name:      "emails",
+	Usage:     "Explain which email a pair commits as.",
+	ArgsUsage: "<alias>...",
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		loadTeam(cx.App.ErrWriter, config)
+		aliases, err := cfg.SplitAliases(cx.Args(), os.Stdin)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		authors, err := config.With(aliases)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+
+		template, err := config.EmailTemplate()
+		source := i18n.T("the domain of your email")
+		switch {
+		case err != nil:
+			source = err.Error()
+		case os.Getenv("PAIR_EMAIL") != "":
+			source = "$PAIR_EMAIL"
+		}
+		fmt.Fprintf(cx.App.Writer, "%s %s (%s)\n", i18n.T("template:"), template, source)
+
+		writeEmails(cx.App.Writer, cfg.ComposeName(authors), config.EmailCandidates(template, authors))
+		if len(authors) > 1 {
+			for _, a := range authors {
+				writeEmails(cx.App.Writer, a.Name, cfg.EmailCandidates(template, []*cfg.Author{a}))
+			}
+		}
+		return nil
+	},
+}
+
+// writeEmails prints the candidate emails for who, marking the chosen one.
+func writeEmails(out io.Writer, who string, candidates []cfg.EmailCandidate) {
+	fmt.Fprintf(out, "\n%s\n", who)
+	w := table(out)
+	defer w.Flush()
+	for _, c := range candidates {
+		mark, email := " ", c.Email
+		if c.Chosen {
+			mark = "*"
+		}
+		if email == "" {
+			email = "-"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\n", mark, cell(out, c.Chosen, c.Source), cell(out, c.Chosen, email), c.Reason)
+	}
+}
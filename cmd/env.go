@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/shell"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var shellFlag = cli.StringFlag{
+	Name:   "shell",
+	Usage:  "Shell syntax to print: bash, zsh, fish or powershell (default: detected).",
+	EnvVar: "PAIR_SHELL",
+}
+
+// shellName returns the shell selected with --shell, or the detected one.
+func shellName(cx *cli.Context) string {
+	if name := cx.String("shell"); name != "" {
+		return name
+	}
+	return shell.Detect()
+}
+
+// Env provides the `pair env` command. Prints shell code exporting the git
+// environment for the given aliases, or else the current pair, for use with
+// eval. Given aliases, it changes no git config and starts no session, so the
+// pair only applies to the shell which evaluates it.
+var Env = cli.Command{
+	Name:      "env",
+	Usage:     "Print shell exports for a pair, or the current pair, without changing git config.",
+	ArgsUsage: "[<alias>...]",
+	Flags:     []cli.Flag{shellFlag},
+	Action: func(cx *cli.Context) error {
+		if cx.NArg() > 0 {
+			return exportPair(cx)
+		}
+		s, err := session.Current()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+		}
+		if s.ID == "" {
+			return cli.NewExitError(i18n.T("error: no pairing session is active"), 1)
+		}
+		exports, err := shell.Exports(shellName(cx), s.Environment())
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		fmt.Fprint(cx.App.Writer, exports)
+		return nil
+	},
+}
+
+// InitShell provides the `pair init-shell` command. Prints a shell function
+// wrapping pair so that pairing can apply to the current shell only, e.g.
+// `eval "$(pair init-shell bash)"`.
+var InitShell = cli.Command{
+	Name:      "init-shell",
+	Usage:     "Print a shell function for per-shell pairing.",
+	ArgsUsage: "[bash|zsh|fish|powershell]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "unpair-on-exit",
+			Usage: "Revert to your own identity when a shell which changed the pair exits.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		name := shell.Detect()
+		if cx.NArg() > 0 {
+			name = cx.Args().First()
+		}
+		script, err := shell.Init(name)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		if cx.Bool("unpair-on-exit") {
+			hook, err := shell.UnpairOnExit(name)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			script += hook
+		}
+		fmt.Fprint(cx.App.Writer, script)
+		return nil
+	},
+}
+
+// exportPair prints shell exports for the pair named by the arguments, like
+// `pair with --export`.
+func exportPair(cx *cli.Context) error {
+	aliases, err := cfg.SplitAliases(cx.Args(), os.Stdin)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	config, err := cfg.Read()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+	}
+	loadTeam(cx.App.ErrWriter, config)
+	lookupUnknown(cx.App.ErrWriter, config, aliases)
+	authors, err := config.With(aliases)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	warnAway(cx.App.ErrWriter, authors, time.Now())
+	warnOffHours(cx.App.ErrWriter, authors, time.Now())
+	return exportAuthors(cx, config, authors)
+}
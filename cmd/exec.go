@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Exec provides the `pair exec` command. Runs a command with git's author and
+// committer environment set for the current pair, leaving git config alone.
+var Exec = cli.Command{
+	Name:            "exec",
+	Usage:           "Run a command as the current pair.",
+	ArgsUsage:       "-- <command> [<args>...]",
+	SkipFlagParsing: true,
+	Action: func(cx *cli.Context) error {
+		args := []string(cx.Args())
+		if len(args) > 0 && args[0] == "--" {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			return cli.NewExitError(i18n.T("error: expected a command to run"), 1)
+		}
+
+		s, err := session.Current()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+		}
+		if s.ID == "" {
+			return cli.NewExitError(i18n.T("error: no pairing session is active"), 1)
+		}
+
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Env = append(os.Environ(), s.Environment()...)
+		if editor, err := vcs.Git("var", "GIT_EDITOR"); err == nil {
+			cmd.Env = append(cmd.Env, "GIT_EDITOR="+trailerEditor(editor))
+		}
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return cli.NewExitError("", exitErr.ExitCode())
+			}
+			return cli.NewExitError(i18n.Sprintf("error: unable to run %s: %v", args[0], err), 1)
+		}
+		return nil
+	},
+}
+
+// trailerEditor wraps editor so that co-author trailers are added to the
+// commit message before it's opened. Git runs GIT_EDITOR through the shell
+// with the message file as its argument.
+func trailerEditor(editor string) string {
+	return fmt.Sprintf(`f() { pair hook prepare-commit-msg "$1" && %s "$1"; }; f`, editor)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Guess provides the `pair guess` command. Works out who has been pairing
+// from the authors and co-author trailers of the last few commits, and offers
+// to pair as them. Handy when taking over a machine mid-session.
+var Guess = cli.Command{
+	Name:  "guess",
+	Usage: "Guess the pair from recent commits and offer to pair as them.",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "commits, n",
+			Usage: "How many recent commits to look at.",
+			Value: 10,
+		},
+		cli.BoolFlag{
+			Name:  "yes, y",
+			Usage: "Pair as the guess without asking.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		loadTeam(cx.App.ErrWriter, config)
+		commits, err := vcs.RecentCommits(cx.Int("commits"))
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read recent commits: %v", err), 1)
+		}
+
+		var identities [][]string
+		for _, c := range commits {
+			identities = append(identities, commitIdentities(c))
+		}
+		authors := config.Guess(identities)
+		if authors == nil {
+			return cli.NewExitError(i18n.Sprintf("error: none of the last %d commits are by anyone in the roster", len(commits)), 1)
+		}
+
+		fmt.Fprintf(cx.App.Writer, "Recent commits were by %s\n", cfg.ComposeLabel(authors))
+		if !cx.Bool("yes") && !confirm(cx, "Pair as them?") {
+			return nil
+		}
+		s, err := applyIdentity(cx.App.ErrWriter, config, authors, "")
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to set the pair: %v", err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "%s <%s>\n", s.Name, s.Email)
+		return nil
+	},
+}
+
+// commitIdentities returns the identities credited with c: its author and
+// any co-authors from trailers.
+func commitIdentities(c *vcs.Commit) []string {
+	ids := []string{fmt.Sprintf("%s <%s>", c.Author, c.Email)}
+	for _, t := range trailer.Parse(c.Message) {
+		ids = append(ids, strings.TrimSpace(strings.TrimPrefix(t, trailer.CoAuthoredBy+":")))
+	}
+	return ids
+}
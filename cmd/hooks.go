@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/hooks"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var globalHooksFlag = cli.BoolFlag{
+	Name:  "global, g",
+	Usage: "Use a user-level hooks directory (core.hooksPath) for every repository.",
+}
+
+var optionalHooksFlag = cli.StringSliceFlag{
+	Name:  "with",
+	Usage: "Also manage an optional hook (pre-commit, post-commit).",
+}
+
+// hookNames returns the hooks selected on the command line: every required
+// hook plus any optional hooks named with --with.
+func hookNames(cx *cli.Context) ([]string, error) {
+	names := append([]string{}, hooks.Names...)
+	for _, name := range cx.StringSlice("with") {
+		if !hooks.IsOptional(name) {
+			return nil, fmt.Errorf("unknown optional hook: %s", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Hooks provides the `pair hooks` command. Installs and removes the git hooks
+// that add co-author trailers, chaining into any existing hooks.
+var Hooks = cli.Command{
+	Name:  "hooks",
+	Usage: "Manage pair's git hooks.",
+	Subcommands: []cli.Command{
+		{
+			Name:  "install",
+			Usage: "Install hooks in the current repository.",
+			Flags: []cli.Flag{globalHooksFlag, optionalHooksFlag},
+			Action: func(cx *cli.Context) error {
+				names, err := hookNames(cx)
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				if cx.Bool("global") {
+					return installGlobalHooks(cx, names)
+				}
+				return installRepoHooks(cx, names)
+			},
+		},
+		{
+			Name:  "uninstall",
+			Usage: "Remove hooks from the current repository.",
+			Flags: []cli.Flag{globalHooksFlag},
+			Action: func(cx *cli.Context) error {
+				names := append(append([]string{}, hooks.Names...), hooks.Optional...)
+				if cx.Bool("global") {
+					return uninstallGlobalHooks(cx, names)
+				}
+				loc, err := hooks.Locate()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				for _, name := range names {
+					if err := hooks.Uninstall(filepath.Join(loc.Dir, name)); err != nil {
+						return cli.NewExitError(i18n.Sprintf("error: unable to uninstall %s: %v", name, err), 1)
+					}
+				}
+				return nil
+			},
+		},
+	},
+}
+
+// installGlobalHooks installs hooks into the global core.hooksPath, setting
+// it to pair's own directory if the user hasn't configured one.
+func installGlobalHooks(cx *cli.Context, names []string) error {
+	dir, err := hooks.GlobalPath()
+	if err != nil {
+		dir = hooks.GlobalDir()
+		if _, err := vcs.Git("config", "--global", "core.hooksPath", dir); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to set core.hooksPath: %v", err), 1)
+		}
+	}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := hooks.InstallGlobal(path, name); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to install %s: %v", name, err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "Installed %s\n", path)
+	}
+	return nil
+}
+
+// uninstallGlobalHooks removes hooks from the global core.hooksPath, unsetting
+// it again if it was pair's own directory.
+func uninstallGlobalHooks(cx *cli.Context, names []string) error {
+	dir, err := hooks.GlobalPath()
+	if err != nil {
+		return nil
+	}
+	for _, name := range names {
+		if err := hooks.Uninstall(filepath.Join(dir, name)); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to uninstall %s: %v", name, err), 1)
+		}
+	}
+	if dir == hooks.GlobalDir() {
+		if _, err := vcs.Git("config", "--global", "--unset", "core.hooksPath"); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to unset core.hooksPath: %v", err), 1)
+		}
+	}
+	return nil
+}
+
+// Hook provides the hidden `pair hook` command run by the installed hooks.
+var Hook = cli.Command{
+	Name:   "hook",
+	Hidden: true,
+	Subcommands: []cli.Command{
+		{
+			Name:      "prepare-commit-msg",
+			ArgsUsage: "<message-file> [<source> [<commit>]]",
+			Action: func(cx *cli.Context) error {
+				if cx.NArg() < 1 {
+					return cli.NewExitError(i18n.T("error: expected the commit message file"), 1)
+				}
+				if cx.Args().Get(1) == "merge" {
+					return nil
+				}
+				s, err := session.Current()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+				}
+				trailers := trailer.Trailers(s)
+				if len(trailers) == 0 {
+					return nil
+				}
+
+				path := cx.Args().First()
+				buf, err := ioutil.ReadFile(path)
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read commit message: %v", err), 1)
+				}
+				message := trailer.Append(string(buf), trailers)
+				return ioutil.WriteFile(path, []byte(message), 0644)
+			},
+		},
+		{
+			Name:      "commit-msg",
+			ArgsUsage: "<message-file>",
+			Action: func(cx *cli.Context) error {
+				if cx.NArg() < 1 {
+					return cli.NewExitError(i18n.T("error: expected the commit message file"), 1)
+				}
+				if !hookInstalled("pre-commit") {
+					if err := checkStale(cx); err != nil {
+						return err
+					}
+				}
+				s, err := session.Current()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+				}
+				buf, err := ioutil.ReadFile(cx.Args().First())
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read commit message: %v", err), 1)
+				}
+				message := string(buf)
+				if strings.HasPrefix(message, "fixup! ") || strings.HasPrefix(message, "squash! ") {
+					// Squashed into a commit which has its own trailers.
+					return nil
+				}
+				missing := trailer.Missing(message, s)
+				if len(missing) == 0 {
+					return nil
+				}
+
+				var names []string
+				for _, a := range missing {
+					names = append(names, a.Alias)
+				}
+				text := fmt.Sprintf("the commit message doesn't credit %s with a Co-authored-by trailer", strings.Join(names, ", "))
+				config, err := cfg.Read()
+				if err == nil && config.OnMissingTrailers() == cfg.Block {
+					return cli.NewExitError(i18n.Sprintf("error: %s", text), 1)
+				}
+				warnf(cx.App.ErrWriter, "%s", text)
+				return nil
+			},
+		},
+		{
+			Name:   "pre-commit",
+			Action: checkStale,
+		},
+		{
+			Name: "post-commit",
+			Action: func(cx *cli.Context) error {
+				s, err := session.Current()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+				}
+				if s.ID == "" {
+					return nil
+				}
+				head, err := vcs.Git("rev-parse", "HEAD")
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to find the new commit: %v", err), 1)
+				}
+				s.Commits = append(s.Commits, head)
+				s.LastCommit = time.Now()
+				if err := s.Save(); err != nil {
+					return err
+				}
+				if config, err := cfg.Read(); err == nil {
+					if err := rotateCommit(cx.App.ErrWriter, config, s); err != nil {
+						warnf(cx.App.ErrWriter, "unable to hand over the lead: %v", err)
+					}
+				}
+				return nil
+			},
+		},
+	},
+}
+
+// installRepoHooks installs names in the current repository's hooks, or
+// explains how to when lefthook manages them. When core.hooksPath is set
+// globally, the repository's hooks are the global ones, so they're installed
+// as such.
+func installRepoHooks(cx *cli.Context, names []string) error {
+	loc, err := hooks.Locate()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	if loc.Manager == hooks.Lefthook {
+		fmt.Fprintf(cx.App.Writer, "lefthook manages this repository's hooks; add this to lefthook.yml:\n\n%s", hooks.LefthookConfig(names))
+		return nil
+	}
+	if loc.Global {
+		warnf(cx.App.ErrWriter, "core.hooksPath is set globally to %s, so installing the hooks for every repository", loc.Dir)
+		return installGlobalHooks(cx, names)
+	}
+	for _, name := range names {
+		path := filepath.Join(loc.Dir, name)
+		if err := hooks.Install(path, name); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to install %s: %v", name, err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "Installed %s\n", path)
+	}
+	return nil
+}
+
+// checkStale enforces policy.stale before a commit once the pair has
+// expired: warning, refusing the commit, or reverting to just you. Reverting
+// refuses the commit too, since git has already read the pair's identity.
+func checkStale(cx *cli.Context) error {
+	config, err := cfg.Read()
+	if err != nil {
+		// Without a config there's no TTL to enforce.
+		return nil
+	}
+	s, err := session.Current()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+	}
+	why, err := expired(config, s, time.Now())
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	switch {
+	case why == "":
+		return nil
+	case config.OnStale() == cfg.Block:
+		return cli.NewExitError(i18n.Sprintf("error: %s; run pair with to renew it, or pair self", why), 1)
+	case config.OnStale() == cfg.Revert:
+		reverted, err := checkExpired(cx.App.ErrWriter, config, s)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		if reverted == s {
+			// Nobody to revert to, so the commit goes ahead as the pair.
+			return nil
+		}
+		return cli.NewExitError(i18n.T("error: commit again to commit as yourself"), 1)
+	}
+	warnf(cx.App.ErrWriter, "%s; run pair with to renew it, or pair self", why)
+	return nil
+}
+
+// hookInstalled reports whether pair's block is in the named hook of the
+// current repository, or in the user-level hooks directory.
+func hookInstalled(name string) bool {
+	if dir, err := hooks.GlobalPath(); err == nil && hooks.Installed(filepath.Join(dir, name)) {
+		return true
+	}
+	loc, err := hooks.Locate()
+	return err == nil && hooks.Installed(filepath.Join(loc.Dir, name))
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/keeferrourke/pair/audit"
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/hooks"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/tui"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// applyIdentity makes authors the git author, starting a new session for
+// them. reason explains why, when pair changed the pair on its own.
+func applyIdentity(warnings io.Writer, config *cfg.Config, authors []*cfg.Author, reason string) (*session.Session, error) {
+	name, email, err := config.Identity(authors)
+	if err != nil {
+		return nil, err
+	}
+	return startSession(warnings, config, name, email, authors, false, reason)
+}
+
+// applyDriver makes the first of a mob's authors, the driver, the git
+// author, crediting everyone else with trailers whatever the trailer style.
+func applyDriver(warnings io.Writer, config *cfg.Config, authors []*cfg.Author) (*session.Session, error) {
+	name, email, err := config.Identity(authors[:1])
+	if err != nil {
+		return nil, err
+	}
+	return startSession(warnings, config, name, email, authors, true, "")
+}
+
+// setAuthor makes name and email the author of new commits in repo, with the
+// committer according to the attribution setting. Failing to send the change
+// to an audit log sink is only a warning.
+func setAuthor(warnings io.Writer, config *cfg.Config, repo vcs.VCS, name, email string) error {
+	var err error
+	if committer, committerEmail, split := config.Committer(); split {
+		err = repo.SetSplitAuthor(name, email, committer, committerEmail)
+	} else {
+		err = repo.SetAuthor(name, email)
+	}
+	if _, ok := err.(*audit.SinkError); ok {
+		warnf(warnings, "%v", err)
+		return nil
+	}
+	return err
+}
+
+// startSession sets the git author to name and email, and the committer
+// according to the attribution setting, and starts a new session for authors.
+func startSession(warnings io.Writer, config *cfg.Config, name, email string, authors []*cfg.Author, mob bool, reason string) (*session.Session, error) {
+	repo, err := vcs.New(config.Vcs)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := vcs.BackupIdentity(vcs.IdentityFile())
+	if err != nil {
+		return nil, fmt.Errorf("unable to back up git author info changed outside pair: %v", err)
+	}
+	if backup != "" {
+		warnf(warnings, "git author info in %s was changed outside pair; the previous file is backed up to %s", vcs.IdentityFile(), backup)
+	}
+	if err := setAuthor(warnings, config, repo, name, email); err != nil {
+		return nil, err
+	}
+	committer, committerEmail, split := config.Committer()
+	s, err := session.Start(name, email, config.WithEmails(authors))
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" || mob || split {
+		s.Reason = reason
+		s.Mob = mob
+		s.Committer, s.CommitterEmail = committer, committerEmail
+		if err := s.Save(); err != nil {
+			return nil, err
+		}
+	}
+	if err := trailer.UpdateTemplate(s); err != nil {
+		return nil, err
+	}
+	if config.ShowTitle() {
+		tui.SetTitle(tui.PairTitle(s.Aliases(), vcs.Ticket(repo.CurrentBranch())))
+	}
+	if (config.UsesTrailers() || s.Mob) && len(s.CoAuthors()) > 0 && !trailersInstalled() {
+		warnf(warnings, "co-authors are only credited once you run pair hooks install or pair template install")
+	}
+	return s, nil
+}
+
+// trailersInstalled reports whether co-author trailers will be added to
+// commits in the current repository, by a hook or the commit template.
+func trailersInstalled() bool {
+	if _, err := os.Stat(trailer.TemplatePath()); err == nil {
+		return true
+	}
+	if dir, err := hooks.GlobalPath(); err == nil && hooks.Installed(filepath.Join(dir, "prepare-commit-msg")) {
+		return true
+	}
+	loc, err := hooks.Locate()
+	if err != nil {
+		return false
+	}
+	// Hook managers run pair from their own config instead of a block.
+	return loc.Manager != hooks.None || hooks.Installed(filepath.Join(loc.Dir, "prepare-commit-msg"))
+}
+
+// trailersFlag credits co-authors with trailers instead of combining their
+// names, whatever the trailer_style setting.
+var trailersFlag = cli.BoolFlag{
+	Name:  "trailers",
+	Usage: "Stay the author and credit the others with Co-authored-by trailers.",
+}
+
+// strictFlag requires aliases to be given in full, whatever the strict
+// setting.
+var strictFlag = cli.BoolFlag{
+	Name:  "strict",
+	Usage: "Require exact aliases instead of matching a unique prefix of an alias or name.",
+}
+
+// applyTrailersFlag sets the trailer style from --trailers, if given.
+func applyTrailersFlag(cx *cli.Context, config *cfg.Config) {
+	if cx.Bool("trailers") {
+		config.TrailerStyle = cfg.CoAuthor
+	}
+}
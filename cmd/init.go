@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/hooks"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Recommended entries for a pairing-ready repository.
+var (
+	gitignoreEntries     = []string{".pair.local.yml"}
+	gitattributesEntries = []string{cfg.RepoFile + " text eol=lf"}
+)
+
+// Init provides the `pair init` command. Gets a repository ready for pairing:
+// a starter config, hooks, and recommended git settings.
+var Init = cli.Command{
+	Name:  "init",
+	Usage: "Set up the current repository for pairing.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "yes, y",
+			Usage: "Install hooks without asking.",
+		},
+		cli.BoolFlag{
+			Name:  "no-hooks",
+			Usage: "Don't install hooks.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		root := vcs.TopLevel()
+		if root == "" {
+			return cli.NewExitError(i18n.T("error: not inside a git repository"), 1)
+		}
+
+		path := filepath.Join(root, cfg.RepoFile)
+		if _, err := os.Stat(path); err == nil {
+			fmt.Fprintf(cx.App.Writer, "%s already exists\n", path)
+		} else {
+			if err := ioutil.WriteFile(path, []byte(cfg.Starter("git")), 0644); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to write %s: %v", path, err), 1)
+			}
+			fmt.Fprintf(cx.App.Writer, "Created %s\n", path)
+		}
+
+		for _, f := range []struct {
+			name    string
+			entries []string
+		}{{".gitignore", gitignoreEntries}, {".gitattributes", gitattributesEntries}} {
+			added, err := appendMissingLines(filepath.Join(root, f.name), f.entries)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to update %s: %v", f.name, err), 1)
+			}
+			for _, entry := range added {
+				fmt.Fprintf(cx.App.Writer, "Added %s to %s\n", entry, f.name)
+			}
+		}
+
+		if cx.Bool("no-hooks") {
+			return nil
+		}
+		if !cx.Bool("yes") && !confirm(cx, "Install pair's git hooks in this repository?") {
+			return nil
+		}
+		return installRepoHooks(cx, hooks.Names)
+	},
+}
+
+// appendMissingLines appends each of lines not already in the file at path,
+// creating it if necessary, and returns the lines it added.
+func appendMissingLines(path string, lines []string) ([]string, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(buf), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+	text := string(buf)
+	var added []string
+	for _, line := range lines {
+		if existing[line] {
+			continue
+		}
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		text += line + "\n"
+		added = append(added, line)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return added, ioutil.WriteFile(path, []byte(text), 0644)
+}
+
+// confirm asks a yes or no question on stdin, defaulting to no.
+func confirm(cx *cli.Context, question string) bool {
+	fmt.Fprintf(cx.App.Writer, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
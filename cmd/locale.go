@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// localize loads the message catalog for the configured locale from
+// ~/.config/pair/locale and translates the help for app and its commands.
+// Without a catalog everything stays in English. It reads only local config
+// files, since it runs before every command, help included.
+func localize(app *cli.App) {
+	var configured string
+	if config, err := cfg.ReadLocal(); err == nil {
+		configured = config.Locale
+	}
+	catalog, err := i18n.Load(filepath.Join(cfg.ConfigDir(), "locale"), i18n.Locale(configured))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: unable to read message catalog: %v\n", err)
+		return
+	}
+	if catalog == nil {
+		return
+	}
+	i18n.Use(catalog)
+
+	app.Description = i18n.T(app.Description)
+	app.Flags = localizeFlags(app.Flags)
+	localizeCommands(app.Commands)
+}
+
+// localizeCommands translates the help for commands and their subcommands.
+func localizeCommands(commands []cli.Command) {
+	for i := range commands {
+		c := &commands[i]
+		c.Usage = i18n.T(c.Usage)
+		c.Description = i18n.T(c.Description)
+		c.ArgsUsage = i18n.T(c.ArgsUsage)
+		c.Flags = localizeFlags(c.Flags)
+		localizeCommands(c.Subcommands)
+	}
+}
+
+// localizeFlags returns flags with their usage translated. The flags are
+// copied, since they're shared between commands.
+func localizeFlags(flags []cli.Flag) []cli.Flag {
+	localized := make([]cli.Flag, len(flags))
+	for i, flag := range flags {
+		switch f := flag.(type) {
+		case cli.BoolFlag:
+			f.Usage = i18n.T(f.Usage)
+			flag = f
+		case cli.StringFlag:
+			f.Usage = i18n.T(f.Usage)
+			flag = f
+		case cli.StringSliceFlag:
+			f.Usage = i18n.T(f.Usage)
+			flag = f
+		case cli.IntFlag:
+			f.Usage = i18n.T(f.Usage)
+			flag = f
+		case cli.DurationFlag:
+			f.Usage = i18n.T(f.Usage)
+			flag = f
+		}
+		localized[i] = flag
+	}
+	return localized
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Log provides the `pair log` command. Lists every identity change from the
+// session history, with who was pairing, where and for how long, followed by
+// the time spent with each person.
+var Log = cli.Command{
+	Name:  "log",
+	Usage: "Show who you've paired with, when, where and for how long.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "Only show sessions started on or after this date, e.g. 2026-10-01.",
+		},
+		cli.StringSliceFlag{
+			Name:  "with",
+			Usage: "Only show sessions with this alias. Repeatable.",
+		},
+		cli.StringFlag{
+			Name:  "repo",
+			Usage: "Only show sessions in the repository at this path, or . for the current one.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		filter := session.Filter{With: cx.StringSlice("with")}
+		if since := cx.String("since"); since != "" {
+			var err error
+			if filter.Since, err = time.ParseInLocation("2006-01-02", since, time.Local); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: --since must be a date like 2006-01-02, got %s", since), 1)
+			}
+		}
+		if repo := cx.String("repo"); repo == "." {
+			if filter.Repo = vcs.TopLevel(); filter.Repo == "" {
+				return cli.NewExitError(i18n.T("error: --repo . only works inside a repository"), 1)
+			}
+		} else if repo != "" {
+			abs, err := filepath.Abs(repo)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			filter.Repo = abs
+		}
+		sessions, err := session.All()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read session history: %v", err), 1)
+		}
+
+		you := ""
+		if config, err := cfg.Read(); err == nil && config.Author != nil {
+			you = config.Author.Alias
+		}
+		now := time.Now()
+		totals := make(map[string]time.Duration)
+		labels := make(map[string]string)
+		w := table(cx.App.Writer)
+		fmt.Fprintln(w, "STARTED\tDURATION\tPAIR\tREPO\tBRANCH")
+		for _, s := range sessions {
+			if len(s.Authors) == 0 || !filter.Match(s) {
+				continue
+			}
+			d := s.Duration(now)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Started.Format("2006-01-02 15:04"), shortDuration(d),
+				cfg.ComposeLabel(s.Authors), s.Repo, s.Branch)
+			if len(s.Authors) < 2 {
+				continue
+			}
+			for _, a := range s.Authors {
+				if a.Alias != you {
+					totals[a.Alias] += d
+					labels[a.Alias] = a.Label()
+				}
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		if len(totals) == 0 {
+			return nil
+		}
+
+		aliases := make([]string, 0, len(totals))
+		for alias := range totals {
+			aliases = append(aliases, alias)
+		}
+		sort.Slice(aliases, func(i, j int) bool {
+			if totals[aliases[i]] != totals[aliases[j]] {
+				return totals[aliases[i]] > totals[aliases[j]]
+			}
+			return aliases[i] < aliases[j]
+		})
+		fmt.Fprintln(cx.App.Writer)
+		w = table(cx.App.Writer)
+		for _, alias := range aliases {
+			fmt.Fprintf(w, "%s\t%s\n", i18n.Sprintf("With %s (%s)", labels[alias], alias), shortDuration(totals[alias]))
+		}
+		if err := w.Flush(); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		return nil
+	},
+}
+
+// shortDuration formats d to the minute, without trailing seconds. e.g. 2h5m
+func shortDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
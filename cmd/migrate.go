@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Migrate provides the `pair migrate` command. Adds the authors known to
+// another pairing tool to your teammates.
+var Migrate = cli.Command{
+	Name:      "migrate",
+	Usage:     "Add the authors from git-duet or git-together to your teammates.",
+	ArgsUsage: "[<authors file>]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "from",
+			Usage: "The tool to migrate from: git-duet, whose authors file is $GIT_DUET_AUTHORS_FILE or ~/.git-authors unless given, or git-together, from your git config.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		var authors []*cfg.Author
+		switch from := cx.String("from"); from {
+		case "git-duet":
+			path := cx.Args().First()
+			if path == "" {
+				path = cfg.DuetPath()
+			}
+			buf, err := ioutil.ReadFile(path)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read %s: %v", path, err), 1)
+			}
+			if authors, err = cfg.ParseDuet(buf); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %s: %v", path, err), 1)
+			}
+		case "git-together":
+			section, err := vcs.ConfigSection("git-together")
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read git config: %v", err), 1)
+			}
+			if authors, err = cfg.ParseTogether(section); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+		default:
+			return cli.NewExitError(i18n.Sprintf("error: --from must be git-duet or git-together, got %q", from), 1)
+		}
+
+		config, err := cfg.Read()
+		if os.IsNotExist(err) {
+			config, err = cfg.New(cfg.DefaultPath()), nil
+		}
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		added, problems := config.Import(&cfg.Snippet{Authors: authors})
+		for _, p := range problems {
+			warnf(cx.App.ErrWriter, "%v", p)
+		}
+		if err := config.Save(); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to save config: %v", err), 1)
+		}
+		for _, a := range added {
+			fmt.Fprintf(cx.App.Writer, "Added %s (%s) to %s\n", a.Name, a.Alias, config.Path)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Mob provides the `pair mob` commands, for three or more people taking
+// turns at the keyboard. The driver is the git author and everyone else is
+// credited with Co-authored-by trailers, whatever the trailer style.
+var Mob = cli.Command{
+	Name:  "mob",
+	Usage: "Take turns driving with three or more people.",
+	Subcommands: []cli.Command{
+		{
+			Name:      "start",
+			Usage:     "Start mobbing, with the first alias driving. Saves the order to the repository's config.",
+			ArgsUsage: "[<alias>...]",
+			Action: func(cx *cli.Context) error {
+				aliases, err := cfg.SplitAliases(cx.Args(), os.Stdin)
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				config, err := cfg.Read()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+				}
+				loadTeam(cx.App.ErrWriter, config)
+				lookupUnknown(cx.App.ErrWriter, config, aliases)
+				authors, err := config.MobOrder(aliases)
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				if len(aliases) > 0 {
+					if err := saveMobOrder(cx.App.Writer, cx.App.ErrWriter, config, aliases); err != nil {
+						return cli.NewExitError(i18n.Sprintf("error: unable to save the mob order: %v", err), 1)
+					}
+				}
+				warnAway(cx.App.ErrWriter, authors, time.Now())
+				warnOffHours(cx.App.ErrWriter, authors, time.Now())
+				s, err := applyDriver(cx.App.ErrWriter, config, authors)
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				printIdentity(cx.App.Writer, config, s)
+				printTurn(cx.App.Writer, s)
+				return nil
+			},
+		},
+		{
+			Name:  "next",
+			Usage: "Hand over to the next driver.",
+			Action: func(cx *cli.Context) error {
+				s, config, err := currentMob()
+				if err != nil {
+					return err
+				}
+				return nextDriver(cx, config, s)
+			},
+		},
+		{
+			Name:  "done",
+			Usage: "Stop mobbing. It's just you again.",
+			Action: func(cx *cli.Context) error {
+				_, config, err := currentMob()
+				if err != nil {
+					return err
+				}
+				if config.Author == nil {
+					return cli.NewExitError(i18n.T("error: set author in your config first"), 1)
+				}
+				s, err := applyIdentity(cx.App.ErrWriter, config, []*cfg.Author{config.Author}, "")
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				printIdentity(cx.App.Writer, config, s)
+				return nil
+			},
+		},
+	},
+}
+
+// currentMob returns the current session, which must be a mob, and the
+// config.
+func currentMob() (*session.Session, *cfg.Config, error) {
+	s, err := session.Current()
+	if err != nil {
+		return nil, nil, cli.NewExitError(i18n.Sprintf("error: unable to read the current session: %v", err), 1)
+	}
+	if !s.Mob {
+		return nil, nil, cli.NewExitError(i18n.T("error: not mobbing; run pair mob start first"), 1)
+	}
+	config, err := cfg.Read()
+	if err != nil {
+		return nil, nil, cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+	}
+	return s, config, nil
+}
+
+// nextDriver hands the mob in s over to its next driver.
+func nextDriver(cx *cli.Context, config *cfg.Config, s *session.Session) error {
+	s, err := applyDriver(cx.App.ErrWriter, config, s.Rotate())
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	printIdentity(cx.App.Writer, config, s)
+	printTurn(cx.App.Writer, s)
+	return nil
+}
+
+// saveMobOrder sets mob.order in the repository's config to aliases,
+// creating the config if there isn't one yet. Outside a repository there's
+// nowhere to save it, so it's only a warning.
+func saveMobOrder(w, warnings io.Writer, config *cfg.Config, aliases []string) error {
+	repo, err := repoConfig(warnings, config, "the mob order")
+	if repo == nil || err != nil {
+		return err
+	}
+	if repo.Mob != nil && reflect.DeepEqual(repo.Mob.Order, aliases) {
+		return nil
+	}
+	repo.Mob = &cfg.Mob{Order: aliases}
+	if err := repo.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, i18n.Sprintf("Saved the mob order to %s", repo.Path))
+	return nil
+}
+
+// repoConfig returns the repository's config, creating it if there isn't
+// one yet. Outside a repository it warns that what, which would have been
+// saved there, isn't, and returns nil.
+func repoConfig(warnings io.Writer, config *cfg.Config, what string) (*cfg.Config, error) {
+	if config.Repo != nil {
+		return config.Repo, nil
+	}
+	root := vcs.TopLevel()
+	if root == "" {
+		warnf(warnings, "not inside a repository, so %s isn't saved", what)
+		return nil, nil
+	}
+	path := filepath.Join(root, cfg.RepoFile)
+	if err := ioutil.WriteFile(path, []byte(cfg.Starter("git")), 0644); err != nil {
+		return nil, err
+	}
+	repo, err := cfg.NewFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config.Repo = repo
+	return repo, nil
+}
+
+// printTurn prints who's driving and who's navigating a mob.
+func printTurn(w io.Writer, s *session.Session) {
+	fmt.Fprintln(w, i18n.Sprintf("%s is driving and %s is navigating.", s.Authors[0].Label(), s.Authors[1].Label()))
+}
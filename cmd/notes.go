@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Notes provides the `pair notes` command. Records structured pairing
+// metadata as git notes, keeping it out of commit messages.
+var Notes = cli.Command{
+	Name:  "notes",
+	Usage: "Record pairing metadata on commits with git notes.",
+	Subcommands: []cli.Command{
+		{
+			Name:      "add",
+			Usage:     "Attach the current session to a commit (default: HEAD).",
+			ArgsUsage: "[<commit>]",
+			Action: func(cx *cli.Context) error {
+				s, err := session.Current()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+				}
+				if s.ID == "" {
+					return cli.NewExitError(i18n.T("error: no pairing session is active"), 1)
+				}
+				note, err := s.Note(time.Now())
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to describe session: %v", err), 1)
+				}
+				if err := vcs.AddNote(commitArg(cx), string(note)); err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to add note: %v", err), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "show",
+			Usage:     "Print the pairing metadata for a commit (default: HEAD).",
+			ArgsUsage: "[<commit>]",
+			Action: func(cx *cli.Context) error {
+				note, err := vcs.ShowNote(commitArg(cx))
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: no pairing note for %s", commitArg(cx)), 1)
+				}
+				fmt.Fprintln(cx.App.Writer, note)
+				return nil
+			},
+		},
+	},
+}
+
+// commitArg returns the commit named by the first argument, or HEAD.
+func commitArg(cx *cli.Context) string {
+	if cx.NArg() > 0 {
+		return cx.Args().First()
+	}
+	return "HEAD"
+}
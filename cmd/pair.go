@@ -1,23 +1,114 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/clipboard"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/legacy"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/shell"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/tui"
+	"github.com/keeferrourke/pair/vcs"
 	"gopkg.in/urfave/cli.v1"
 )
 
 const version = "0.0.1"
 
+var exportFlag = cli.BoolFlag{
+	Name:  "export",
+	Usage: "Only print shell exports for the identity, for use with eval.",
+}
+
 var (
 	// With provides the `pair with` command. Modifies the VCS author to reflect
 	// the invoker and the other specified authors.
 	With = cli.Command{
-		Name:  "with",
-		Usage: "Pair with another author.",
-		Action: func(cx *cli.Context) {
-			// TODO
-			//vcs.SetAuthor(cfg.Read().Vsc, cfg.Read().Author)
+		Name:      "with",
+		Usage:     "Pair with another author. Without aliases, pick them from a list.",
+		ArgsUsage: "[<alias>...]",
+		Flags: []cli.Flag{exportFlag, shellFlag, lastFlag, trailersFlag, strictFlag,
+			cli.DurationFlag{
+				Name:  "for",
+				Usage: "Expire the pair after this long, e.g. 4h, instead of after session_ttl.",
+			},
+		},
+		Action: func(cx *cli.Context) error {
+			if cx.Bool("strict") {
+				// Both the config and the pairs file go by $PAIR_STRICT.
+				os.Setenv("PAIR_STRICT", "1")
+			}
+			aliases, err := cfg.SplitAliases(cx.Args(), os.Stdin)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			if cx.Bool("last") {
+				last, err := lastPairInRepo()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				aliases = append(aliases, last...)
+			}
+			config, err := cfg.Read()
+			if os.IsNotExist(err) && len(aliases) == 0 {
+				return cli.NewExitError(i18n.T("error: expected at least one alias"), 1)
+			}
+			if os.IsNotExist(err) && cx.Duration("for") != 0 {
+				return cli.NewExitError(i18n.Sprintf("error: --for needs a config file at %s", cfg.DefaultPath()), 1)
+			}
+			if os.IsNotExist(err) && !cx.Bool("export") && !cx.Bool("trailers") {
+				// Without a config, pair from the pairs file as pair always has.
+				return legacyPair(aliases)
+			}
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+			}
+			applyTrailersFlag(cx, config)
+			loadTeam(cx.App.ErrWriter, config)
+			picked := len(aliases) == 0
+			if picked {
+				if aliases, err = pickAliases(cx, config); err != nil {
+					return err
+				}
+			}
+			lookupUnknown(cx.App.ErrWriter, config, aliases)
+			with := config.With
+			if picked {
+				with = config.WithInOrder // Whoever was picked first drives
+			}
+			authors, err := with(aliases)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			warnAway(cx.App.ErrWriter, authors, time.Now())
+			warnOffHours(cx.App.ErrWriter, authors, time.Now())
+			if cx.Bool("export") {
+				return exportAuthors(cx, config, authors)
+			}
+			if config.Rotates(cfg.RotatePair) {
+				if err := rotateLead(cx.App.ErrWriter, config, authors); err != nil {
+					warnf(cx.App.ErrWriter, "unable to save whose turn it is to lead: %v", err)
+				}
+			}
+			s, err := applyIdentity(cx.App.ErrWriter, config, authors, "")
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			if d := cx.Duration("for"); d > 0 {
+				s.Expires = s.Started.Add(d)
+				if err := s.Save(); err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to save the pairing session: %v", err), 1)
+				}
+			}
+			printIdentity(cx.App.Writer, config, s)
+			return nil
 		},
 	}
 	// Self provides the `pair self` command. Modifies the VCS author to reflect
@@ -26,10 +117,24 @@ var (
 		Name:    "self",
 		Aliases: []string{"me"},
 		Usage:   "It's just you.",
-		Action: func(cx *cli.Context) {
-			// TODO
-			//authors := []string{}
-			//vsc.SetAuthor(cfg.Read().Vsc, authors)
+		Flags:   []cli.Flag{exportFlag, shellFlag},
+		Action: func(cx *cli.Context) error {
+			config, err := cfg.Read()
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+			}
+			if config.Author == nil {
+				return cli.NewExitError(i18n.T("error: set author in your config first"), 1)
+			}
+			if cx.Bool("export") {
+				return exportAuthors(cx, config, []*cfg.Author{config.Author})
+			}
+			s, err := applyIdentity(cx.App.ErrWriter, config, []*cfg.Author{config.Author}, "")
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			printIdentity(cx.App.Writer, config, s)
+			return nil
 		},
 	}
 	// WhoAmI provides the `pair whoami` command. Lists who the current author
@@ -37,27 +142,155 @@ var (
 	WhoAmI = cli.Command{
 		Name:  "whoami",
 		Usage: "Who are you anyway?",
-		Action: func(cx *cli.Context) {
-			// TODO
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "copy, c",
+				Usage: "Copy the identity to the clipboard as Name <email>.",
+			},
+			cli.BoolFlag{
+				Name:  "trailers, t",
+				Usage: "Print, or with --copy copy, the Co-authored-by trailers instead.",
+			},
+			cli.StringFlag{
+				Name:  "format, f",
+				Usage: "Output format: text, json, yaml or template.",
+				Value: "text",
+			},
+			cli.StringFlag{
+				Name:  "template",
+				Usage: "Go template for --format template, e.g. '{{join .Aliases \"+\"}}'.",
+			},
+		},
+		Action: func(cx *cli.Context) error {
+			s, err := currentSession()
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read the current session: %v", err), 1)
+			}
+			if len(s.Authors) == 0 {
+				return cli.NewExitError(i18n.T("error: not pairing with anyone; run pair with to start"), 1)
+			}
+			if config, err := cfg.Read(); err == nil {
+				if s, err = checkExpired(cx.App.ErrWriter, config, s); err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+			}
+			if format := cx.String("format"); format != "text" {
+				if err := writeWhoAmI(cx.App.Writer, format, cx.String("template"), s); err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				return nil
+			}
+
+			text := fmt.Sprintf("%s <%s>\n", s.Name, s.Email)
+			if cx.Bool("trailers") {
+				text = strings.Join(trailer.Trailers(s), "\n") + "\n"
+			}
+			if cx.Bool("copy") {
+				if err := clipboard.Copy(text); err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to copy to the clipboard: %v", err), 1)
+				}
+				fmt.Fprintln(cx.App.ErrWriter, i18n.T("Copied to the clipboard."))
+				return nil
+			}
+			if cx.Bool("trailers") {
+				fmt.Fprint(cx.App.Writer, text)
+				return nil
+			}
+			fmt.Fprintf(cx.App.Writer, "%s <%s>\n", paint(cx.App.Writer, tui.Bold, cfg.ComposeLabel(s.Authors)), s.Email)
+			return nil
 		},
 	}
 
 	// Branch provides the `pair branch` command. Changes the VCS branch.
 	// If provided branch name exists, changes to that branch. Otherwise,
-	// a new branch is created prefixed with the author names.
+	// a new branch is created named for the pair by the branch_template.
+	// Also lists and cleans up the pair's branches.
 	Branch = cli.Command{
-		Name:    "branch",
-		Aliases: []string{"b"},
-		Usage:   "Checkout branch.",
+		Name:      "branch",
+		Aliases:   []string{"b"},
+		Usage:     "Checkout branch, or list or clean up the pair's branches.",
+		ArgsUsage: "<branch>",
 		Flags: []cli.Flag{
 			cli.BoolFlag{
 				Name:   "no-prefix",
 				Usage:  "Do not prefix new branch with usernames.",
 				EnvVar: "PAIR_NO_BRANCH_PREFIX",
 			},
+			cli.StringFlag{
+				Name:  "base",
+				Usage: "Start a new branch from this one instead of the default_branch setting or origin/HEAD.",
+			},
+			cli.BoolFlag{
+				Name:  "track",
+				Usage: "Make the base of a new branch its upstream.",
+			},
+			cli.BoolFlag{
+				Name:  "list",
+				Usage: "List the current pair's branches.",
+			},
+			cli.BoolFlag{
+				Name:  "cleanup",
+				Usage: "Delete the current pair's branches which are merged into the base.",
+			},
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "With --cleanup, only print the branches which would be deleted.",
+			},
 		},
-		Action: func(cx *cli.Command) {
-			// TODO
+		Action: func(cx *cli.Context) error {
+			listing := cx.Bool("list") || cx.Bool("cleanup")
+			if listing && cx.NArg() != 0 || !listing && cx.NArg() != 1 {
+				return cli.NewExitError(i18n.T("error: expected a branch name, or --list or --cleanup"), 1)
+			}
+			config, err := cfg.Read()
+			if os.IsNotExist(err) && !listing {
+				if !legacy.Branch(cx.Args().First(), !cx.Bool("no-prefix"), cx.String("base"), cx.Bool("track")) {
+					return cli.NewExitError("", 1)
+				}
+				return nil
+			}
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+			}
+			repo, err := vcs.New(config.Vcs)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			base := cx.String("base")
+			if base == "" {
+				base = config.DefaultBranch
+			}
+			if base == "" {
+				base = repo.DefaultBranch()
+			}
+			authors := branchAuthors(config)
+			if authors == nil && (listing || !cx.Bool("no-prefix")) {
+				return cli.NewExitError(i18n.T("error: set author.alias in your config, or pass --no-prefix"), 1)
+			}
+			if cx.Bool("list") {
+				return listBranches(cx, config, repo, authors)
+			}
+			if cx.Bool("cleanup") {
+				return cleanupBranches(cx, config, repo, authors, base)
+			}
+
+			branch, name := "", cx.Args().First()
+			if config.Tracker != nil {
+				branch, name = ticketBranch(cx, config, repo, authors, name)
+			}
+			if branch == "" {
+				branch = name
+				if !cx.Bool("no-prefix") {
+					if branch, err = config.FormatBranch(authors, name); err != nil {
+						return cli.NewExitError(i18n.Sprintf("error: branch_template: %v", err), 1)
+					}
+				}
+			}
+			if err := repo.Checkout(branch, base, cx.Bool("track")); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Switched to branch '%s'", branch))
+			return nil
 		},
 	}
 	// Config provides the `pair config` command.
@@ -74,9 +307,30 @@ var (
 			{
 				Name:  "dump",
 				Usage: "Dump the current config.",
-				Action: func(cx *cli.Context) {
-					// TODO
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "effective",
+						Usage: "List each setting in effect and where it came from.",
+					},
 				},
+				Action: dumpConfig,
+			},
+			{
+				Name:  "schema",
+				Usage: "Print a JSON Schema for the config, for editor completion and validation.",
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "roster",
+						Usage: "Describe an organization roster instead.",
+					},
+				},
+				Action: printSchema,
+			},
+			{
+				Name:      "validate",
+				Usage:     "Check the config for typos and invalid settings.",
+				ArgsUsage: "[<file>]",
+				Action:    validateConfig,
 			},
 			{
 				Name:  "new",
@@ -89,7 +343,163 @@ var (
 	}
 )
 
-func main() {
+var lastFlag = cli.BoolFlag{
+	Name:  "last",
+	Usage: "Pair with whoever you last paired with in this repository.",
+}
+
+// lastPairInRepo returns the aliases of the last pair set in the current
+// repository.
+func lastPairInRepo() ([]string, error) {
+	repo := vcs.TopLevel()
+	if repo == "" {
+		return nil, errors.New("--last only works inside a repository")
+	}
+	sessions, err := session.All()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read session history: %v", err)
+	}
+	last := session.LastIn(sessions, repo)
+	if last == nil {
+		return nil, fmt.Errorf("nobody has paired in %s yet", repo)
+	}
+	var aliases []string
+	for _, a := range last.Authors {
+		aliases = append(aliases, a.Alias)
+	}
+	return aliases, nil
+}
+
+// restoreLast makes the last pair in this repository the git author again,
+// unless they already are, and prints the git author.
+func restoreLast(cx *cli.Context, config *cfg.Config) error {
+	repo, err := vcs.New(config.Vcs)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	name, email, err := repo.GetAuthor()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to get current git author: %v", err), 1)
+	}
+	if aliases, err := lastPairInRepo(); err == nil {
+		authors, err := config.With(aliases)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		_, lastEmail, err := config.Identity(authors)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		if lastEmail != email {
+			s, err := applyIdentity(cx.App.ErrWriter, config, authors, "")
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			printIdentity(cx.App.Writer, config, s)
+			return nil
+		}
+	}
+	fmt.Fprintf(cx.App.Writer, "%s <%s>\n", name, email)
+	return nil
+}
+
+// warnAway warns about any of authors marked as away, who probably didn't
+// mean to be paired with.
+func warnAway(w io.Writer, authors []*cfg.Author, now time.Time) {
+	for _, a := range authors {
+		if !a.AwayOn(now) {
+			continue
+		}
+		if a.Until != "" {
+			warnf(w, "%s (%s) is away until %s", a.Name, a.Alias, a.Until)
+		} else {
+			warnf(w, "%s (%s) is marked away", a.Name, a.Alias)
+		}
+	}
+}
+
+// warnOffHours shows the local time of authors in other timezones, warning
+// about any who are well outside their working hours.
+func warnOffHours(w io.Writer, authors []*cfg.Author, now time.Time) {
+	for _, a := range authors {
+		if a.Timezone == "" {
+			continue
+		}
+		local := a.LocalTime(now)
+		_, theirs := local.Zone()
+		_, yours := now.Zone()
+		if a.OffHours(now) {
+			warnf(w, "it's %s for %s (%s), outside their working hours", local.Format("15:04 MST"), a.Name, a.Alias)
+		} else if theirs != yours {
+			fmt.Fprintf(w, "It's %s for %s (%s)\n", local.Format("15:04 MST"), a.Name, a.Alias)
+		}
+	}
+}
+
+// printIdentity prints the git author set for s, followed by the
+// Co-authored-by trailers crediting everyone else when the trailer or email
+// style, or a mob, calls for them.
+func printIdentity(w io.Writer, config *cfg.Config, s *session.Session) {
+	fmt.Fprintf(w, "%s <%s>\n", s.Name, s.Email)
+	if !config.UsesTrailers() && !s.Mob {
+		return
+	}
+	for _, t := range trailer.Trailers(s) {
+		fmt.Fprintln(w, t)
+	}
+}
+
+// exportAuthors prints shell exports for the identity composed of authors,
+// and nothing else, so the output can be passed straight to eval.
+func exportAuthors(cx *cli.Context, config *cfg.Config, authors []*cfg.Author) error {
+	name, email, err := config.Identity(authors)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	s := &session.Session{Name: name, Email: email}
+	s.Committer, s.CommitterEmail, _ = config.Committer()
+	exports, err := shell.Exports(shellName(cx), s.Environment())
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	fmt.Fprint(cx.App.Writer, exports)
+	if config.ShowTitle() {
+		s.Authors = authors
+		tui.SetTitle(tui.PairTitle(s.Aliases(), vcs.Ticket(vcs.CurrentBranch())))
+	}
+	return nil
+}
+
+// quickCommands are the commands prompts and the daemon run over and over,
+// and help, which read only local config files before they start, so they
+// never wait on a remote pairs file.
+var quickCommands = map[string]bool{
+	Status.Name: true,
+	Daemon.Name: true,
+	"serve":     true,
+	"help":      true,
+	"h":         true,
+}
+
+// isQuick reports whether args, the command and its arguments, run one of
+// the quickCommands or only ask for a command's help.
+func isQuick(args cli.Args) bool {
+	if quickCommands[args.First()] {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "-h" || arg == "--help" {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs pair with the command line args, including the program name. The
+// original invocations keep working: `pair USER1 USER2` is `pair with USER1
+// USER2`, `pair -b BRANCH` is `pair branch BRANCH` and `pair` alone prints
+// the current pair.
+func Run(args []string) error {
 	cli.VersionPrinter = func(cx *cli.Context) {
 		fmt.Fprintf(cx.App.Writer, "%s %s - %s",
 			cx.App.Name, cx.App.Version, cx.App.Description)
@@ -101,6 +511,33 @@ func main() {
 Configures your VCS (default: git) author name to reflect multiple authors.
 Based on Square's pair utility.`
 	app.Version = version
+	app.ErrWriter = os.Stderr
+	app.UsageText = `pair [global options] command [command options] [arguments...]
+   pair USER1 [USER2...]
+   pair -b BRANCH`
+	app.CustomAppHelpTemplate = cli.AppHelpTemplate + "\n" + legacy.Environment
+
+	app.Flags = []cli.Flag{noColorFlag, plainFlag}
+	app.Before = func(cx *cli.Context) error {
+		plain = cx.GlobalBool("plain")
+		noColor = plain || cx.GlobalBool("no-color")
+		quiet = plain
+		read := cfg.Read
+		if isQuick(cx.Args()) {
+			read = cfg.ReadLocal
+		}
+		config, err := read()
+		if err != nil {
+			return nil
+		}
+		if cx.Args().First() != Config.Name {
+			warnConfig(cx.App.ErrWriter, config)
+		}
+		if config.Git != nil {
+			vcs.Configure(config.Git.Path, config.Git.Args)
+		}
+		return nil
+	}
 
 	app.Commands = []cli.Command{
 		With,
@@ -108,10 +545,141 @@ Based on Square's pair utility.`
 		WhoAmI,
 		Branch,
 		Config,
+		Template,
+		SquashMsg,
+		Doctor,
+		Verify,
+		Check,
+		Notes,
+		PR,
+		Hooks,
+		Hook,
+		Report,
+		Exec,
+		Direnv,
+		Env,
+		InitShell,
+		Add,
+		Remove,
+		List,
+		Team,
+		Signers,
+		Whois,
+		Init,
+		Share,
+		Import,
+		Migrate,
+		Audit,
+		Status,
+		Idle,
+		Guess,
+		Remind,
+		CI,
+		Emails,
+		Auth,
+		Daemon,
+		Mob,
+		Timer,
+		Log,
+		Stats,
+		Undo,
 	}
 	app.CommandNotFound = func(c *cli.Context, command string) {
-		fmt.Fprintf(c.App.Writer, "Did you read the manual? %s isn't in it.\n", command)
+		fmt.Fprintln(c.App.Writer, i18n.Sprintf("Did you read the manual? %s isn't in it.", command))
 	}
+	app.Action = func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if os.IsNotExist(err) {
+			// Without a config, restore from the pairs file as pair always has.
+			if !legacy.Current() {
+				return cli.NewExitError("", 1)
+			}
+			return nil
+		}
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		return restoreLast(cx, config)
+	}
+	localize(app)
+
+	return app.Run(legacyArgs(app, args, isRosterAlias))
+}
 
-	app.Run(os.Args)
+// legacyArgs rewrites the original flag-based invocations of pair into
+// commands: `pair -b BRANCH` becomes `pair branch BRANCH`, and aliases given
+// without a command become `pair with ALIASES`. An alias which isAlias
+// reports is in the roster wins over a command of the same name, with a
+// warning, so pairing with someone called log keeps working; the prompt
+// commands and help are never taken for aliases. Anything else is returned
+// as is.
+func legacyArgs(app *cli.App, args []string, isAlias func(string) bool) []string {
+	if len(args) < 2 {
+		return args
+	}
+	i := 1
+	for i < len(args) && isGlobalFlag(app, args[i]) {
+		i++
+	}
+	if i == len(args) {
+		return args
+	}
+	rewritten := append([]string{}, args[:i]...)
+	switch arg := args[i]; {
+	case arg == "-b" || arg == "--b":
+		rewritten = append(rewritten, Branch.Name)
+	case strings.HasPrefix(arg, "-b=") || strings.HasPrefix(arg, "--b="):
+		rewritten = append(rewritten, Branch.Name, arg[strings.Index(arg, "=")+1:])
+	case arg != "-" && strings.HasPrefix(arg, "-"), quickCommands[arg]:
+		return args
+	case app.Command(arg) != nil && !isAlias(arg):
+		return args
+	case app.Command(arg) != nil:
+		warnf(app.ErrWriter, "%s is both an alias and a command; pairing with %s as pair always has. Rename the alias to run pair %s", arg, arg, arg)
+		rewritten = append(rewritten, With.Name, arg)
+	default:
+		rewritten = append(rewritten, With.Name, arg)
+	}
+	return append(rewritten, args[i+1:]...)
+}
+
+// isRosterAlias reports whether name is an alias or group in your config, or
+// in your pairs files. It reads only local files, using the cached copy of a
+// remote pairs file, since it's asked before most commands.
+func isRosterAlias(name string) bool {
+	if config, err := cfg.ReadLocal(); err == nil && config.HasAlias(name) {
+		return true
+	}
+	var paths []string
+	for _, path := range cfg.LegacyPaths() {
+		if cfg.IsRemote(path) {
+			path = cfg.TeamCachePath(path)
+		}
+		paths = append(paths, path)
+	}
+	authors, _, _ := cfg.ReadLegacyFiles(paths)
+	_, ok := authors[name]
+	return ok
+}
+
+// isGlobalFlag reports whether arg is one of app's global boolean flags.
+func isGlobalFlag(app *cli.App, arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if name == arg || name == "" {
+		return false
+	}
+	for _, f := range app.Flags {
+		if f.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyPair sets the git author to aliases from the pairs file.
+func legacyPair(aliases []string) error {
+	if !legacy.Pair(aliases) {
+		return cli.NewExitError("", 1)
+	}
+	return nil
 }
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// slowPairsFile points $PAIR_FILE at a server which takes delay to answer,
+// with nothing cached, in a temporary HOME with a config file. Call the
+// returned func to clean up.
+func slowPairsFile(t *testing.T, delay time.Duration) func() {
+	dir, err := ioutil.TempDir("", "pair-cmd")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write([]byte("---\nmb: Michael Bluth\n"))
+	}))
+	env := map[string]string{
+		"HOME":            dir,
+		"XDG_CONFIG_HOME": filepath.Join(dir, "config"),
+		"XDG_DATA_HOME":   filepath.Join(dir, "data"),
+		"XDG_CACHE_HOME":  filepath.Join(dir, "cache"),
+		"XDG_RUNTIME_DIR": filepath.Join(dir, "run"),
+		"PAIR_CONFIG":     filepath.Join(dir, "config.yml"),
+		"PAIR_FILE":       server.URL + "/pairs.yml",
+	}
+	ioutil.WriteFile(env["PAIR_CONFIG"], []byte("vcs: git\nauthor:\n  name: Michael Bluth\n  alias: mb\n  email: mb@example.com\n"), 0644)
+	saved := map[string]string{}
+	for k, v := range env {
+		saved[k] = os.Getenv(k)
+		os.Setenv(k, v)
+	}
+	return func() {
+		for k, v := range saved {
+			os.Setenv(k, v)
+		}
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStatusSkipsRemotePairsFile(t *testing.T) {
+	defer slowPairsFile(t, 2*time.Second)()
+
+	start := time.Now()
+	if err := Run([]string{"pair", "status", "--porcelain"}); err != nil {
+		t.Fatalf("error running pair status: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected pair status not to wait on the pairs file, took %v", elapsed)
+	}
+}
+
+func TestHelpSkipsRemotePairsFile(t *testing.T) {
+	defer slowPairsFile(t, 2*time.Second)()
+
+	for _, args := range [][]string{{"pair", "--help"}, {"pair", "help"}, {"pair", "with", "--help"}} {
+		start := time.Now()
+		if err := Run(args); err != nil {
+			t.Fatalf("error running %v: %v", args, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("expected %v not to wait on the pairs file, took %v", args, elapsed)
+		}
+	}
+}
+
+func TestLegacyArgs(t *testing.T) {
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{plainFlag}
+	app.Commands = []cli.Command{With, Branch, Status, Log}
+	app.ErrWriter = ioutil.Discard
+	roster := map[string]bool{"lb": true, "log": true, "status": true}
+	isAlias := func(name string) bool { return roster[name] }
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"pair"}, []string{"pair"}},
+		{[]string{"pair", "lb", "mb"}, []string{"pair", "with", "lb", "mb"}},
+		{[]string{"pair", "--plain", "lb"}, []string{"pair", "--plain", "with", "lb"}},
+		{[]string{"pair", "-b", "fix"}, []string{"pair", "branch", "fix"}},
+		{[]string{"pair", "-b=fix"}, []string{"pair", "branch", "fix"}},
+		{[]string{"pair", "branch", "fix"}, []string{"pair", "branch", "fix"}},
+		{[]string{"pair", "log", "mb"}, []string{"pair", "with", "log", "mb"}},
+		{[]string{"pair", "status"}, []string{"pair", "status"}},
+		{[]string{"pair", "help"}, []string{"pair", "help"}},
+	}
+	for _, tt := range tests {
+		if got := legacyArgs(app, tt.args, isAlias); strings.Join(got, " ") != strings.Join(tt.expected, " ") {
+			t.Errorf("expected %v to become %v, got %v", tt.args, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// plainFlag asks for stable, line-oriented output that works well with screen
+// readers and in captured logs: no colors, spinners or padded columns.
+var plainFlag = cli.BoolFlag{
+	Name:   "plain",
+	Usage:  "Print plain, line-oriented output without colors, spinners or aligned columns.",
+	EnvVar: "PAIR_PLAIN",
+}
+
+// plain is set by the global --plain flag.
+var plain bool
+
+// tableWriter is where tables are written. Flush it when done.
+type tableWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// table returns a writer that aligns tab-separated columns written to w. In
+// plain mode, the columns are left separated by a single tab instead.
+func table(w io.Writer) tableWriter {
+	if plain {
+		return plainTable{w}
+	}
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
+
+// plainTable writes table rows through unchanged.
+type plainTable struct {
+	io.Writer
+}
+
+func (plainTable) Flush() error {
+	return nil
+}
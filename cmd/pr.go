@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/keeferrourke/pair/auth"
+	"github.com/keeferrourke/pair/compliance"
+	"github.com/keeferrourke/pair/github"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// PR provides the `pair pr` command. Integrates pairing information with
+// GitHub pull requests.
+var PR = cli.Command{
+	Name:  "pr",
+	Usage: "Work with pull requests.",
+	Subcommands: []cli.Command{
+		{
+			Name:      "annotate",
+			Usage:     "Add or refresh a pairing summary in a pull request description.",
+			ArgsUsage: "[<number>]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "remote",
+					Usage: "Git remote pointing at the GitHub repository.",
+					Value: "origin",
+				},
+			},
+			Action: annotatePR,
+		},
+	},
+}
+
+func annotatePR(cx *cli.Context) error {
+	remote := cx.String("remote")
+	url, err := vcs.Git("remote", "get-url", remote)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to find remote %s", remote), 1)
+	}
+	owner, repo, err := github.ParseRemote(url)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+
+	client := github.NewClient(auth.Token("github"))
+	spinner := progress(cx.App.ErrWriter, i18n.T("Finding the pull request"))
+	var pr *github.PullRequest
+	if cx.NArg() > 0 {
+		number, err := strconv.Atoi(cx.Args().First())
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: invalid pull request number: %s", cx.Args().First()), 1)
+		}
+		pr, err = client.PullRequest(owner, repo, number)
+	} else {
+		var branch string
+		branch, err = vcs.Git("rev-parse", "--abbrev-ref", "HEAD")
+		if err == nil {
+			pr, err = client.PullRequestForBranch(owner, repo, branch)
+		}
+	}
+	spinner.Stop()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to find pull request: %v", err), 1)
+	}
+
+	revRange := remote + "/" + pr.Base.Ref + "..HEAD"
+	commits, err := vcs.Commits(revRange)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read commits in %s: %v", revRange, err), 1)
+	}
+	report := compliance.Analyze(revRange, commits)
+	for _, c := range report.Commits {
+		var note session.Note
+		if raw, err := vcs.ShowNote(c.Hash); err == nil && json.Unmarshal([]byte(raw), &note) == nil {
+			c.Driver = note.Driver
+		}
+	}
+
+	var summary strings.Builder
+	if err := report.WriteMarkdown(&summary); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to write summary: %v", err), 1)
+	}
+	body := github.ReplaceSection(pr.Body, summary.String())
+	spinner = progress(cx.App.ErrWriter, i18n.Sprintf("Updating #%d", pr.Number))
+	err = client.EditPullRequestBody(owner, repo, pr.Number, body)
+	spinner.Stop()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to update pull request: %v", err), 1)
+	}
+	fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Annotated #%d: %d of %d commits paired", pr.Number, report.Paired, report.Total))
+	return nil
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/keeferrourke/pair/tui"
+)
+
+// quiet suppresses progress spinners, such as while producing machine-readable
+// output.
+var quiet bool
+
+// progress starts a spinner on w for a slow network operation, unless w isn't
+// a terminal or output is quiet. Stop it before writing anything else to w.
+func progress(w io.Writer, message string) *tui.Spinner {
+	if quiet || !tui.IsTerminal(w) {
+		return nil
+	}
+	return tui.StartSpinner(w, message)
+}
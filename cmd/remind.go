@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/auth"
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/notify"
+	"github.com/keeferrourke/pair/remind"
+	"github.com/keeferrourke/pair/session"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Remind provides the `pair remind` command. Reminds the pair to rotate
+// drivers, with a desktop notification and, if a webhook is configured, a
+// Slack message. `pair remind install` has the system run it periodically.
+var Remind = cli.Command{
+	Name:  "remind",
+	Usage: "Remind the pair to rotate drivers.",
+	Subcommands: []cli.Command{
+		{
+			Name:  "install",
+			Usage: "Remind the pair periodically, using launchd or a systemd user timer.",
+			Flags: []cli.Flag{
+				cli.DurationFlag{
+					Name:  "every",
+					Usage: "How often to remind.",
+					Value: 2 * time.Hour,
+				},
+			},
+			Action: func(cx *cli.Context) error {
+				exe, err := os.Executable()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to find the pair executable: %v", err), 1)
+				}
+				units, err := remind.Install(runtime.GOOS, cx.Duration("every"), exe)
+				for _, u := range units {
+					fmt.Fprintf(cx.App.Writer, "Wrote %s\n", u.Path)
+				}
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to schedule reminders: %v", err), 1)
+				}
+				fmt.Fprintf(cx.App.Writer, "Reminding every %s\n", cx.Duration("every"))
+				return nil
+			},
+		},
+		{
+			Name:  "uninstall",
+			Usage: "Stop periodic reminders.",
+			Action: func(cx *cli.Context) error {
+				units, err := remind.Uninstall(runtime.GOOS)
+				for _, u := range units {
+					fmt.Fprintf(cx.App.Writer, "Removed %s\n", u.Path)
+				}
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to remove reminders: %v", err), 1)
+				}
+				return nil
+			},
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		s, err := session.Current()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+		}
+		if len(s.Authors) < 2 {
+			return nil
+		}
+		message := fmt.Sprintf("%s, you've been pairing since %s. Time to rotate drivers? Run pair status to check who's pairing.",
+			cfg.ComposeLabel(s.Authors), s.Started.Format("15:04"))
+		fmt.Fprintln(cx.App.Writer, message)
+		if err := remind.Notify(runtime.GOOS, message); err != nil {
+			warnf(cx.App.ErrWriter, "unable to show a notification: %v", err)
+		}
+		if webhook := slackWebhook(); webhook != "" {
+			if err := notify.Slack(webhook, message); err != nil {
+				warnf(cx.App.ErrWriter, "unable to post to Slack: %v", err)
+			}
+		}
+		return nil
+	},
+}
+
+// slackWebhook returns the webhook reminders are posted to: the configured
+// one, or else a webhook stored with pair auth set slack.
+func slackWebhook() string {
+	if config, err := cfg.Read(); err == nil && config.Webhook() != "" {
+		return config.Webhook()
+	}
+	if token := auth.Token("slack"); strings.HasPrefix(token, "https://") {
+		return token
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/notify"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/stats"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Report provides the `pair report` command. Lists pairing sessions and the
+// commits each one produced, as logged by the post-commit hook.
+var Report = cli.Command{
+	Name:  "report",
+	Usage: "Show pairing sessions and their commits.",
+	Subcommands: []cli.Command{
+		{
+			Name:  "html",
+			Usage: "Write a self-contained HTML page with the pairing matrix, totals and a timeline.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "out, o",
+					Usage: "File to write, or - for standard output.",
+					Value: "-",
+				},
+				cli.StringFlag{
+					Name:  "since",
+					Usage: "Only include sessions started on or after this date, e.g. 2026-10-01.",
+				},
+				cli.StringFlag{
+					Name:  "until",
+					Usage: "Only include sessions started before this date.",
+				},
+			},
+			Action: reportHTML,
+		},
+		{
+			Name:  "digest",
+			Usage: "Send a summary of the last week's pairing by email or webhook, or print it.",
+			Flags: []cli.Flag{
+				cli.StringSliceFlag{
+					Name:  "email",
+					Usage: "Address to email the digest to, over the SMTP server at $PAIR_SMTP_URL. Repeatable.",
+				},
+				cli.StringFlag{
+					Name:   "webhook",
+					Usage:  "Slack-compatible webhook to post the digest to.",
+					EnvVar: "PAIR_DIGEST_WEBHOOK",
+				},
+				cli.StringFlag{
+					Name:  "since",
+					Usage: "Start of the digest, e.g. 2026-10-01. Defaults to a week ago.",
+				},
+				cli.StringFlag{
+					Name:  "until",
+					Usage: "End of the digest. Defaults to now.",
+				},
+			},
+			Action: reportDigest,
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		sessions, err := session.All()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read session history: %v", err), 1)
+		}
+
+		for _, s := range sessions {
+			name := s.Name
+			if len(s.Authors) > 0 {
+				name = cfg.ComposeLabel(s.Authors)
+			}
+			fmt.Fprintf(cx.App.Writer, "%s  %s  %s\n", s.Started.Format("2006-01-02 15:04"), s.ID, name)
+			for _, commit := range s.Commits {
+				fmt.Fprintf(cx.App.Writer, "    %s\n", commit)
+			}
+		}
+		return nil
+	},
+}
+
+func reportHTML(cx *cli.Context) error {
+	from, to, err := reportRange(cx)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	sessions, err := session.All()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read session history: %v", err), 1)
+	}
+	now := time.Now()
+	summary := stats.Summarize(sessions, from, to, now)
+
+	title := "Pairing report"
+	if !from.IsZero() {
+		title += " since " + from.Format("2006-01-02")
+	}
+	out := cx.String("out")
+	if out == "-" {
+		return summary.WriteHTML(cx.App.Writer, title, now)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to write report: %v", err), 1)
+	}
+	if err := summary.WriteHTML(f, title, now); err != nil {
+		f.Close()
+		return cli.NewExitError(i18n.Sprintf("error: unable to write report: %v", err), 1)
+	}
+	if err := f.Close(); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to write report: %v", err), 1)
+	}
+	fmt.Fprintf(cx.App.ErrWriter, "Wrote %s\n", out)
+	return nil
+}
+
+func reportDigest(cx *cli.Context) error {
+	from, to, err := reportRange(cx)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	now := time.Now()
+	weekFrom, weekTo := stats.Week(now)
+	if from.IsZero() {
+		from = weekFrom
+	}
+	if to.IsZero() {
+		to = weekTo
+	}
+	sessions, err := session.All()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read session history: %v", err), 1)
+	}
+	var digest strings.Builder
+	if err := stats.Summarize(sessions, from, to, now).WriteDigest(&digest); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to write report: %v", err), 1)
+	}
+
+	emails, webhook := cx.StringSlice("email"), cx.String("webhook")
+	if len(emails) == 0 && webhook == "" {
+		fmt.Fprint(cx.App.Writer, digest.String())
+		return nil
+	}
+	if len(emails) > 0 {
+		server, err := notify.SMTPFromEnv()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		subject := fmt.Sprintf("Pairing digest for %s to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		if err := server.Email(emails, subject, digest.String()); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to send email: %v", err), 1)
+		}
+		fmt.Fprintf(cx.App.ErrWriter, "Emailed %s\n", strings.Join(emails, ", "))
+	}
+	if webhook != "" {
+		if err := notify.Slack(webhook, digest.String()); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to post to the webhook: %v", err), 1)
+		}
+		fmt.Fprintln(cx.App.ErrWriter, i18n.T("Posted to the webhook"))
+	}
+	return nil
+}
+
+// reportRange parses the --since and --until dates, either of which may be
+// empty for an unbounded range.
+func reportRange(cx *cli.Context) (from, to time.Time, err error) {
+	if since := cx.String("since"); since != "" {
+		if from, err = time.ParseInLocation("2006-01-02", since, time.Local); err != nil {
+			return from, to, fmt.Errorf("--since must be a date like 2006-01-02, got %s", since)
+		}
+	}
+	if until := cx.String("until"); until != "" {
+		if to, err = time.ParseInLocation("2006-01-02", until, time.Local); err != nil {
+			return from, to, fmt.Errorf("--until must be a date like 2006-01-02, got %s", until)
+		}
+	}
+	return from, to, nil
+}
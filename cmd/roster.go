@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/keeferrourke/pair/auth"
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/directory"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/tui"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Add provides the `pair add` command. Adds a teammate to the roster: the
+// config's teammates, or the legacy pairs file if that's all there is. With
+// --update, changes the name or email of someone already in it.
+var Add = cli.Command{
+	Name:      "add",
+	Usage:     "Add a teammate to the roster, or with --update change one.",
+	ArgsUsage: "<alias> [<name> [<email>]]",
+	Flags: []cli.Flag{
+		cli.StringFlag{Name: "name", Usage: "The teammate's full name, e.g. \"Lindsay Bluth\"."},
+		cli.StringFlag{Name: "email", Usage: "The teammate's own email; derived from $PAIR_EMAIL if unset."},
+		cli.BoolFlag{Name: "update", Usage: "Change the name or email of a teammate already in the roster."},
+	},
+	Action: func(cx *cli.Context) error {
+		if cx.NArg() < 1 || cx.NArg() > 3 {
+			return cli.NewExitError(i18n.T("error: expected an alias, a name and optionally an email"), 1)
+		}
+		a := &cfg.Author{
+			Alias: cx.Args().Get(0),
+			Name:  cx.Args().Get(1),
+			Email: cx.Args().Get(2),
+		}
+		if name := cx.String("name"); name != "" {
+			a.Name = name
+		}
+		if email := cx.String("email"); email != "" {
+			a.Email = email
+		}
+		if cx.Bool("update") {
+			return updateTeammate(cx, a)
+		}
+
+		if useLegacyRoster() {
+			path := cfg.LegacyPath()
+			if cfg.IsRemote(path) {
+				return cli.NewExitError(i18n.Sprintf("error: %s is a remote pairs file; change it at its source", path), 1)
+			}
+			authors, err := cfg.ReadLegacy(path)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read authors from file (%s): %v", path, err), 1)
+			}
+			if err := cfg.AddLegacy(authors, a.Alias, a.Name); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			if a.Email != "" {
+				warnf(cx.App.ErrWriter, "%s has no room for emails; %s will be derived from $PAIR_EMAIL", path, a.Alias)
+			}
+			if err := cfg.WriteLegacy(path, authors); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to write %s: %v", path, err), 1)
+			}
+			fmt.Fprintf(cx.App.Writer, "Added %s (%s) to %s\n", a.Name, a.Alias, path)
+			return nil
+		}
+
+		config, err := cfg.Read()
+		if os.IsNotExist(err) {
+			config, err = cfg.New(cfg.DefaultPath()), nil
+		}
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		if err := config.AddTeammate(a); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		if err := config.Save(); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to save config: %v", err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "Added %s (%s) to %s\n", a.Name, a.Alias, config.Path)
+		return nil
+	},
+}
+
+// updateTeammate changes the name or email of the teammate with a's alias to
+// a's, where given.
+func updateTeammate(cx *cli.Context, a *cfg.Author) error {
+	if a.Name == "" && a.Email == "" {
+		return cli.NewExitError(i18n.T("error: expected a name or email to update"), 1)
+	}
+	if useLegacyRoster() {
+		path := cfg.LegacyPath()
+		if cfg.IsRemote(path) {
+			return cli.NewExitError(i18n.Sprintf("error: %s is a remote pairs file; change it at its source", path), 1)
+		}
+		authors, err := cfg.ReadLegacy(path)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read authors from file (%s): %v", path, err), 1)
+		}
+		if _, ok := authors[a.Alias]; !ok {
+			return cli.NewExitError(i18n.Sprintf("error: no such username: %s", a.Alias), 1)
+		}
+		if a.Email != "" {
+			return cli.NewExitError(i18n.Sprintf("error: %s has no room for emails; %s's is derived from $PAIR_EMAIL", path, a.Alias), 1)
+		}
+		authors[a.Alias] = a.Name
+		if err := cfg.WriteLegacy(path, authors); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to write %s: %v", path, err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "Updated %s (%s) in %s\n", a.Name, a.Alias, path)
+		return nil
+	}
+
+	config, err := cfg.Read()
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+	}
+	updated, err := config.UpdateTeammate(a.Alias, a.Name, a.Email)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	if err := config.Save(); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to save config: %v", err), 1)
+	}
+	fmt.Fprintf(cx.App.Writer, "Updated %s (%s) in %s\n", updated.Name, updated.Alias, config.Path)
+	return nil
+}
+
+// useLegacyRoster reports whether the roster lives in the legacy pairs file,
+// which is only the case when there is no config file but a pairs file, or
+// a remote one.
+func useLegacyRoster() bool {
+	if _, err := os.Stat(cfg.DefaultPath()); err == nil {
+		return false
+	}
+	if cfg.IsRemote(cfg.LegacyPath()) {
+		return true
+	}
+	_, err := os.Stat(cfg.LegacyPath())
+	return err == nil
+}
+
+// Remove provides the `pair remove` command. Removes a departed teammate from
+// the roster and from any groups they belonged to.
+var Remove = cli.Command{
+	Name:      "remove",
+	Aliases:   []string{"rm"},
+	Usage:     "Remove a teammate from the roster.",
+	ArgsUsage: "<alias>",
+	Action: func(cx *cli.Context) error {
+		if cx.NArg() != 1 {
+			return cli.NewExitError(i18n.T("error: expected an alias"), 1)
+		}
+		alias := cx.Args().First()
+
+		var path string
+		if useLegacyRoster() {
+			path = cfg.LegacyPath()
+			if cfg.IsRemote(path) {
+				return cli.NewExitError(i18n.Sprintf("error: %s is a remote pairs file; change it at its source", path), 1)
+			}
+			authors, err := cfg.ReadLegacy(path)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read authors from file (%s): %v", path, err), 1)
+			}
+			if _, ok := authors[alias]; !ok {
+				return cli.NewExitError(i18n.Sprintf("error: no such username: %s", alias), 1)
+			}
+			delete(authors, alias)
+			if err := cfg.WriteLegacy(path, authors); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to write %s: %v", path, err), 1)
+			}
+		} else {
+			config, err := cfg.Read()
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+			}
+			if _, err := config.RemoveTeammate(alias); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+			if err := config.Save(); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to save config: %v", err), 1)
+			}
+			path = config.Path
+		}
+		fmt.Fprintf(cx.App.Writer, "Removed %s from %s\n", alias, path)
+
+		if s, err := session.Current(); err == nil {
+			for _, a := range s.Authors {
+				if a.Alias == alias {
+					warnf(cx.App.ErrWriter, "%s is part of the active pair %s; run pair to change it", alias, s.Name)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// loadRoster returns everyone in the roster, you first, from the config or
+// the legacy pairs files. Conflicts between pairs files are reported to
+// warnings.
+func loadRoster(warnings io.Writer) ([]*cfg.Author, error) {
+	if useLegacyRoster() {
+		authors, conflicts, err := cfg.ReadLegacyFiles(cfg.LegacyPaths())
+		if _, ok := err.(*cfg.StaleTeamError); ok {
+			warnf(warnings, "pairs file: %v", err)
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range conflicts {
+			warnf(warnings, "%v", c)
+		}
+		spinner := progress(warnings, i18n.T("Fetching the team roster"))
+		err = cfg.MergeLegacyTeam(authors)
+		spinner.Stop()
+		if err != nil {
+			warnf(warnings, "team roster: %v", err)
+		}
+		var roster []*cfg.Author
+		for alias, name := range authors {
+			roster = append(roster, &cfg.Author{Alias: alias, Name: name})
+		}
+		sort.Sort(cfg.ByName(roster))
+		return roster, nil
+	}
+	config, err := cfg.Read()
+	if err != nil {
+		return nil, err
+	}
+	loadTeam(warnings, config)
+	roster := config.Roster()
+	sort.Sort(cfg.ByName(roster))
+	if config.Author != nil {
+		roster = append([]*cfg.Author{config.Author}, roster...)
+	}
+	return roster, nil
+}
+
+// loadTeam loads the organization roster into config, warning rather than
+// failing when it can't be fetched so local teammates still work.
+func loadTeam(warnings io.Writer, config *cfg.Config) {
+	spinner := progress(warnings, i18n.T("Fetching the team roster"))
+	err := config.LoadTeam()
+	spinner.Stop()
+	if err != nil {
+		warnf(warnings, "team roster: %v", err)
+	}
+}
+
+// lookupUnknown looks up aliases nobody in config has in the directory, if
+// one is set, adding whoever it finds to your teammates so they're known
+// next time. Failures are only warnings; resolving the aliases reports
+// anyone still unknown.
+func lookupUnknown(warnings io.Writer, config *cfg.Config, aliases []string) {
+	if config.Directory == nil {
+		return
+	}
+	var d directory.Directory
+	added := false
+	for _, alias := range aliases {
+		if _, err := config.Resolve([]string{alias}); err == nil {
+			continue
+		}
+		if d == nil {
+			var err error
+			if d, err = directory.New(config.Directory, auth.Token("ldap")); err != nil {
+				warnf(warnings, "directory: %v", err)
+				return
+			}
+		}
+		spinner := progress(warnings, i18n.Sprintf("Looking up %s in the directory", alias))
+		a, err := d.Lookup(alias)
+		spinner.Stop()
+		if err == directory.ErrNotFound {
+			continue
+		}
+		if err == nil {
+			err = config.AddTeammate(a)
+		}
+		if err != nil {
+			warnf(warnings, "directory: %s: %v", alias, err)
+			continue
+		}
+		added = true
+	}
+	if added {
+		if err := config.Save(); err != nil {
+			warnf(warnings, "unable to save teammates from the directory: %v", err)
+		}
+	}
+}
+
+// rosterEntry is a teammate as shown by `pair list`. Serializes to JSON.
+type rosterEntry struct {
+	Alias      string `json:"alias"`
+	Name       string `json:"name"`
+	Label      string `json:"-"`
+	Pronouns   string `json:"pronouns,omitempty"`
+	Display    string `json:"display_name,omitempty"`
+	Email      string `json:"email,omitempty"`
+	LastPaired string `json:"last_paired,omitempty"`
+}
+
+// List provides the `pair list` command. Lists everyone in the roster along
+// with when you last paired with them.
+var List = cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls"},
+	Usage:     "List teammates in the roster.",
+	ArgsUsage: "[<filter>]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Usage: "Output format: table, json or names.",
+			Value: "table",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		quiet = quiet || cx.String("format") != "table"
+		roster, err := loadRoster(cx.App.ErrWriter)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read roster: %v", err), 1)
+		}
+		sessions, err := session.All()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read session history: %v", err), 1)
+		}
+		last := session.LastPaired(sessions)
+
+		filter := strings.ToLower(cx.Args().First())
+		var entries []*rosterEntry
+		for _, a := range roster {
+			haystack := strings.ToLower(a.Alias + "\n" + a.Name + "\n" + a.DisplayName + "\n" + a.Email)
+			if !strings.Contains(haystack, filter) {
+				continue
+			}
+			e := &rosterEntry{Alias: a.Alias, Name: a.Name, Label: a.Label(), Pronouns: a.Pronouns, Display: a.DisplayName, Email: a.Email}
+			if t, ok := last[a.Alias]; ok {
+				e.LastPaired = t.Format("2006-01-02")
+			}
+			entries = append(entries, e)
+		}
+
+		switch cx.String("format") {
+		case "table":
+			// Highlight whoever you're pairing with right now.
+			pairing := make(map[string]bool)
+			if s, err := session.Current(); err == nil {
+				for _, a := range s.Authors {
+					pairing[a.Alias] = true
+				}
+			}
+			out := cx.App.Writer
+			w := table(out)
+			fmt.Fprintf(w, "%s\t%s\tEMAIL\tLAST PAIRED\n", cell(out, false, "ALIAS"), cell(out, false, "NAME"))
+			for _, e := range entries {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", cell(out, pairing[e.Alias], e.Alias), cell(out, pairing[e.Alias], e.Label), e.Email, e.LastPaired)
+			}
+			return w.Flush()
+		case "json":
+			if entries == nil {
+				entries = []*rosterEntry{}
+			}
+			enc := json.NewEncoder(cx.App.Writer)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		case "names":
+			for _, e := range entries {
+				fmt.Fprintln(cx.App.Writer, e.Name)
+			}
+			return nil
+		default:
+			return cli.NewExitError(i18n.Sprintf("error: unknown format: %s", cx.String("format")), 1)
+		}
+	},
+}
+
+// pickAliases asks who to pair with, in a full-screen picker on a terminal
+// or with a plain prompt otherwise, returning their aliases in the order
+// picked.
+func pickAliases(cx *cli.Context, config *cfg.Config) ([]string, error) {
+	t, err := tui.Open()
+	var aliases []string
+	if err == tui.ErrNotTerminal {
+		aliases, err = tui.PickPlain(os.Stdin, cx.App.ErrWriter, config.Author, config.Roster())
+	} else if err == nil {
+		aliases, err = tui.NewPicker(config.Author, config.Roster()).Run(t)
+		t.Close()
+	}
+	if err == tui.ErrCancelled {
+		return nil, cli.NewExitError(i18n.T("error: expected at least one alias"), 1)
+	}
+	if err != nil {
+		return nil, cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	return aliases, nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/vcs"
+)
+
+// rotateLead hands the lead of config's rotation over to the next of
+// authors, saving whose turn it is to the repository's config. A pair of
+// one has nobody to hand over to.
+func rotateLead(warnings io.Writer, config *cfg.Config, authors []*cfg.Author) error {
+	if config.Rotation == nil || len(authors) < 2 {
+		return nil
+	}
+	lead := config.NextLead(authors)
+	config.Rotation.Lead = lead
+	repo, err := repoConfig(warnings, config, "whose turn it is to lead")
+	if repo == nil || err != nil {
+		return err
+	}
+	if repo.Rotation == nil {
+		repo.Rotation = &cfg.Rotation{}
+	}
+	repo.Rotation.Lead = lead
+	return repo.Save()
+}
+
+// rotateCommit hands the lead over after a commit when the rotation says to,
+// setting the git author for the next commit without starting a new session.
+func rotateCommit(warnings io.Writer, config *cfg.Config, s *session.Session) error {
+	if !config.Rotates(cfg.RotateCommit) || s.ID == "" || s.Mob || len(s.Authors) < 2 {
+		return nil
+	}
+	if err := rotateLead(warnings, config, s.Authors); err != nil {
+		return err
+	}
+	name, email, err := config.Identity(s.Authors)
+	if err != nil {
+		return err
+	}
+	repo, err := vcs.New(config.Vcs)
+	if err != nil {
+		return err
+	}
+	if err := setAuthor(warnings, config, repo, name, email); err != nil {
+		return err
+	}
+	s.Name, s.Email = name, email
+	if err := s.Save(); err != nil {
+		return err
+	}
+	return trailer.UpdateTemplate(s)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Share provides the `pair share` command. Prints a one-line snippet holding
+// your roster entry, a teammate's, or a whole group's, for `pair import`.
+var Share = cli.Command{
+	Name:      "share",
+	Usage:     "Print a snippet a teammate can pass to pair import.",
+	ArgsUsage: "[<alias or group>]",
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		snippet := &cfg.Snippet{}
+		name := cx.Args().First()
+		switch {
+		case name == "":
+			if config.Author == nil {
+				return cli.NewExitError(i18n.T("error: author can't be nil"), 1)
+			}
+			snippet.Authors = []*cfg.Author{config.Author}
+		case config.Groups[name] != nil:
+			snippet.Group = name
+			if snippet.Authors, err = config.Resolve(config.Groups[name]); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+		default:
+			if snippet.Authors, err = config.Resolve([]string{name}); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+		}
+		text, err := snippet.Encode()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		fmt.Fprintln(cx.App.Writer, text)
+		return nil
+	},
+}
+
+// Import provides the `pair import` command. Adds the teammates in a snippet
+// from `pair share` to the roster.
+var Import = cli.Command{
+	Name:      "import",
+	Usage:     "Add teammates from a pair share snippet.",
+	ArgsUsage: "[<snippet>]",
+	Action: func(cx *cli.Context) error {
+		text := cx.Args().First()
+		if text == "" {
+			buf, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read snippet: %v", err), 1)
+			}
+			text = string(buf)
+		}
+		snippet, err := cfg.DecodeSnippet(text)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+
+		config, err := cfg.Read()
+		if os.IsNotExist(err) {
+			config, err = cfg.New(cfg.DefaultPath()), nil
+		}
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		added, problems := config.Import(snippet)
+		for _, p := range problems {
+			warnf(cx.App.ErrWriter, "%v", p)
+		}
+		if err := config.Save(); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to save config: %v", err), 1)
+		}
+		for _, a := range added {
+			fmt.Fprintf(cx.App.Writer, "Added %s (%s) to %s\n", a.Name, a.Alias, config.Path)
+		}
+		if snippet.Group != "" {
+			fmt.Fprintf(cx.App.Writer, "Updated group %s\n", snippet.Group)
+		}
+		return nil
+	},
+}
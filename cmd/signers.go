@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/internal/block"
+	"github.com/keeferrourke/pair/signing"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Signers provides the `pair signers` command. Generates ssh allowed signers
+// entries from the SSH signing keys recorded in the roster.
+var Signers = cli.Command{
+	Name:  "signers",
+	Usage: "Print allowed signers for the SSH signing keys in the roster.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "write, w",
+			Usage: "Update the block pair manages in gpg.ssh.allowedSignersFile instead of printing.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		loadTeam(cx.App.ErrWriter, config)
+		keys := rosterSigningKeys(config)
+		if len(keys) == 0 {
+			warnf(cx.App.ErrWriter, "nobody in the roster has an SSH signingkey")
+		}
+		signers := signing.AllowedSigners(keys)
+		if !cx.Bool("write") {
+			fmt.Fprint(cx.App.Writer, signers)
+			return nil
+		}
+
+		path := vcs.ConfigValue("gpg.ssh.allowedSignersFile")
+		if path == "" {
+			return cli.NewExitError(i18n.T("error: gpg.ssh.allowedSignersFile is not set"), 1)
+		}
+		if strings.HasPrefix(path, "~/") {
+			path = filepath.Join(os.Getenv("HOME"), path[2:])
+		}
+		existing, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read %s: %v", path, err), 1)
+		}
+		if err := ioutil.WriteFile(path, []byte(block.Replace(string(existing), signers)), 0644); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to write %s: %v", path, err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "Updated %d allowed signers in %s\n", len(keys), path)
+		return nil
+	},
+}
+
+// rosterSigningKeys maps the email of everyone in the roster with an SSH
+// signingkey to that key. Teammates without an email of their own are listed
+// under the email derived for them.
+func rosterSigningKeys(config *cfg.Config) map[string]string {
+	template, _ := config.EmailTemplate()
+	keys := make(map[string]string)
+	everyone := config.Roster()
+	if config.Author != nil {
+		everyone = append([]*cfg.Author{config.Author}, everyone...)
+	}
+	for _, a := range everyone {
+		if !signing.IsSSHKey(a.SigningKey) {
+			continue
+		}
+		email, err := cfg.ComposeEmail(template, []*cfg.Author{a})
+		if err != nil {
+			continue
+		}
+		keys[email] = a.SigningKey
+	}
+	return keys
+}
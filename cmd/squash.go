@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// SquashMsg provides the `pair squash-msg` command. Prints a squash commit
+// message body that preserves the co-authors of the squashed commits.
+var SquashMsg = cli.Command{
+	Name:      "squash-msg",
+	Usage:     "Print a squash commit message preserving co-authors.",
+	ArgsUsage: "<base>..<head>",
+	Action: func(cx *cli.Context) error {
+		if cx.NArg() != 1 {
+			return cli.NewExitError(i18n.T("error: expected a single revision range, e.g. main..HEAD"), 1)
+		}
+		messages, err := vcs.Messages(cx.Args().First())
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read commits in %s: %v", cx.Args().First(), err), 1)
+		}
+		fmt.Fprint(cx.App.Writer, trailer.SquashMessage(messages))
+		return nil
+	},
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/stats"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Stats provides the `pair stats` command. Shows how many commits each two
+// people made together and when they last paired, from the git history or
+// the session history, and who never paired at all.
+var Stats = cli.Command{
+	Name:      "stats",
+	Usage:     "Show who paired with whom, from git history, and who never has.",
+	ArgsUsage: "[<revision range>]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "sessions",
+			Usage: "Count your session history, with time spent, instead of git history.",
+		},
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "Only count commits or sessions on or after this date, e.g. 2026-10-01.",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "Only count commits or sessions before this date.",
+		},
+		cli.StringFlag{
+			Name:  "format, f",
+			Usage: "Output format: text, json or csv.",
+			Value: "text",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		from, to, err := reportRange(cx)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+
+		var summary *stats.Summary
+		if cx.Bool("sessions") {
+			sessions, err := session.All()
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read session history: %v", err), 1)
+			}
+			summary = stats.Summarize(sessions, from, to, time.Now())
+		} else {
+			revRange := "HEAD"
+			if cx.NArg() > 0 {
+				revRange = cx.Args().First()
+			}
+			commits, err := vcs.Commits(revRange)
+			if err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: unable to read commits in %s: %v", revRange, err), 1)
+			}
+			var credited []*stats.Commit
+			for _, c := range commits {
+				if authors := config.Recognize(commitIdentities(c)); len(authors) > 0 {
+					credited = append(credited, &stats.Commit{Authors: authors, Time: c.Time})
+				}
+			}
+			summary = stats.FromCommits(credited, from, to)
+		}
+
+		var team []string
+		if config.Author != nil {
+			team = append(team, config.Author.Alias)
+		}
+		for _, a := range config.Roster() {
+			team = append(team, a.Alias)
+		}
+		unpaired := summary.Unpaired(team)
+
+		w := cx.App.Writer
+		switch format := cx.String("format"); format {
+		case "json":
+			err = summary.WriteJSON(w, unpaired)
+		case "csv":
+			err = summary.WriteCSV(w, unpaired)
+		case "text":
+			err = writeStats(cx, summary, unpaired)
+		default:
+			return cli.NewExitError(i18n.Sprintf("error: unknown format %s; use text, json or csv", format), 1)
+		}
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		return nil
+	},
+}
+
+// writeStats writes the pairing matrix as a table, followed by everyone who
+// never paired.
+func writeStats(cx *cli.Context, summary *stats.Summary, unpaired []*stats.Pairing) error {
+	w := table(cx.App.Writer)
+	if cx.Bool("sessions") {
+		fmt.Fprintln(w, "PAIR\tCOMMITS\tTIME\tLAST PAIRED")
+	} else {
+		fmt.Fprintln(w, "PAIR\tCOMMITS\tLAST PAIRED")
+	}
+	for _, p := range summary.Pairings() {
+		if cx.Bool("sessions") {
+			fmt.Fprintf(w, "%s+%s\t%d\t%s\t%s\n", p.A, p.B, p.Commits, shortDuration(p.Duration), p.Last.Format("2006-01-02"))
+		} else {
+			fmt.Fprintf(w, "%s+%s\t%d\t%s\n", p.A, p.B, p.Commits, p.Last.Format("2006-01-02"))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(unpaired) == 0 {
+		return nil
+	}
+	fmt.Fprintln(cx.App.Writer)
+	fmt.Fprintln(cx.App.Writer, i18n.T("Never paired:"))
+	for _, p := range unpaired {
+		fmt.Fprintf(cx.App.Writer, "  %s+%s\n", p.A, p.B)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/service"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/tui"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Status provides the `pair status` command. Shows the current pairing
+// session, including whether pair ended the previous one on its own. With
+// --porcelain or --json, summarizes it for prompts instead.
+var Status = cli.Command{
+	Name:  "status",
+	Usage: "Show the current pairing session.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "porcelain",
+			Usage: "Print a single stable line for prompts: the pair, the session's age and ok or expired, e.g. lb+mb 1h05m ok. Empty when not pairing.",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "Like --porcelain, but as JSON.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		if cx.Bool("porcelain") || cx.Bool("json") {
+			return writeSummary(cx.App.Writer, cx.Bool("json"))
+		}
+		s, err := currentSession()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+		}
+		if s.ID == "" {
+			fmt.Fprintln(cx.App.Writer, i18n.T("Not pairing."))
+			return nil
+		}
+		now := time.Now()
+		w := cx.App.Writer
+		fmt.Fprintf(w, "%s <%s>\n", paint(w, tui.Bold, s.Name), s.Email)
+		fmt.Fprintf(w, "%s %s (%s ago), %d commits\n", paint(w, tui.Faint, "Started"),
+			s.Started.Format("2006-01-02 15:04"), now.Sub(s.Started).Round(time.Minute), len(s.Commits))
+		if config, err := cfg.Read(); err == nil {
+			if ttl, err := config.TTL(); err == nil && len(s.Authors) > 1 && !s.Deadline(ttl).IsZero() {
+				deadline := s.Deadline(ttl)
+				when := "in " + deadline.Sub(now).Round(time.Minute).String()
+				if now.After(deadline) {
+					when = now.Sub(deadline).Round(time.Minute).String() + " ago"
+				}
+				fmt.Fprintf(w, "%s %s (%s)\n", paint(w, tui.Faint, "Expires"), deadline.Format("2006-01-02 15:04"), when)
+			}
+		}
+		if s.Reason != "" {
+			fmt.Fprintf(w, "%s %s\n", paint(w, tui.Yellow, "Note:"), s.Reason)
+		}
+		return nil
+	},
+}
+
+// writeSummary writes the prompt summary of the session to w, as a line or
+// JSON. It asks pair daemon, or else reads only local files, and never runs
+// git, so prompts can call it on every render. Without a readable config
+// there's no session_ttl, but the pair can still expire when it was set with
+// --for.
+func writeSummary(w io.Writer, asJSON bool) error {
+	sum, err := service.CurrentSummary(service.SocketPath())
+	if err != nil {
+		s, err := session.Current()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+		}
+		var ttl time.Duration
+		if config, err := cfg.ReadLocal(); err == nil {
+			ttl, _ = config.TTL()
+		}
+		sum = s.Summarize(ttl, time.Now())
+	}
+	if !asJSON {
+		fmt.Fprintln(w, sum)
+		return nil
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(sum); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+	}
+	return nil
+}
+
+// Idle provides the `pair idle` command. Reverts to just you once the pair
+// has gone idle_timeout without a commit logged by the post-commit hook, or
+// has expired and policy.stale is revert. An expired pair is otherwise marked
+// in the prompt state file. Run it in the background, e.g. from cron or a
+// prompt hook.
+var Idle = cli.Command{
+	Name:  "idle",
+	Usage: "Revert to just you if the pair has been idle too long, or has expired.",
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		if config.Author == nil {
+			return cli.NewExitError(i18n.T("error: set author in your config first"), 1)
+		}
+		reason, err := revertIfDue(cx.App.ErrWriter, config, time.Now())
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		if reason != "" {
+			fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Reverted to %s: %s", config.Author.Name, reason))
+		}
+		return nil
+	},
+}
+
+// revertIfDue reverts to just you once the pair has gone idle_timeout
+// without a commit, or has expired and policy.stale is revert, returning
+// why. An expired pair is otherwise marked in the prompt state file.
+func revertIfDue(warnings io.Writer, config *cfg.Config, now time.Time) (string, error) {
+	timeout, err := config.Idle()
+	if err != nil {
+		return "", err
+	}
+	s, err := session.Current()
+	if err != nil {
+		return "", fmt.Errorf("unable to read pairing session: %v", err)
+	}
+	why, err := expired(config, s, now)
+	if err != nil {
+		return "", err
+	}
+	var reason string
+	switch {
+	case why != "" && config.OnStale() == cfg.Revert:
+		reason = fmt.Sprintf("unpaired from %s at %s when it expired", s.Name, now.Format("2006-01-02 15:04"))
+	case timeout != 0 && len(s.Authors) > 1 && s.Idle(now) >= timeout:
+		reason = fmt.Sprintf("unpaired from %s at %s after %s without commits", s.Name, now.Format("2006-01-02 15:04"), timeout)
+	case why != "":
+		if err := session.WriteExpiredState(session.StatePath(), s); err != nil {
+			return "", fmt.Errorf("unable to update the prompt state: %v", err)
+		}
+		return "", nil
+	default:
+		return "", nil
+	}
+	if config.Author == nil {
+		return "", errors.New("set author in your config to revert to yourself")
+	}
+	if _, err := applyIdentity(warnings, config, []*cfg.Author{config.Author}, reason); err != nil {
+		return "", fmt.Errorf("unable to revert to yourself: %v", err)
+	}
+	return reason, nil
+}
+
+// expired describes how the pair in s has outlived its pair with --for, or
+// session_ttl, or returns "" while it hasn't. Just you never expires.
+func expired(config *cfg.Config, s *session.Session, now time.Time) (string, error) {
+	ttl, err := config.TTL()
+	if err != nil {
+		return "", err
+	}
+	if len(s.Authors) < 2 || !s.Stale(ttl, now) {
+		return "", nil
+	}
+	return fmt.Sprintf("pair %s expired %s ago", s.Name, now.Sub(s.Deadline(ttl)).Round(time.Minute)), nil
+}
+
+// checkExpired warns when the pair in s has expired, or with policy.stale
+// set to revert, reverts to just you, if there's an author in the config to
+// revert to. It returns the session in effect afterwards.
+func checkExpired(warnings io.Writer, config *cfg.Config, s *session.Session) (*session.Session, error) {
+	now := time.Now()
+	why, err := expired(config, s, now)
+	if err != nil || why == "" {
+		return s, err
+	}
+	if config.OnStale() != cfg.Revert {
+		warnf(warnings, "%s; run pair with to renew it, or pair self", why)
+		return s, nil
+	}
+	if config.Author == nil {
+		warnf(warnings, "%s; set author in your config to revert to yourself", why)
+		return s, nil
+	}
+	reason := fmt.Sprintf("unpaired from %s at %s when it expired", s.Name, now.Format("2006-01-02 15:04"))
+	reverted, err := applyIdentity(warnings, config, []*cfg.Author{config.Author}, reason)
+	if err != nil {
+		return nil, fmt.Errorf("unable to revert to yourself: %v", err)
+	}
+	warnf(warnings, "%s; reverted to %s", why, config.Author.Name)
+	return reverted, nil
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keeferrourke/pair/auth"
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/github"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/tui"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Team provides the `pair team` command. Manages the roster of teammates.
+var Team = cli.Command{
+	Name:    "team",
+	Aliases: []string{"teammates"},
+	Usage:   "Manage the roster of teammates.",
+	Subcommands: []cli.Command{
+		{
+			Name:  "sync",
+			Usage: "Add the members of a GitHub organization to your teammates, with their noreply emails.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "github-org",
+					Usage: "The organization, e.g. bluth.",
+				},
+				cli.BoolFlag{
+					Name:  "refresh",
+					Usage: "Fetch the members again instead of using the cached copy.",
+				},
+			},
+			Action: syncTeam,
+		},
+		{
+			Name:   "refresh",
+			Usage:  "Fetch remote pairs files and the organization roster again, rather than waiting for the cached copies to expire.",
+			Action: refreshTeam,
+		},
+		{
+			Name:      "import",
+			Usage:     "Propose teammates from the authors of commits, to confirm or edit before adding them.",
+			ArgsUsage: "[<repo>...]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "from-log",
+					Usage: "Propose the authors in the git history of the repositories, the current one by default.",
+				},
+				cli.BoolFlag{
+					Name:  "yes, y",
+					Usage: "Add everyone proposed without asking.",
+				},
+			},
+			Action: importFromLog,
+		},
+		{
+			Name:  "edit",
+			Usage: "Edit the roster in a full-screen table.",
+			Action: func(cx *cli.Context) error {
+				config, err := cfg.Read()
+				if os.IsNotExist(err) {
+					config, err = cfg.New(cfg.DefaultPath()), nil
+				}
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+				}
+
+				t, err := tui.Open()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: pair team edit needs a terminal: %v", err), 1)
+				}
+				editor := tui.NewRosterEditor(config)
+				err = editor.Run(t, func() error {
+					editor.Apply(config)
+					return config.Save()
+				})
+				t.Close()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "search",
+			Usage:     "Find teammates by name, alias or email.",
+			ArgsUsage: "<pattern>",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "regexp, E",
+					Usage: "Treat the pattern as a regular expression.",
+				},
+			},
+			Action: func(cx *cli.Context) error {
+				if cx.NArg() != 1 {
+					return cli.NewExitError(i18n.T("error: expected a pattern"), 1)
+				}
+				roster, err := loadRoster(cx.App.ErrWriter)
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read roster: %v", err), 1)
+				}
+				found, err := cfg.Search(roster, cx.Args().First(), cx.Bool("regexp"))
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+				}
+				if len(found) == 0 {
+					return cli.NewExitError("", 1)
+				}
+				w := table(cx.App.Writer)
+				for _, a := range found {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", a.Alias, a.Label(), a.Email)
+				}
+				return w.Flush()
+			},
+		},
+	},
+}
+
+func syncTeam(cx *cli.Context) error {
+	org := cx.String("github-org")
+	if org == "" {
+		return cli.NewExitError(i18n.T("error: expected --github-org"), 1)
+	}
+	config, err := cfg.Read()
+	if os.IsNotExist(err) {
+		config, err = cfg.New(cfg.DefaultPath()), nil
+	}
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+	}
+	members, err := githubMembers(cx, org, cx.Bool("refresh"))
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to list the members of %s: %v", org, err), 1)
+	}
+	authors := make([]*cfg.Author, len(members))
+	for i, m := range members {
+		name := m.Name
+		if name == "" {
+			name = m.Login
+		}
+		authors[i] = &cfg.Author{Name: name, Alias: strings.ToLower(m.Login), Email: m.NoreplyEmail(), GitHub: m.Login, GitHubID: m.ID}
+	}
+	added, updated := config.SyncTeammates(authors)
+	if err := config.Save(); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to save config: %v", err), 1)
+	}
+	fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Added %d and updated %d of the %d members of %s", added, updated, len(members), org))
+	return nil
+}
+
+func importFromLog(cx *cli.Context) error {
+	if !cx.Bool("from-log") {
+		return cli.NewExitError(i18n.T("error: expected --from-log"), 1)
+	}
+	repos := []string(cx.Args())
+	if len(repos) == 0 {
+		repos = []string{"."}
+	}
+	var contributors []*cfg.Contributor
+	for _, repo := range repos {
+		output, err := vcs.Shortlog(repo)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read the history of %s: %v", repo, err), 1)
+		}
+		contributors = append(contributors, cfg.ParseShortlog(output)...)
+	}
+
+	config, err := cfg.Read()
+	if os.IsNotExist(err) {
+		config, err = cfg.New(cfg.DefaultPath()), nil
+	}
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+	}
+	proposed := config.ProposeTeammates(contributors)
+	if len(proposed) == 0 {
+		fmt.Fprintln(cx.App.Writer, i18n.T("Everyone in the history is already in the roster"))
+		return nil
+	}
+
+	var added []*cfg.Author
+	answers := bufio.NewReader(os.Stdin)
+	for _, a := range proposed {
+		if !cx.Bool("yes") {
+			fmt.Fprintf(cx.App.Writer, "Add %s <%s> as %s? [Y/n, or another alias and name] ", a.Name, a.Email, a.Alias)
+			answer, err := answers.ReadString('\n')
+			if err != nil && answer == "" {
+				fmt.Fprintln(cx.App.Writer)
+				break
+			}
+			if !editProposal(a, answer) {
+				continue
+			}
+		}
+		if err := config.AddTeammate(a); err != nil {
+			warnf(cx.App.ErrWriter, "%v", err)
+			continue
+		}
+		added = append(added, a)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	if err := config.Save(); err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to save config: %v", err), 1)
+	}
+	for _, a := range added {
+		fmt.Fprintf(cx.App.Writer, "Added %s (%s) to %s\n", a.Name, a.Alias, config.Path)
+	}
+	return nil
+}
+
+// editProposal applies an answer to whether to add a proposed teammate:
+// empty or yes to add them as proposed, no to skip them, or an alias,
+// optionally followed by a name, to add them under those instead. It reports
+// whether to add them.
+func editProposal(a *cfg.Author, answer string) bool {
+	fields := strings.Fields(answer)
+	if len(fields) == 0 {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	a.Alias = fields[0]
+	if len(fields) > 1 {
+		a.Name = strings.Join(fields[1:], " ")
+	}
+	return true
+}
+
+func refreshTeam(cx *cli.Context) error {
+	var urls []string
+	for _, path := range cfg.LegacyPaths() {
+		if cfg.IsRemote(path) {
+			urls = append(urls, path)
+		}
+	}
+	failed := 0
+	for _, url := range urls {
+		spinner := progress(cx.App.ErrWriter, i18n.Sprintf("Fetching %s", url))
+		authors, err := cfg.FetchLegacy(url, true)
+		spinner.Stop()
+		if stale, ok := err.(*cfg.StaleTeamError); ok {
+			err = stale.Err // The cached copy is no refresh
+		}
+		if err != nil {
+			warnf(cx.App.ErrWriter, "%s: %v", url, err)
+			failed++
+			continue
+		}
+		fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Fetched %d teammates from %s", len(authors), url))
+	}
+
+	teamURL := os.Getenv("PAIR_TEAM_URL")
+	if config, err := cfg.Read(); err == nil {
+		teamURL = config.TeamURL()
+	}
+	if teamURL != "" {
+		spinner := progress(cx.App.ErrWriter, i18n.T("Fetching the team roster"))
+		team, err := cfg.FetchTeam(teamURL)
+		spinner.Stop()
+		if stale, ok := err.(*cfg.StaleTeamError); ok {
+			err = stale.Err
+		}
+		if err != nil {
+			warnf(cx.App.ErrWriter, "team roster: %v", err)
+			failed++
+		} else {
+			fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Fetched %d teammates from %s", len(team), teamURL))
+		}
+		urls = append(urls, teamURL)
+	}
+
+	if len(urls) == 0 {
+		return cli.NewExitError(i18n.T("error: nothing to refresh; neither PAIR_FILE nor the team roster is a URL"), 1)
+	}
+	if failed > 0 {
+		return cli.NewExitError(i18n.Sprintf("error: unable to fetch %d of %d rosters", failed, len(urls)), 1)
+	}
+	return nil
+}
+
+// githubMembers returns the members of the GitHub organization org, from the
+// cache unless refresh is set or there's nothing cached yet.
+func githubMembers(cx *cli.Context, org string, refresh bool) ([]*github.User, error) {
+	cache := filepath.Join(cfg.CacheDir(), "github", strings.ToLower(org)+".json")
+	if !refresh {
+		if buf, err := ioutil.ReadFile(cache); err == nil {
+			var members []*github.User
+			if err := json.Unmarshal(buf, &members); err == nil {
+				return members, nil
+			}
+		}
+	}
+	spinner := progress(cx.App.ErrWriter, i18n.Sprintf("Fetching the members of %s", org))
+	members, err := github.NewClient(auth.Token("github")).OrgMembers(org)
+	spinner.Stop()
+	if err != nil {
+		return nil, err
+	}
+	if buf, err := json.Marshal(members); err == nil && os.MkdirAll(filepath.Dir(cache), 0755) == nil {
+		ioutil.WriteFile(cache, buf, 0644)
+	}
+	return members, nil
+}
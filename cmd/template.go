@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Template provides the `pair template` command. Manages a commit message
+// template carrying Co-authored-by trailers for the current pair.
+var Template = cli.Command{
+	Name:  "template",
+	Usage: "Manage the co-author commit template.",
+	Subcommands: []cli.Command{
+		{
+			Name:  "install",
+			Usage: "Write the commit template and register it as commit.template.",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "global, g",
+					Usage: "Register the template globally instead of for this repository.",
+				},
+			},
+			Action: func(cx *cli.Context) error {
+				s, err := session.Current()
+				if err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to read pairing session: %v", err), 1)
+				}
+				path := trailer.TemplatePath()
+				if err := trailer.WriteTemplate(path, s); err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to write commit template: %v", err), 1)
+				}
+
+				scope := "--local"
+				if cx.Bool("global") || !vcs.InRepo() {
+					scope = "--global"
+				}
+				if _, err := vcs.Git("config", scope, "commit.template", path); err != nil {
+					return cli.NewExitError(i18n.Sprintf("error: unable to set commit.template: %v", err), 1)
+				}
+				fmt.Fprintln(cx.App.Writer, i18n.Sprintf("Installed commit template %s (%s)", path, scope[2:]))
+				return nil
+			},
+		},
+	},
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/remind"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/timer"
+	"github.com/keeferrourke/pair/tui"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Timer provides the `pair timer` command. Counts down the current driver's
+// turn and shows a desktop notification when it's time to swap.
+var Timer = cli.Command{
+	Name:      "timer",
+	Usage:     "Count down the driver's turn, with a notification when it's time to swap.",
+	ArgsUsage: "[<duration>]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "rotate",
+			Usage: "When the time's up, hand over to the next driver of the mob, as pair mob next does.",
+		},
+	},
+	Action: func(cx *cli.Context) error {
+		turn := 15 * time.Minute
+		if cx.NArg() > 0 {
+			d, err := time.ParseDuration(cx.Args().First())
+			if err != nil || d <= 0 {
+				return cli.NewExitError(i18n.Sprintf("error: expected a duration like 15m, got %s", cx.Args().First()), 1)
+			}
+			turn = d
+		}
+		s, err := session.Current()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read the current session: %v", err), 1)
+		}
+		if len(s.Authors) == 0 {
+			return cli.NewExitError(i18n.T("error: not pairing with anyone; run pair with to start"), 1)
+		}
+		if cx.Bool("rotate") && !s.Mob {
+			return cli.NewExitError(i18n.T("error: --rotate only works in a mob; run pair mob start first"), 1)
+		}
+
+		w := cx.App.Writer
+		driver := s.Authors[0]
+		fmt.Fprintln(w, i18n.Sprintf("%s is driving for %s.", driver.Label(), turn))
+		t := &timer.Timer{Duration: turn}
+		var tick func(time.Duration)
+		if tui.IsTerminal(w) {
+			t.Tick = time.Second
+			tick = func(left time.Duration) {
+				fmt.Fprintf(w, "\r%s ", timer.Format(left))
+			}
+		}
+		stop := make(chan struct{})
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		defer signal.Stop(interrupt)
+		go func() {
+			<-interrupt
+			close(stop)
+		}()
+		up := t.Run(stop, tick)
+		if tick != nil {
+			fmt.Fprint(w, "\r\x1b[K")
+		}
+		if !up {
+			return cli.NewExitError("", 130)
+		}
+
+		message := i18n.Sprintf("Time's up, %s!", driver.PreferredName())
+		if len(s.Authors) > 1 {
+			message = i18n.Sprintf("Time's up, %s! Hand over to %s.", driver.PreferredName(), s.Authors[1].PreferredName())
+		}
+		fmt.Fprintln(w, message)
+		if err := remind.Notify(runtime.GOOS, message); err != nil {
+			warnf(cx.App.ErrWriter, "unable to show a notification: %v", err)
+		}
+		if !cx.Bool("rotate") {
+			return nil
+		}
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		return nextDriver(cx, config, s)
+	},
+}
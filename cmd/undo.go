@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/keeferrourke/pair/audit"
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Undo provides the `pair undo` command, which puts the git author back the
+// way it was before the last change, from the backup kept beside the
+// identity file. Running it again redoes the change. It works without a
+// config too, for those pairing from the pairs file.
+var Undo = cli.Command{
+	Name:  "undo",
+	Usage: "Put the git author back the way it was before the last change.",
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if os.IsNotExist(err) {
+			config = nil
+		} else if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		} else if _, err := vcs.New(config.Vcs); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		name, email, err := vcs.RestoreIdentity(vcs.IdentityFile())
+		if _, ok := err.(*audit.SinkError); ok {
+			warnf(cx.App.ErrWriter, "%v", err)
+		} else if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to undo: %v", err), 1)
+		}
+		if name == "" || email == "" {
+			fmt.Fprintln(cx.App.Writer, i18n.Sprintf("The git author is no longer set in %s.", vcs.IdentityFile()))
+			return nil
+		}
+		if config == nil {
+			// Without a roster, there's nobody to start a session for.
+			fmt.Fprintf(cx.App.Writer, "%s <%s>\n", name, email)
+			return nil
+		}
+
+		authors := config.Recognize([]string{fmt.Sprintf("%s <%s>", name, email)})
+		if len(authors) == 0 {
+			warnf(cx.App.ErrWriter, "%s <%s> isn't anyone in your roster, so the current session is unchanged", name, email)
+			fmt.Fprintf(cx.App.Writer, "%s <%s>\n", name, email)
+			return nil
+		}
+		s, err := session.Start(name, email, config.WithEmails(authors))
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		if committer, err := vcs.GetConfig(vcs.IdentityFile(), "committer.name"); err == nil {
+			s.Committer = committer
+			s.CommitterEmail, _ = vcs.GetConfig(vcs.IdentityFile(), "committer.email")
+			if err := s.Save(); err != nil {
+				return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+			}
+		}
+		if err := trailer.UpdateTemplate(s); err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: %v", err), 1)
+		}
+		printIdentity(cx.App.Writer, config, s)
+		return nil
+	},
+}
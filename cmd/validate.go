@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// validateConfig implements `pair config validate`. Reports unknown keys,
+// which are probably typos, and invalid settings in a config file, the
+// default one unless another is given.
+func validateConfig(cx *cli.Context) error {
+	path := cfg.DefaultPath()
+	if cx.NArg() > 0 {
+		path = cx.Args().First()
+	}
+	config, err := cfg.NewFromFile(path)
+	if err != nil {
+		return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+	}
+
+	problems := 0
+	for _, u := range config.UnknownKeys() {
+		fmt.Fprintf(cx.App.Writer, "%s: %v\n", path, u)
+		problems++
+	}
+	if _, err := config.Validate(); err != nil {
+		fmt.Fprintf(cx.App.Writer, "%s: %v\n", path, err)
+		problems++
+	}
+	if problems > 0 {
+		return cli.NewExitError("", 1)
+	}
+	fmt.Fprintln(cx.App.Writer, i18n.Sprintf("%s is valid", path))
+	return nil
+}
+
+// warnConfig warns about problems reading the config, and unknown keys in
+// the config file, so typos like temmates: don't go unnoticed.
+func warnConfig(w io.Writer, config *cfg.Config) {
+	for _, err := range config.Warnings() {
+		warnf(w, "%v", err)
+	}
+	for _, u := range config.UnknownKeys() {
+		warnf(w, "%s: %v", config.Path, u)
+	}
+}
+
+// printSchema implements `pair config schema`, printing the JSON Schema for
+// the config or, with --roster, for an organization roster.
+func printSchema(cx *cli.Context) error {
+	schema := cfg.Schema()
+	if cx.Bool("roster") {
+		schema = cfg.RosterSchema()
+	}
+	enc := json.NewEncoder(cx.App.Writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
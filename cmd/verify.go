@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Verify provides the `pair verify` command. Checks that the active git
+// identity is you, or you plus teammates from the roster.
+var Verify = cli.Command{
+	Name:  "verify",
+	Usage: "Check that the active identity matches the roster.",
+	Action: func(cx *cli.Context) error {
+		config, err := cfg.Read()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read config: %v", err), 1)
+		}
+		name := vcs.ConfigValue("user.name")
+		email := vcs.ConfigValue("user.email")
+		if err := config.VerifyIdentity(name, email); err != nil {
+			return cli.NewExitError(i18n.Sprintf("warning: %v", err), 1)
+		}
+		fmt.Fprintf(cx.App.Writer, "%s <%s>\n", name, email)
+		return nil
+	},
+}
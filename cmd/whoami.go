@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/trailer"
+	"gopkg.in/yaml.v3"
+)
+
+// whoAmI is the identity reported by `pair whoami --format`, so prompts and
+// scripts don't have to parse "Name <email>".
+type whoAmI struct {
+	Name     string        `json:"name" yaml:"name"`                           // Composed author name. e.g. Lindsay Bluth and Michael Bluth
+	Email    string        `json:"email" yaml:"email"`                         // Composed author email. e.g. git+lb+mb@example.com
+	Aliases  []string      `json:"aliases" yaml:"aliases"`                     // Everyone's alias. e.g. [lb, mb]
+	Authors  []*cfg.Author `json:"authors" yaml:"authors"`                     // Everyone in the pair
+	Trailers []string      `json:"trailers" yaml:"trailers"`                   // Co-authored-by trailers for commits
+	Started  time.Time     `json:"started" yaml:"started"`                     // When the pair was set
+	Expires  *time.Time    `json:"expires,omitempty" yaml:"expires,omitempty"` // When the pair expires, if set with pair with --for
+}
+
+// writeWhoAmI writes the identity of s to w as json, yaml, or with the Go
+// template text, which can also use join like name templates.
+func writeWhoAmI(w io.Writer, format, text string, s *session.Session) error {
+	me := &whoAmI{
+		Name:     s.Name,
+		Email:    s.Email,
+		Aliases:  []string{},
+		Authors:  s.Authors,
+		Trailers: trailer.Trailers(s),
+		Started:  s.Started,
+	}
+	if !s.Expires.IsZero() {
+		me.Expires = &s.Expires
+	}
+	for _, a := range s.Authors {
+		me.Aliases = append(me.Aliases, a.Alias)
+	}
+	if me.Trailers == nil {
+		me.Trailers = []string{}
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(me)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		return enc.Encode(me)
+	case "template":
+		if text == "" {
+			return fmt.Errorf("--format template needs --template")
+		}
+		t, err := template.New("whoami").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+		if err != nil {
+			return fmt.Errorf("--template: %v", err)
+		}
+		if err := t.Execute(w, me); err != nil {
+			return fmt.Errorf("--template: %v", err)
+		}
+		fmt.Fprintln(w)
+		return nil
+	}
+	return fmt.Errorf("unknown format: %s", format)
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Whois provides the `pair whois` command. Prints everything known about an
+// alias from the roster and the session history.
+var Whois = cli.Command{
+	Name:      "whois",
+	Usage:     "Show everything known about a teammate.",
+	ArgsUsage: "<alias>",
+	Action: func(cx *cli.Context) error {
+		if cx.NArg() != 1 {
+			return cli.NewExitError(i18n.T("error: expected an alias"), 1)
+		}
+		alias := cx.Args().First()
+
+		roster, err := loadRoster(cx.App.ErrWriter)
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read roster: %v", err), 1)
+		}
+		var a *cfg.Author
+		for _, r := range roster {
+			if r.Alias == alias {
+				a = r
+				break
+			}
+		}
+		if a == nil {
+			return cli.NewExitError(i18n.Sprintf("error: no such username: %s", alias), 1)
+		}
+
+		var groups []string
+		if !useLegacyRoster() {
+			if config, err := cfg.Read(); err == nil {
+				for group, aliases := range config.Groups {
+					for _, member := range aliases {
+						if member == alias {
+							groups = append(groups, group)
+						}
+					}
+				}
+			}
+		}
+		sort.Strings(groups)
+
+		sessions, err := session.All()
+		if err != nil {
+			return cli.NewExitError(i18n.Sprintf("error: unable to read session history: %v", err), 1)
+		}
+		writeWhois(cx.App.Writer, a, groups, sessions, time.Now())
+		return nil
+	},
+}
+
+// writeWhois prints what's known about a as aligned fields, leaving out
+// anything unknown.
+func writeWhois(out io.Writer, a *cfg.Author, groups []string, sessions []*session.Session, now time.Time) {
+	w := table(out)
+	defer w.Flush()
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s:\t%s\n", name, value)
+		}
+	}
+
+	field("Alias", a.Alias)
+	field("Name", a.Name)
+	field("Goes by", a.DisplayName)
+	field("Pronouns", a.Pronouns)
+
+	var emails []string
+	seen := map[string]bool{}
+	addEmail := func(email string) {
+		if email != "" && !seen[strings.ToLower(email)] {
+			seen[strings.ToLower(email)] = true
+			emails = append(emails, email)
+		}
+	}
+	addEmail(a.Email)
+	var last *session.Session
+	count := 0
+	for _, s := range sessions {
+		if !s.Includes(a.Alias) {
+			continue
+		}
+		count++
+		for _, sa := range s.Authors {
+			if sa.Alias == a.Alias {
+				addEmail(sa.Email)
+			}
+		}
+		if last == nil || s.Started.After(last.Started) {
+			last = s
+		}
+	}
+	field("Emails", strings.Join(emails, ", "))
+	field("Groups", strings.Join(groups, ", "))
+
+	switch {
+	case a.AwayOn(now) && a.Until != "":
+		field("Status", "away until "+a.Until)
+	case a.AwayOn(now):
+		field("Status", "away")
+	default:
+		field("Status", "available")
+	}
+	if a.Timezone != "" {
+		local := a.LocalTime(now)
+		hours := a.Hours
+		if hours == "" {
+			hours = cfg.DefaultHours
+		}
+		field("Local time", fmt.Sprintf("%s (%s, works %s)", local.Format("Mon 15:04 MST"), a.Timezone, hours))
+	}
+	field("Signing key", a.SigningKey)
+
+	if last != nil {
+		var others []string
+		for _, sa := range last.Authors {
+			if sa.Alias != a.Alias {
+				others = append(others, sa.Alias)
+			}
+		}
+		with := ""
+		if len(others) > 0 {
+			with = " with " + strings.Join(others, ", ")
+		}
+		field("Last paired", fmt.Sprintf("%s%s (%d sessions)", last.Started.Format("2006-01-02"), with, count))
+	}
+}
@@ -0,0 +1,149 @@
+// Package compliance summarizes how much of a repository's history was
+// written in pairs, for CI artifacts and adoption trends.
+package compliance
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/vcs"
+)
+
+// Commit records whether a single commit was paired. Serializes to JSON.
+type Commit struct {
+	Hash      string   `json:"hash"`
+	Subject   string   `json:"subject"`
+	Author    string   `json:"author"`
+	CoAuthors []string `json:"coauthors"`
+	Driver    string   `json:"driver,omitempty"` // From the session's git note, if any
+	Paired    bool     `json:"paired"`
+}
+
+// Report summarizes pairing compliance over a range of commits.
+type Report struct {
+	Range   string    `json:"range"`
+	Total   int       `json:"total"`
+	Paired  int       `json:"paired"`
+	Commits []*Commit `json:"commits"`
+}
+
+// Analyze builds a report for commits. A commit counts as paired when it
+// carries co-author trailers, or when its author is a composed identity such
+// as "Lindsay Bluth and Michael Bluth".
+func Analyze(revRange string, commits []*vcs.Commit) *Report {
+	r := &Report{Range: revRange, Commits: []*Commit{}}
+	for _, c := range commits {
+		var coauthors []string
+		for _, t := range trailer.Parse(c.Message) {
+			coauthors = append(coauthors, strings.TrimSpace(strings.TrimPrefix(t, trailer.CoAuthoredBy+":")))
+		}
+		paired := len(coauthors) > 0 || strings.Contains(c.Author, " and ")
+		r.Commits = append(r.Commits, &Commit{
+			Hash:      c.Hash,
+			Subject:   c.Subject(),
+			Author:    fmt.Sprintf("%s <%s>", c.Author, c.Email),
+			CoAuthors: coauthors,
+			Paired:    paired,
+		})
+		r.Total++
+		if paired {
+			r.Paired++
+		}
+	}
+	return r
+}
+
+// WriteText writes a human readable summary of the report.
+func (r *Report) WriteText(w io.Writer) error {
+	for _, c := range r.Commits {
+		mark := "solo  "
+		if c.Paired {
+			mark = "paired"
+		}
+		if _, err := fmt.Fprintf(w, "%s %.7s %s\n", mark, c.Hash, c.Subject); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d of %d commits paired\n", r.Paired, r.Total)
+	return err
+}
+
+// WriteMarkdown writes the report as a Markdown summary suitable for a pull
+// request description.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "**Pairing:** %d of %d commits paired\n\n", r.Paired, r.Total); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "| Commit | Author | Co-authors | Driver |\n| --- | --- | --- | --- |\n"); err != nil {
+		return err
+	}
+	for _, c := range r.Commits {
+		coauthors := strings.Join(c.CoAuthors, ", ")
+		if coauthors == "" {
+			coauthors = "—"
+		}
+		_, err := fmt.Fprintf(w, "| %.7s %s | %s | %s | %s |\n", c.Hash, cell(c.Subject), cell(c.Author), cell(coauthors), cell(c.Driver))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cell makes s fit in a Markdown table cell: on one line, with any pipes
+// escaped so they don't end the cell.
+func cell(s string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", `\|`)
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the report as a JUnit XML test suite, with one test case
+// per commit that fails when the commit wasn't paired.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suite := junitSuite{Name: "pairing " + r.Range, Tests: r.Total, Failures: r.Total - r.Paired}
+	for _, c := range r.Commits {
+		tc := junitCase{Name: fmt.Sprintf("%.7s %s", c.Hash, c.Subject), ClassName: "pair.check"}
+		if !c.Paired {
+			tc.Failure = &junitFailure{Message: "commit by " + c.Author + " has no co-authors"}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
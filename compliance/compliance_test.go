@@ -0,0 +1,79 @@
+package compliance
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/keeferrourke/pair/vcs"
+)
+
+var commits = []*vcs.Commit{
+	{Hash: "aaaaaaaaaa", Author: "Michael Bluth", Email: "mb@example.com", Message: "Fix the stair car\n\nCo-authored-by: Lindsay Bluth <lb@example.com>"},
+	{Hash: "bbbbbbbbbb", Author: "Lindsay Bluth and Michael Bluth", Email: "git+lb+mb@example.com", Message: "Sell the banana stand"},
+	{Hash: "cccccccccc", Author: "George Bluth", Email: "gb@example.com", Message: "Hide the money"},
+}
+
+func TestAnalyze(t *testing.T) {
+	r := Analyze("main..HEAD", commits)
+	if r.Total != 3 || r.Paired != 2 {
+		t.Fatalf("expected 2 of 3 commits paired, got %d of %d", r.Paired, r.Total)
+	}
+	if len(r.Commits[0].CoAuthors) != 1 || r.Commits[0].CoAuthors[0] != "Lindsay Bluth <lb@example.com>" {
+		t.Fatalf("expected the trailer to be reported as a co-author, got %v", r.Commits[0].CoAuthors)
+	}
+	if r.Commits[2].Paired {
+		t.Fatalf("expected a solo commit not to count as paired")
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var b strings.Builder
+	if err := Analyze("main..HEAD", commits).WriteJUnit(&b); err != nil {
+		t.Fatalf("error writing junit report: %v", err)
+	}
+	out := b.String()
+	if !strings.Contains(out, `tests="3" failures="1"`) {
+		t.Fatalf("expected junit report to count tests and failures, got %s", out)
+	}
+	if strings.Count(out, "<failure") != 1 {
+		t.Fatalf("expected exactly one failure in junit report, got %s", out)
+	}
+}
+
+func TestWriteMarkdownEscapesCells(t *testing.T) {
+	r := Analyze("main..HEAD", []*vcs.Commit{
+		{Hash: "dddddddddd", Author: "Gob | Bluth", Email: "gob@example.com", Message: "Use a | in the subject"},
+	})
+	r.Commits[0].Driver = "Gob\nBluth"
+	var b strings.Builder
+	if err := r.WriteMarkdown(&b); err != nil {
+		t.Fatalf("error writing markdown report: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	expected := `| ddddddd Use a \| in the subject | Gob \| Bluth <gob@example.com> | — | Gob Bluth |`
+	if last := lines[len(lines)-1]; last != expected {
+		t.Fatalf("expected %s, got %s", expected, last)
+	}
+}
+
+func ExampleReport_WriteText() {
+	Analyze("main..HEAD", commits).WriteText(os.Stdout)
+
+	// Output:
+	// paired aaaaaaa Fix the stair car
+	// paired bbbbbbb Sell the banana stand
+	// solo   ccccccc Hide the money
+	// 2 of 3 commits paired
+}
+
+func ExampleReport_WriteMarkdown() {
+	Analyze("main..HEAD", commits[:1]).WriteMarkdown(os.Stdout)
+
+	// Output:
+	// **Pairing:** 1 of 1 commits paired
+	//
+	// | Commit | Author | Co-authors | Driver |
+	// | --- | --- | --- | --- |
+	// | aaaaaaa Fix the stair car | Michael Bluth <mb@example.com> | Lindsay Bluth <lb@example.com> |  |
+}
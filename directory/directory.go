@@ -0,0 +1,173 @@
+// Package directory looks people up in a company directory, such as LDAP or
+// Active Directory, so aliases which aren't in any roster still resolve.
+package directory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+// ErrNotFound is returned by Lookup for an alias nobody in the directory
+// has.
+var ErrNotFound = errors.New("not found in the directory")
+
+// Directory is somewhere people can be looked up by alias.
+type Directory interface {
+	// Lookup returns the person with alias, or ErrNotFound.
+	Lookup(alias string) (*cfg.Author, error)
+}
+
+// New returns the directory described by the directory setting, searched
+// with password for its bind_dn.
+func New(c *cfg.Directory, password string) (Directory, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ldap", "ldaps":
+		return &ldap{config: c, password: password, search: ldapsearch}, nil
+	}
+	return nil, fmt.Errorf("unsupported directory %s; use an ldap:// or ldaps:// URL", c.URL)
+}
+
+// ldap searches an LDAP or Active Directory server with ldapsearch, from the
+// OpenLDAP client tools.
+type ldap struct {
+	config   *cfg.Directory
+	password string
+	search   func(args []string) ([]byte, error) // ldapsearch, replaceable in tests
+}
+
+func (l *ldap) Lookup(alias string) (*cfg.Author, error) {
+	aliasAttr := orDefault(l.config.AliasAttribute, "uid")
+	nameAttr := orDefault(l.config.NameAttribute, "cn")
+	emailAttr := orDefault(l.config.EmailAttribute, "mail")
+	args := []string{"-LLL", "-x", "-z", "1", "-H", l.config.URL, "-b", l.config.BaseDN}
+	if l.config.BindDN != "" {
+		args = append(args, "-D", l.config.BindDN)
+	}
+	args = append(args, fmt.Sprintf("(%s=%s)", aliasAttr, EscapeFilter(alias)), nameAttr, emailAttr)
+	if l.config.BindDN != "" {
+		// The password is passed in a file rather than with -w, where anyone
+		// could see it in the process list.
+		f, err := ioutil.TempFile("", "pair-ldap-*")
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(l.password)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		args = append([]string{"-y", f.Name()}, args...)
+	}
+	out, err := l.search(args)
+	if err != nil {
+		return nil, err
+	}
+	entry := ParseLDIF(out)
+	if entry == nil {
+		return nil, ErrNotFound
+	}
+	a := &cfg.Author{Alias: alias, Name: entry[strings.ToLower(nameAttr)], Email: entry[strings.ToLower(emailAttr)]}
+	if a.Name == "" {
+		return nil, fmt.Errorf("%s has no %s in the directory", alias, nameAttr)
+	}
+	return a, nil
+}
+
+func ldapsearch(args []string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("ldapsearch", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("ldapsearch: %s", message)
+		}
+		return nil, fmt.Errorf("ldapsearch: %v", err)
+	}
+	return out, nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// EscapeFilter escapes value for use in an LDAP search filter (RFC 4515),
+// so an alias can't change what's searched for.
+func EscapeFilter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '*', '(', ')', '\\', 0:
+			fmt.Fprintf(&b, "\\%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// ParseLDIF returns the attributes of the first entry in LDIF output, such
+// as ldapsearch's, keyed by their lowercased names. Where an attribute has
+// several values, the first is kept. It returns nil if there are no entries.
+func ParseLDIF(ldif []byte) map[string]string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(ldif))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case strings.HasPrefix(line, " ") && len(lines) > 0:
+			lines[len(lines)-1] += line[1:] // A folded continuation line
+		case line == "" && len(lines) > 0:
+			return parseEntry(lines)
+		case line == "" || strings.HasPrefix(line, "#"):
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return parseEntry(lines)
+}
+
+func parseEntry(lines []string) map[string]string {
+	entry := make(map[string]string)
+	for _, line := range lines {
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		name, value := strings.ToLower(line[:i]), line[i+1:]
+		if strings.HasPrefix(value, ":") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				continue
+			}
+			value = string(decoded)
+		}
+		if _, ok := entry[name]; !ok {
+			entry[name] = strings.TrimSpace(value)
+		}
+	}
+	return entry
+}
@@ -0,0 +1,93 @@
+package directory
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+func TestParseLDIF(t *testing.T) {
+	ldif := `# extended LDIF
+dn: uid=tf,ou=people,dc=example,dc=com
+cn:: VG9iaWFzIEbDvG5rZQ==
+mail: tobias.funke@exam
+ ple.com
+mail: tf@example.com
+
+dn: uid=tf2,ou=people,dc=example,dc=com
+cn: Someone Else
+`
+	entry := ParseLDIF([]byte(ldif))
+	expected := map[string]string{
+		"dn":   "uid=tf,ou=people,dc=example,dc=com",
+		"cn":   "Tobias Fünke",
+		"mail": "tobias.funke@example.com",
+	}
+	if !reflect.DeepEqual(entry, expected) {
+		t.Fatalf("expected the first entry, got %v", entry)
+	}
+	if entry := ParseLDIF([]byte("# search result\n")); entry != nil {
+		t.Fatalf("expected no entry, got %v", entry)
+	}
+}
+
+func TestEscapeFilter(t *testing.T) {
+	for value, expected := range map[string]string{
+		"tf":          "tf",
+		"*)(uid=*":    "\\2a\\29\\28uid=\\2a",
+		"back\\slash": "back\\5cslash",
+	} {
+		if escaped := EscapeFilter(value); escaped != expected {
+			t.Errorf("expected %q to escape to %q, got %q", value, expected, escaped)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var password string
+	var got []string
+	d, err := New(&cfg.Directory{
+		URL:           "ldaps://ldap.example.com",
+		BaseDN:        "ou=people,dc=example,dc=com",
+		BindDN:        "cn=pair,dc=example,dc=com",
+		NameAttribute: "displayName",
+	}, "hunter2")
+	if err != nil {
+		t.Fatalf("expected no error creating the directory, got %v", err)
+	}
+	d.(*ldap).search = func(args []string) ([]byte, error) {
+		got = args
+		buf, _ := ioutil.ReadFile(args[1])
+		password = string(buf)
+		if strings.Contains(args[len(args)-3], "nobody") {
+			return nil, nil
+		}
+		return []byte("dn: uid=tf,dc=example,dc=com\ndisplayName: Tobias Fünke\nmail: tf@example.com\n"), nil
+	}
+
+	a, err := d.Lookup("tf")
+	if err != nil {
+		t.Fatalf("expected no error looking up tf, got %v", err)
+	}
+	if a.Alias != "tf" || a.Name != "Tobias Fünke" || a.Email != "tf@example.com" {
+		t.Fatalf("expected Tobias Fünke, got %+v", a)
+	}
+	if password != "hunter2" {
+		t.Fatalf("expected the password in a file, got %q", password)
+	}
+	expected := []string{"-LLL", "-x", "-z", "1", "-H", "ldaps://ldap.example.com", "-b", "ou=people,dc=example,dc=com",
+		"-D", "cn=pair,dc=example,dc=com", "(uid=tf)", "displayName", "mail"}
+	if !reflect.DeepEqual(got[2:], expected) {
+		t.Fatalf("expected ldapsearch %v, got %v", expected, got[2:])
+	}
+
+	if _, err := d.Lookup("nobody"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := New(&cfg.Directory{URL: "https://directory.example.com"}, ""); err == nil {
+		t.Fatalf("expected an error for a directory which isn't LDAP")
+	}
+}
@@ -0,0 +1,201 @@
+// Package github is a minimal client for the parts of the GitHub API that
+// pair integrates with.
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/internal/httpx"
+)
+
+// DefaultBaseURL is the root of the public GitHub API.
+const DefaultBaseURL = "https://api.github.com"
+
+// Client talks to the GitHub API on behalf of a user.
+type Client struct {
+	BaseURL string        // API root. e.g. https://api.github.com
+	Token   string        // Personal access token, may be empty
+	HTTP    *httpx.Client // Client used for requests
+}
+
+// NewClient creates a Client for the public API authenticating with token.
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL: DefaultBaseURL,
+		Token:   token,
+		HTTP:    httpx.New(30 * time.Second),
+	}
+}
+
+// do sends a request with an optional JSON body, decoding any JSON response
+// into out.
+func (c *Client) do(method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(c.BaseURL, "/")+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(buf, out)
+}
+
+// PullRequest is the subset of a GitHub pull request pair cares about.
+type PullRequest struct {
+	Number int    `json:"number"`
+	Body   string `json:"body"`
+	Base   struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	Head struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+}
+
+// PullRequest fetches pull request number from owner/repo.
+func (c *Client) PullRequest(owner, repo string, number int) (*PullRequest, error) {
+	var pr PullRequest
+	err := c.do("GET", fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number), nil, &pr)
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// PullRequestForBranch finds the open pull request whose head is branch.
+func (c *Client) PullRequestForBranch(owner, repo, branch string) (*PullRequest, error) {
+	var prs []*PullRequest
+	err := c.do("GET", fmt.Sprintf("/repos/%s/%s/pulls?head=%s:%s", owner, repo, owner, branch), nil, &prs)
+	if err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, fmt.Errorf("no open pull request for branch %s", branch)
+	}
+	return prs[0], nil
+}
+
+// EditPullRequestBody replaces the description of pull request number.
+func (c *Client) EditPullRequestBody(owner, repo string, number int, body string) error {
+	in := map[string]string{"body": body}
+	return c.do("PATCH", fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number), in, nil)
+}
+
+// Issue is the subset of a GitHub issue pair cares about.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+}
+
+// Issue fetches issue number from owner/repo.
+func (c *Client) Issue(owner, repo string, number int) (*Issue, error) {
+	var issue Issue
+	err := c.do("GET", fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number), nil, &issue)
+	if err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// User is the subset of a GitHub user pair cares about.
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+// NoreplyEmail returns the address GitHub credits commits to u with, without
+// revealing their own email. e.g. 1234+lindsay@users.noreply.github.com
+func (u *User) NoreplyEmail() string {
+	return fmt.Sprintf("%d+%s@users.noreply.github.com", u.ID, u.Login)
+}
+
+// OrgMembers lists the members of org the token can see, with their names.
+func (c *Client) OrgMembers(org string) ([]*User, error) {
+	var members []*User
+	for page := 1; ; page++ {
+		var batch []*User
+		if err := c.do("GET", fmt.Sprintf("/orgs/%s/members?per_page=100&page=%d", org, page), nil, &batch); err != nil {
+			return nil, err
+		}
+		members = append(members, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	for _, m := range members {
+		// Names aren't included in the list of members.
+		if err := c.do("GET", "/users/"+m.Login, nil, m); err != nil {
+			return nil, err
+		}
+	}
+	return members, nil
+}
+
+var remotePattern = regexp.MustCompile(`github\.com[:/]([^/]+)/([^/]+?)(\.git)?/?$`)
+
+// ParseRemote extracts the owner and repository name from a GitHub remote
+// URL, in either its SSH or HTTPS form.
+func ParseRemote(url string) (string, string, error) {
+	m := remotePattern.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil {
+		return "", "", errors.New("not a GitHub remote: " + url)
+	}
+	return m[1], m[2], nil
+}
+
+// Section markers delimit the part of a description managed by pair.
+const (
+	sectionBegin = "<!-- pair:begin -->"
+	sectionEnd   = "<!-- pair:end -->"
+)
+
+// ReplaceSection returns body with its pair-managed section replaced by
+// section, appending one if body doesn't have it yet.
+func ReplaceSection(body, section string) string {
+	managed := sectionBegin + "\n" + strings.TrimSpace(section) + "\n" + sectionEnd
+	begin := strings.Index(body, sectionBegin)
+	end := strings.Index(body, sectionEnd)
+	if begin < 0 || end < begin {
+		if strings.TrimSpace(body) == "" {
+			return managed + "\n"
+		}
+		return strings.TrimRight(body, "\n") + "\n\n" + managed + "\n"
+	}
+	return body[:begin] + managed + body[end+len(sectionEnd):]
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func ExampleParseRemote() {
+	for _, url := range []string{
+		"git@github.com:bluth/banana-stand.git",
+		"https://github.com/bluth/banana-stand",
+		"https://gitlab.com/bluth/banana-stand.git",
+	} {
+		owner, repo, err := ParseRemote(url)
+		fmt.Printf("owner=%s repo=%s error=%v\n", owner, repo, err)
+	}
+
+	// Output:
+	// owner=bluth repo=banana-stand error=<nil>
+	// owner=bluth repo=banana-stand error=<nil>
+	// owner= repo= error=not a GitHub remote: https://gitlab.com/bluth/banana-stand.git
+}
+
+func TestReplaceSection(t *testing.T) {
+	body := ReplaceSection("Fixes the stair car.", "old summary")
+	if body != "Fixes the stair car.\n\n<!-- pair:begin -->\nold summary\n<!-- pair:end -->\n" {
+		t.Fatalf("expected section to be appended, got %q", body)
+	}
+	body = ReplaceSection(body, "new summary")
+	if body != "Fixes the stair car.\n\n<!-- pair:begin -->\nnew summary\n<!-- pair:end -->\n" {
+		t.Fatalf("expected section to be replaced, got %q", body)
+	}
+	if body := ReplaceSection("", "summary"); body != "<!-- pair:begin -->\nsummary\n<!-- pair:end -->\n" {
+		t.Fatalf("expected section alone for an empty body, got %q", body)
+	}
+}
+
+func TestEditPullRequestBody(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/repos/bluth/banana-stand/pulls/42" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "token secret" {
+			t.Fatalf("expected token to be sent, got %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient("secret")
+	c.BaseURL = server.URL
+	if err := c.EditPullRequestBody("bluth", "banana-stand", 42, "hello"); err != nil {
+		t.Fatalf("expected no error editing pull request, got %v", err)
+	}
+	if got["body"] != "hello" {
+		t.Fatalf("expected body to be sent, got %v", got)
+	}
+}
+
+func TestOrgMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/bluth/members":
+			if r.URL.Query().Get("page") != "1" {
+				t.Fatalf("expected a single page to be fetched, got %s", r.URL)
+			}
+			fmt.Fprint(w, `[{"id": 2, "login": "lindsay"}, {"id": 3, "login": "gob"}]`)
+		case "/users/lindsay":
+			fmt.Fprint(w, `{"id": 2, "login": "lindsay", "name": "Lindsay Bluth"}`)
+		case "/users/gob":
+			fmt.Fprint(w, `{"id": 3, "login": "gob", "name": null}`)
+		default:
+			t.Fatalf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("")
+	c.BaseURL = server.URL
+	members, err := c.OrgMembers("bluth")
+	if err != nil {
+		t.Fatalf("expected no error listing members, got %v", err)
+	}
+	if len(members) != 2 || members[0].Name != "Lindsay Bluth" || members[1].Name != "" {
+		t.Fatalf("expected members with their names, got %v", members)
+	}
+	if email := members[0].NoreplyEmail(); email != "2+lindsay@users.noreply.github.com" {
+		t.Fatalf("expected a noreply email, got %s", email)
+	}
+}
+
+func TestIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/repos/bluth/banana-stand/issues/843" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"number": 843, "title": "Fix login timeout"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("")
+	c.BaseURL = server.URL
+	issue, err := c.Issue("bluth", "banana-stand", 843)
+	if err != nil || issue.Title != "Fix login timeout" {
+		t.Fatalf("expected the issue's title, got %v, %v", issue, err)
+	}
+}
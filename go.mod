@@ -1,7 +1,12 @@
 module github.com/keeferrourke/pair
 
+go 1.16
+
 require (
+	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1
+	golang.org/x/term v0.1.0
 	gopkg.in/urfave/cli.v1 v1.20.0
 	gopkg.in/yaml.v1 v1.0.0-20140924161607-9f9df34309c0
 	gopkg.in/yaml.v2 v2.2.2
+	gopkg.in/yaml.v3 v3.0.1
 )
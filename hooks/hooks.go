@@ -0,0 +1,201 @@
+// Package hooks installs the git hooks that let pair add co-author trailers
+// at commit time. Hooks are installed as a marked block inside the hook
+// script, so they chain with whatever the repository or a hook manager
+// already runs instead of replacing it.
+package hooks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/internal/block"
+	"github.com/keeferrourke/pair/vcs"
+)
+
+// Manager identifies a third-party hook manager.
+type Manager string
+
+// Hook managers pair knows how to coexist with.
+const (
+	None     Manager = ""
+	Husky    Manager = "husky"
+	Lefthook Manager = "lefthook"
+)
+
+// Names lists the hooks pair always installs.
+var Names = []string{"prepare-commit-msg", "commit-msg"}
+
+// Optional lists the hooks pair installs only on request.
+var Optional = []string{"pre-commit", "post-commit"}
+
+// IsOptional reports whether name is one of the Optional hooks.
+func IsOptional(name string) bool {
+	for _, o := range Optional {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Location describes where hooks should be installed for a repository.
+type Location struct {
+	Dir     string  // Directory holding the hook scripts
+	Manager Manager // Hook manager in use, if any
+	Global  bool    // Dir is the global core.hooksPath, shared by every repository
+}
+
+// Locate finds the hooks directory for the repository in the working
+// directory, honoring core.hooksPath and detecting hook managers.
+func Locate() (*Location, error) {
+	root, err := vcs.Git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository")
+	}
+	dir, err := vcs.Git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	loc := Detect(root, dir)
+	if _, err := vcs.Git("config", "--local", "core.hooksPath"); err != nil {
+		_, err := GlobalPath()
+		loc.Global = err == nil
+	}
+	return loc, nil
+}
+
+// Detect works out which hook manager, if any, owns hooksDir in the
+// repository rooted at root.
+func Detect(root, hooksDir string) *Location {
+	for _, name := range []string{"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return &Location{Dir: hooksDir, Manager: Lefthook}
+		}
+	}
+	husky := filepath.Join(root, ".husky")
+	if hooksDir == husky || strings.HasPrefix(hooksDir, husky+string(filepath.Separator)) {
+		// Husky points core.hooksPath at its own shims; user hooks live
+		// directly in .husky.
+		return &Location{Dir: husky, Manager: Husky}
+	}
+	return &Location{Dir: hooksDir, Manager: None}
+}
+
+// Block returns the managed block for the named hook, which hands the hook's
+// arguments over to pair. If pair fails, so does the hook, so the rest of
+// the script can't make a blocked commit succeed.
+func Block(name string) string {
+	return block.Wrap(runPair(name))
+}
+
+// runPair returns the lines running pair for the named hook, exiting with
+// its status if it fails. Without pair installed, they do nothing.
+func runPair(name string) string {
+	return fmt.Sprintf(`if command -v pair >/dev/null 2>&1; then
+	pair hook %s "$@" || exit $?
+fi`, name)
+}
+
+// GlobalBlock returns the managed block for the named hook when installed in
+// a user-level core.hooksPath. Since git then ignores the repository's own
+// hooks directory, the block runs the repository's hook itself.
+func GlobalBlock(name string) string {
+	return block.Wrap(runPair(name) + fmt.Sprintf(`
+repo_hook="$(git rev-parse --git-dir)/hooks/%s"
+if [ -x "$repo_hook" ] && [ "$repo_hook" != "$0" ]; then
+	"$repo_hook" "$@" || exit $?
+fi`, name))
+}
+
+// GlobalPath returns the global core.hooksPath, with ~ expanded as git does,
+// or an error if it isn't set.
+func GlobalPath() (string, error) {
+	return vcs.Git("config", "--global", "--type=path", "core.hooksPath")
+}
+
+// GlobalDir returns the user-level hooks directory pair manages when no
+// core.hooksPath is configured globally.
+func GlobalDir() string {
+	return filepath.Join(cfg.DataDir(), "hooks")
+}
+
+// Install adds the managed block for the named hook to the script at path,
+// creating the script if needed. An existing block is replaced, so installing
+// twice is harmless; the rest of the script is left untouched.
+func Install(path, name string) error {
+	return install(path, Block(name))
+}
+
+// InstallGlobal is like Install, but uses GlobalBlock so that hooks in a
+// user-level hooks directory still delegate to repository hooks.
+func InstallGlobal(path, name string) error {
+	return install(path, GlobalBlock(name))
+}
+
+func install(path, managed string) error {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	script := block.Remove(string(buf))
+	if strings.TrimSpace(script) == "" {
+		script = "#!/bin/sh\n"
+	}
+
+	// Run before the rest of the script, in case it ends with exit or exec.
+	var updated string
+	if strings.HasPrefix(script, "#!") {
+		lines := strings.SplitN(script, "\n", 2)
+		updated = lines[0] + "\n" + managed
+		if len(lines) == 2 {
+			updated += lines[1]
+		}
+	} else {
+		updated = managed + script
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(updated), 0755)
+}
+
+// Uninstall removes the managed block from the script at path, deleting the
+// script if nothing but a shebang remains.
+func Uninstall(path string) error {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	script := block.Remove(string(buf))
+	if rest := strings.TrimSpace(script); rest == "" || (strings.HasPrefix(rest, "#!") && !strings.Contains(rest, "\n")) {
+		return os.Remove(path)
+	}
+	return ioutil.WriteFile(path, []byte(script), 0755)
+}
+
+// Installed reports whether the script at path contains the managed block.
+func Installed(path string) bool {
+	buf, err := ioutil.ReadFile(path)
+	return err == nil && block.Contains(string(buf))
+}
+
+// LefthookConfig returns the lefthook.yml snippet that runs the named hooks,
+// for repositories where lefthook owns the hook scripts.
+func LefthookConfig(names []string) string {
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s:\n  commands:\n    pair:\n      run: pair hook %s {0}\n", name, name)
+	}
+	return b.String()
+}
@@ -0,0 +1,176 @@
+package hooks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/keeferrourke/pair/internal/block"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "pair-hooks")
+	if err != nil {
+		t.Fatalf("couldn't make tempdir during test set up: %v", err)
+	}
+	return dir
+}
+
+func TestInstallNewHook(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, "prepare-commit-msg")
+
+	for i := 0; i < 2; i++ {
+		if err := Install(path, "prepare-commit-msg"); err != nil {
+			t.Fatalf("error installing hook: %v", err)
+		}
+	}
+	buf, _ := ioutil.ReadFile(path)
+	if string(buf) != "#!/bin/sh\n"+Block("prepare-commit-msg") {
+		t.Fatalf("expected a fresh hook with a single managed block, got %q", buf)
+	}
+	if !Installed(path) {
+		t.Fatalf("expected hook to be reported as installed")
+	}
+
+	if err := Uninstall(path); err != nil {
+		t.Fatalf("error uninstalling hook: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected an otherwise empty hook to be removed")
+	}
+}
+
+func TestInstallChainsExistingHook(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, "prepare-commit-msg")
+	existing := "#!/bin/bash\nnpx lint-staged\nexit 0\n"
+	ioutil.WriteFile(path, []byte(existing), 0755)
+
+	if err := Install(path, "prepare-commit-msg"); err != nil {
+		t.Fatalf("error installing hook: %v", err)
+	}
+	buf, _ := ioutil.ReadFile(path)
+	script := string(buf)
+	if !strings.HasPrefix(script, "#!/bin/bash\n"+Block("prepare-commit-msg")) {
+		t.Fatalf("expected managed block right after the shebang, got %q", script)
+	}
+	if !strings.HasSuffix(script, "npx lint-staged\nexit 0\n") {
+		t.Fatalf("expected the existing hook to be preserved, got %q", script)
+	}
+
+	if err := Uninstall(path); err != nil {
+		t.Fatalf("error uninstalling hook: %v", err)
+	}
+	buf, _ = ioutil.ReadFile(path)
+	if string(buf) != existing {
+		t.Fatalf("expected uninstall to restore the existing hook, got %q", buf)
+	}
+}
+
+func TestInstallGlobal(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, "prepare-commit-msg")
+
+	if err := InstallGlobal(path, "prepare-commit-msg"); err != nil {
+		t.Fatalf("error installing global hook: %v", err)
+	}
+	if err := InstallGlobal(path, "prepare-commit-msg"); err != nil {
+		t.Fatalf("error reinstalling global hook: %v", err)
+	}
+	buf, _ := ioutil.ReadFile(path)
+	script := string(buf)
+	if strings.Count(script, block.Begin) != 1 {
+		t.Fatalf("expected a single managed block, got %q", script)
+	}
+	if !strings.Contains(script, `repo_hook="$(git rev-parse --git-dir)/hooks/prepare-commit-msg"`) {
+		t.Fatalf("expected global hook to delegate to the repository hook, got %q", script)
+	}
+}
+
+func TestHookExitStatus(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir) // clean up
+	bin := filepath.Join(dir, "bin")
+	os.Mkdir(bin, 0755)
+	ioutil.WriteFile(filepath.Join(bin, "pair"), []byte("#!/bin/sh\nexit 3\n"), 0755)
+
+	for name, install := range map[string]func(string, string) error{"repo": Install, "global": InstallGlobal} {
+		path := filepath.Join(dir, name+"-commit-msg")
+		ioutil.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0755)
+		if err := install(path, "commit-msg"); err != nil {
+			t.Fatalf("error installing %s hook: %v", name, err)
+		}
+
+		cmd := exec.Command(path, "COMMIT_EDITMSG")
+		cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+		err := cmd.Run()
+		if exit, ok := err.(*exec.ExitError); !ok || exit.ExitCode() != 3 {
+			t.Fatalf("expected the %s hook to exit with pair's status, got %v", name, err)
+		}
+
+		cmd = exec.Command(path, "COMMIT_EDITMSG")
+		cmd.Env = append(os.Environ(), "PATH="+filepath.Join(dir, "empty"))
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("expected the %s hook to carry on without pair installed, got %v", name, err)
+		}
+	}
+}
+
+func TestGlobalPathExpandsHome(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir) // clean up
+	for k, v := range map[string]string{"HOME": dir, "GIT_CONFIG_GLOBAL": filepath.Join(dir, ".gitconfig")} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Setenv(k, v)
+	}
+	if _, err := GlobalPath(); err == nil {
+		t.Fatal("expected an error with core.hooksPath unset")
+	}
+	ioutil.WriteFile(filepath.Join(dir, ".gitconfig"), []byte("[core]\n\thooksPath = ~/.githooks\n"), 0644)
+
+	path, err := GlobalPath()
+	if err != nil {
+		t.Fatalf("error reading core.hooksPath: %v", err)
+	}
+	if expected := filepath.Join(dir, ".githooks"); path != expected {
+		t.Fatalf("expected %s, got %s", expected, path)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := tempDir(t)
+	defer os.RemoveAll(root) // clean up
+
+	loc := Detect(root, filepath.Join(root, ".git", "hooks"))
+	if loc.Manager != None || loc.Dir != filepath.Join(root, ".git", "hooks") {
+		t.Fatalf("expected plain hooks directory, got %+v", loc)
+	}
+
+	loc = Detect(root, filepath.Join(root, ".husky", "_"))
+	if loc.Manager != Husky || loc.Dir != filepath.Join(root, ".husky") {
+		t.Fatalf("expected husky to own .husky, got %+v", loc)
+	}
+
+	ioutil.WriteFile(filepath.Join(root, "lefthook.yml"), nil, 0644)
+	if loc = Detect(root, filepath.Join(root, ".git", "hooks")); loc.Manager != Lefthook {
+		t.Fatalf("expected lefthook to be detected, got %+v", loc)
+	}
+}
+
+func ExampleLefthookConfig() {
+	fmt.Print(LefthookConfig([]string{"prepare-commit-msg"}))
+
+	// Output:
+	// prepare-commit-msg:
+	//   commands:
+	//     pair:
+	//       run: pair hook prepare-commit-msg {0}
+}
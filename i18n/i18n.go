@@ -0,0 +1,97 @@
+// Package i18n translates pair's user-facing messages. Messages are looked up
+// by their English text, gettext style, so anything without a translation
+// falls back to English. A catalog is a YAML file mapping English format
+// strings to translated ones, e.g. fr.yml:
+//
+//	"Who are you anyway?": "Qui êtes-vous, au juste ?"
+//	"error: unable to read config: %v": "erreur : impossible de lire la configuration : %v"
+package i18n
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog maps English messages to their translations.
+type Catalog map[string]string
+
+// current is the catalog messages are translated with.
+var current Catalog
+
+// Use translates messages with c from now on. A nil catalog means English.
+func Use(c Catalog) {
+	current = c
+}
+
+// T returns the translation of message, or message itself if there isn't one.
+func T(message string) string {
+	if translated := current[message]; translated != "" {
+		return translated
+	}
+	return message
+}
+
+// Sprintf formats the translation of format with args.
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Locale returns the locale messages should be in: configured if set, e.g.
+// from the config's locale, otherwise $LC_ALL, $LC_MESSAGES or $LANG. The
+// C and POSIX locales are English, returned as the empty string.
+func Locale(configured string) string {
+	locale := configured
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale != "" {
+			break
+		}
+		locale = os.Getenv(name)
+	}
+	if locale == "C" || locale == "POSIX" || strings.HasPrefix(locale, "C.") {
+		return ""
+	}
+	return locale
+}
+
+// candidates returns the catalog names to try for locale, most specific
+// first. For example, fr_CA.UTF-8 tries fr_CA, then fr.
+func candidates(locale string) []string {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "" {
+		return nil
+	}
+	names := []string{locale}
+	if i := strings.IndexAny(locale, "_-"); i > 0 {
+		names = append(names, locale[:i])
+	}
+	return names
+}
+
+// Load reads the catalog for locale from dir, trying the most specific file
+// first: dir/fr_CA.yml, then dir/fr.yml. It returns a nil catalog, which is
+// English, if there's no catalog for locale.
+func Load(dir, locale string) (Catalog, error) {
+	for _, name := range candidates(locale) {
+		path := filepath.Join(dir, name+".yml")
+		buf, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var c Catalog
+		if err := yaml.Unmarshal(buf, &c); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return c, nil
+	}
+	return nil, nil
+}
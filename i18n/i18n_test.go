@@ -0,0 +1,56 @@
+package i18n
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocale(t *testing.T) {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+
+	os.Setenv("LANG", "de_DE.UTF-8")
+	if locale := Locale(""); locale != "de_DE.UTF-8" {
+		t.Fatalf("expected $LANG, got %q", locale)
+	}
+	os.Setenv("LC_MESSAGES", "C")
+	if locale := Locale(""); locale != "" {
+		t.Fatalf("expected English for the C locale, got %q", locale)
+	}
+	if locale := Locale("fr_CA"); locale != "fr_CA" {
+		t.Fatalf("expected the configured locale to win, got %q", locale)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pair-i18n")
+	if err != nil {
+		t.Fatalf("unable to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir) // clean up
+	catalog := "\"error: unable to read config: %v\": \"erreur : impossible de lire la configuration : %v\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "fr.yml"), []byte(catalog), 0644); err != nil {
+		t.Fatalf("unable to write catalog: %v", err)
+	}
+
+	c, err := Load(dir, "fr_CA.UTF-8")
+	if err != nil {
+		t.Fatalf("expected the fr catalog for fr_CA, got %v", err)
+	}
+	Use(c)
+	defer Use(nil)
+	if s := Sprintf("error: unable to read config: %v", "boom"); s != "erreur : impossible de lire la configuration : boom" {
+		t.Fatalf("expected a translated message, got %q", s)
+	}
+	if s := T("Who are you anyway?"); s != "Who are you anyway?" {
+		t.Fatalf("expected untranslated messages in English, got %q", s)
+	}
+
+	if c, err := Load(dir, "ja_JP"); c != nil || err != nil {
+		t.Fatalf("expected no catalog for ja_JP, got %v, %v", c, err)
+	}
+}
@@ -0,0 +1,50 @@
+// Package block edits the marked block of generated text that pair keeps
+// inside files which are otherwise maintained by hand, such as git hooks and
+// .envrc files.
+package block
+
+import "strings"
+
+// Markers delimit the block managed by pair.
+const (
+	Begin = "# >>> pair >>>"
+	End   = "# <<< pair <<<"
+)
+
+// Wrap surrounds content with the block markers.
+func Wrap(content string) string {
+	return Begin + "\n" + strings.TrimRight(content, "\n") + "\n" + End + "\n"
+}
+
+// Contains reports whether text has a managed block.
+func Contains(text string) bool {
+	return strings.Contains(text, Begin)
+}
+
+// Remove returns text without its managed block, if it has one.
+func Remove(text string) string {
+	begin := strings.Index(text, Begin)
+	end := strings.Index(text, End)
+	if begin < 0 || end < begin {
+		return text
+	}
+	end += len(End)
+	if end < len(text) && text[end] == '\n' {
+		end++
+	}
+	return text[:begin] + text[end:]
+}
+
+// Replace returns text with its managed block holding content. The block
+// stays where it was, or is appended if text doesn't have one yet.
+func Replace(text, content string) string {
+	begin := strings.Index(text, Begin)
+	if begin < 0 || strings.Index(text, End) < begin {
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		return text + Wrap(content)
+	}
+	rest := Remove(text[begin:])
+	return text[:begin] + Wrap(content) + rest
+}
@@ -0,0 +1,23 @@
+package block
+
+import "testing"
+
+func TestReplace(t *testing.T) {
+	text := Replace("layout go", "export A=1")
+	if text != "layout go\n# >>> pair >>>\nexport A=1\n# <<< pair <<<\n" {
+		t.Fatalf("expected block to be appended, got %q", text)
+	}
+	text = Replace(text+"dotenv\n", "export A=2")
+	if text != "layout go\n# >>> pair >>>\nexport A=2\n# <<< pair <<<\ndotenv\n" {
+		t.Fatalf("expected block to be replaced in place, got %q", text)
+	}
+	if !Contains(text) {
+		t.Fatalf("expected text to contain a block")
+	}
+	if text = Remove(text); text != "layout go\ndotenv\n" {
+		t.Fatalf("expected block to be removed, got %q", text)
+	}
+	if Contains(text) {
+		t.Fatalf("expected text not to contain a block")
+	}
+}
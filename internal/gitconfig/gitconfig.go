@@ -0,0 +1,422 @@
+// Package gitconfig reads and writes single keys in INI-style git config
+// files without running git, which matters for prompts that read the author
+// on every keystroke and on machines where git isn't on the PATH. Writes
+// change only the line holding the key, so comments and unrelated sections
+// are kept as they were.
+//
+// It handles what pair and `git config` write. Anything else, such as the
+// deprecated [section.subsection] headers, is reported as a *SyntaxError so
+// callers can fall back to running git.
+package gitconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/keeferrourke/pair/internal/lockedfile"
+)
+
+// ErrNotSet is returned by Get for a key the file doesn't set.
+var ErrNotSet = errors.New("not set")
+
+// SyntaxError is returned for a file, or a change to it, which this package
+// doesn't handle.
+type SyntaxError struct {
+	Path string // The config file
+	Line int    // Where, from 1, or 0 for the whole file
+	Msg  string // What's wrong
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+}
+
+// Get returns the value of key, such as user.name, in the config file at
+// path. Like git, the last value wins when a key is set more than once.
+func Get(path, key string) (string, error) {
+	k, err := parseKey(key)
+	if err != nil {
+		return "", err
+	}
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := parse(path, string(buf))
+	if err != nil {
+		return "", err
+	}
+	matches := f.find(k)
+	if len(matches) == 0 {
+		return "", ErrNotSet
+	}
+	return matches[len(matches)-1].value, nil
+}
+
+// Set sets key to value in the config file at path, creating the file or
+// the section if needed. An existing value is replaced where it is; a new one
+// goes at the end of the last section it belongs in.
+func Set(path, key, value string) error {
+	return SetAll(path, key, value)
+}
+
+// SetAll sets each key to the value following it in keyValues, as Set does,
+// in one write.
+func SetAll(path string, keyValues ...string) error {
+	if len(keyValues)%2 != 0 {
+		panic("gitconfig: odd number of arguments to SetAll")
+	}
+	changes := make([]Change, 0, len(keyValues)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		changes = append(changes, Change{Key: keyValues[i], Value: keyValues[i+1]})
+	}
+	return Apply(path, changes...)
+}
+
+// Change is a change to one key in a config file: setting it to Value, or
+// removing it if Unset.
+type Change struct {
+	Key   string
+	Value string
+	Unset bool
+}
+
+// Apply makes changes to the config file at path in one locked write, so
+// nobody sees some of them made but not the others. The file it replaces is
+// kept at lockedfile.Backup(path).
+func Apply(path string, changes ...Change) error {
+	return lockedfile.Update(path, 0644, func(buf []byte) ([]byte, error) {
+		text := string(buf)
+		for _, c := range changes {
+			var err error
+			if c.Unset {
+				text, err = unset(path, text, c.Key)
+			} else {
+				text, err = set(path, text, c.Key, c.Value)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return []byte(text), nil
+	})
+}
+
+// set returns text, the contents of the config file at path, with key set to
+// value.
+func set(path, text, key, value string) (string, error) {
+	k, err := parseKey(key)
+	if err != nil {
+		return "", err
+	}
+	f, err := parse(path, text)
+	if err != nil {
+		return "", err
+	}
+
+	line := "\t" + k.name + " = " + quote(value)
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	matches := f.find(k)
+	switch {
+	case len(matches) > 1:
+		return "", &SyntaxError{Path: path, Msg: fmt.Sprintf("%s has %d values", key, len(matches))}
+	case len(matches) == 1:
+		v := matches[0]
+		if v.first == v.header {
+			return "", &SyntaxError{Path: path, Line: v.first + 1, Msg: "a key on the same line as its section"}
+		}
+		lines = splice(lines, v.first, v.last+1, line+"\n")
+	default:
+		at := -1
+		for _, v := range f.vars {
+			if v.section.matches(k.section) && v.last > at {
+				at = v.last
+			}
+		}
+		for _, h := range f.headers {
+			if h.section.matches(k.section) && h.line > at {
+				at = h.line
+			}
+		}
+		if at < 0 {
+			if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+				lines[n-1] += "\n"
+			}
+			lines = append(lines, k.section.header()+"\n", line+"\n")
+			break
+		}
+		if !strings.HasSuffix(lines[at], "\n") {
+			lines[at] += "\n"
+		}
+		lines = splice(lines, at+1, at+1, line+"\n")
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// unset returns text, the contents of the config file at path, without any
+// values of key.
+func unset(path, text, key string) (string, error) {
+	k, err := parseKey(key)
+	if err != nil {
+		return "", err
+	}
+	f, err := parse(path, text)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.SplitAfter(text, "\n")
+	matches := f.find(k)
+	for i := len(matches) - 1; i >= 0; i-- {
+		v := matches[i]
+		if v.first == v.header {
+			return "", &SyntaxError{Path: path, Line: v.first + 1, Msg: "a key on the same line as its section"}
+		}
+		lines = append(lines[:v.first], lines[v.last+1:]...)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// splice replaces lines[from:to] with line.
+func splice(lines []string, from, to int, line string) []string {
+	spliced := append([]string{}, lines[:from]...)
+	spliced = append(spliced, line)
+	return append(spliced, lines[to:]...)
+}
+
+// quote formats value the way git would write it, quoting it only when
+// spaces at either end or a comment character would otherwise be lost.
+func quote(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\b", `\b`).Replace(value)
+	if value != strings.TrimSpace(value) || strings.ContainsAny(value, "#;") {
+		return `"` + escaped + `"`
+	}
+	return escaped
+}
+
+// section names a section, and its subsection if it has one.
+type section struct {
+	name   string // Compared case-insensitively. e.g. user
+	sub    string // Compared exactly. e.g. origin in [remote "origin"]
+	hasSub bool
+}
+
+func (s section) matches(other section) bool {
+	return strings.EqualFold(s.name, other.name) && s.hasSub == other.hasSub && s.sub == other.sub
+}
+
+func (s section) header() string {
+	if !s.hasSub {
+		return "[" + s.name + "]"
+	}
+	return "[" + s.name + ` "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s.sub) + `"]`
+}
+
+// key is a parsed config key. e.g. remote.origin.url
+type key struct {
+	section section
+	name    string
+}
+
+func parseKey(k string) (key, error) {
+	first, last := strings.Index(k, "."), strings.LastIndex(k, ".")
+	if first <= 0 || last == len(k)-1 || !validName(k[last+1:]) {
+		return key{}, fmt.Errorf("invalid key: %s", k)
+	}
+	s := section{name: k[:first]}
+	if first != last {
+		s.sub, s.hasSub = k[first+1:last], true
+	}
+	return key{section: s, name: k[last+1:]}, nil
+}
+
+// validName reports whether name can be a variable or section name: letters,
+// digits and dashes, starting with a letter.
+func validName(name string) bool {
+	for i, r := range name {
+		letter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+		if !letter && (i == 0 || r != '-' && (r < '0' || r > '9')) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// file is a parsed config file: where each section header and variable is.
+type file struct {
+	headers []header
+	vars    []variable
+}
+
+type header struct {
+	section section
+	line    int // Index of the header's line
+}
+
+type variable struct {
+	section section
+	name    string
+	value   string
+	header  int // Index of its section's header line, or -1
+	first   int // Index of its first line
+	last    int // Index of its last line, after any continuations
+}
+
+func (f *file) find(k key) []variable {
+	var found []variable
+	for _, v := range f.vars {
+		if v.section.matches(k.section) && strings.EqualFold(v.name, k.name) {
+			found = append(found, v)
+		}
+	}
+	return found
+}
+
+func parse(path, text string) (*file, error) {
+	f := &file{}
+	lines := strings.Split(text, "\n")
+	current, currentLine := section{}, -1
+	for i := 0; i < len(lines); i++ {
+		syntaxError := func(msg string) error {
+			return &SyntaxError{Path: path, Line: i + 1, Msg: msg}
+		}
+		rest := strings.TrimLeft(strings.TrimRight(lines[i], "\r"), " \t")
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, syntaxError("unterminated section header")
+			}
+			s, err := parseHeader(rest[1:end])
+			if err != nil {
+				return nil, syntaxError(err.Error())
+			}
+			current, currentLine = s, i
+			f.headers = append(f.headers, header{section: s, line: i})
+			rest = strings.TrimLeft(rest[end+1:], " \t")
+		}
+		if rest == "" || rest[0] == '#' || rest[0] == ';' {
+			continue
+		}
+		if currentLine < 0 {
+			return nil, syntaxError("key outside any section")
+		}
+		n := 0
+		for n < len(rest) && rest[n] != '=' && rest[n] != ' ' && rest[n] != '\t' {
+			n++
+		}
+		v := variable{section: current, name: rest[:n], header: currentLine, first: i}
+		if !validName(v.name) {
+			return nil, syntaxError("invalid key " + v.name)
+		}
+		rest = strings.TrimLeft(rest[n:], " \t")
+		if rest == "" || rest[0] == '#' || rest[0] == ';' {
+			v.value, v.last = "true", i // A bare key is a true boolean
+			f.vars = append(f.vars, v)
+			continue
+		}
+		if rest[0] != '=' {
+			return nil, syntaxError("expected = after " + v.name)
+		}
+		value, last, err := parseValue(lines, i, rest[1:])
+		if err != nil {
+			return nil, syntaxError(err.Error())
+		}
+		v.value, v.last = value, last
+		f.vars = append(f.vars, v)
+		i = last
+	}
+	return f, nil
+}
+
+// parseHeader parses what's between the brackets of a section header.
+func parseHeader(text string) (section, error) {
+	space := strings.IndexAny(text, " \t")
+	if space < 0 {
+		if strings.Contains(text, ".") {
+			return section{}, errors.New("deprecated [section.subsection] header")
+		}
+		if !validName(text) {
+			return section{}, errors.New("invalid section " + text)
+		}
+		return section{name: text}, nil
+	}
+	s := section{name: text[:space], hasSub: true}
+	if !validName(s.name) {
+		return section{}, errors.New("invalid section " + s.name)
+	}
+	quoted := strings.TrimLeft(text[space:], " \t")
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		return section{}, errors.New("expected a quoted subsection")
+	}
+	var b strings.Builder
+	for i := 1; i < len(quoted)-1; i++ {
+		if quoted[i] == '\\' && i+1 < len(quoted)-1 {
+			i++
+		}
+		b.WriteByte(quoted[i])
+	}
+	s.sub = b.String()
+	return s, nil
+}
+
+// parseValue parses the value starting at text on lines[i], following
+// continuation lines. It returns the value and the index of its last line.
+func parseValue(lines []string, i int, text string) (string, int, error) {
+	var b strings.Builder
+	quoted := false
+	spaces := "" // Unquoted whitespace, only kept if more of the value follows
+	for {
+		line := strings.TrimRight(text, "\r")
+		continued := false
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case c == '\\' && j == len(line)-1:
+				continued = true
+			case c == '\\':
+				j++
+				escaped, ok := escapes[line[j]]
+				if !ok {
+					return "", i, fmt.Errorf("unknown escape \\%c", line[j])
+				}
+				b.WriteString(spaces)
+				b.WriteByte(escaped)
+				spaces = ""
+			case c == '"':
+				quoted = !quoted
+			case !quoted && (c == '#' || c == ';'):
+				j = len(line) // A comment runs to the end of the line
+			case !quoted && (c == ' ' || c == '\t'):
+				if b.Len() > 0 {
+					spaces += string(c)
+				}
+			default:
+				b.WriteString(spaces)
+				b.WriteByte(c)
+				spaces = ""
+			}
+		}
+		if !continued {
+			break
+		}
+		if i+1 >= len(lines) {
+			return "", i, errors.New("continuation at the end of the file")
+		}
+		i++
+		text = lines[i]
+	}
+	if quoted {
+		return "", i, errors.New("unterminated quote")
+	}
+	return b.String(), i, nil
+}
+
+// escapes are the characters which may follow a backslash in a value.
+var escapes = map[byte]byte{'\\': '\\', '"': '"', 'n': '\n', 't': '\t', 'b': '\b'}
@@ -0,0 +1,199 @@
+package gitconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keeferrourke/pair/internal/lockedfile"
+)
+
+const sample = `# Written by hand
+[core]
+	editor = vim ; for now
+[user]
+	name = Lindsay Bluth and Michael Bluth
+	email = "git+lb+mb@example.com"
+[remote "origin"]
+	url = https://example.com/bluth/\
+banana-stand.git
+	bare
+[alias]
+	lg = "log --graph  # not a comment"
+	co = checkout
+[user]
+	signingkey = ABCD
+`
+
+func tempFile(t *testing.T, text string) (string, func()) {
+	dir, err := ioutil.TempDir("", "gitconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config")
+	if text != "" {
+		ioutil.WriteFile(path, []byte(text), 0600)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestGet(t *testing.T) {
+	path, cleanup := tempFile(t, sample)
+	defer cleanup()
+
+	expected := map[string]string{
+		"core.editor":        "vim",
+		"user.name":          "Lindsay Bluth and Michael Bluth",
+		"USER.Email":         "git+lb+mb@example.com",
+		"remote.origin.url":  "https://example.com/bluth/banana-stand.git",
+		"remote.origin.bare": "true",
+		"alias.lg":           "log --graph  # not a comment",
+		"alias.co":           "checkout",
+		"user.signingkey":    "ABCD",
+	}
+	for key, value := range expected {
+		if got, err := Get(path, key); err != nil || got != value {
+			t.Errorf("expected %s to be %q, got %q, %v", key, value, got, err)
+		}
+	}
+	if _, err := Get(path, "remote.ORIGIN.url"); err != ErrNotSet {
+		t.Errorf("expected subsections to be case sensitive, got %v", err)
+	}
+	if _, err := Get(path, "user.missing"); err != ErrNotSet {
+		t.Errorf("expected ErrNotSet, got %v", err)
+	}
+	if _, err := Get(filepath.Join(filepath.Dir(path), "missing"), "user.name"); !os.IsNotExist(err) {
+		t.Errorf("expected a missing file to be reported, got %v", err)
+	}
+}
+
+func TestSet(t *testing.T) {
+	path, cleanup := tempFile(t, sample)
+	defer cleanup()
+
+	if err := Set(path, "user.name", "Michael Bluth"); err != nil {
+		t.Fatalf("expected no error replacing a key, got %v", err)
+	}
+	if err := Set(path, "user.email", "mb@example.com"); err != nil {
+		t.Fatalf("expected no error replacing a key, got %v", err)
+	}
+	if err := Set(path, "remote.origin.url", "git@example.com:bluth/banana-stand.git"); err != nil {
+		t.Fatalf("expected no error replacing a continued value, got %v", err)
+	}
+	if err := Set(path, "user.signoff", " spaced; out "); err != nil {
+		t.Fatalf("expected no error adding a key, got %v", err)
+	}
+	if err := Set(path, "commit.template", `C:\pair\template`); err != nil {
+		t.Fatalf("expected no error adding a section, got %v", err)
+	}
+	buf, _ := ioutil.ReadFile(path)
+	expected := `# Written by hand
+[core]
+	editor = vim ; for now
+[user]
+	name = Michael Bluth
+	email = mb@example.com
+[remote "origin"]
+	url = git@example.com:bluth/banana-stand.git
+	bare
+[alias]
+	lg = "log --graph  # not a comment"
+	co = checkout
+[user]
+	signingkey = ABCD
+	signoff = " spaced; out "
+[commit]
+	template = C:\\pair\\template
+`
+	if string(buf) != expected {
+		t.Fatalf("expected only the changed lines to change, got\n%s", buf)
+	}
+	for key, value := range map[string]string{"user.signoff": " spaced; out ", "commit.template": `C:\pair\template`} {
+		if got, _ := Get(path, key); got != value {
+			t.Errorf("expected %s to read back as %q, got %q", key, value, got)
+		}
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the file's mode to be kept, got %v", info.Mode())
+	}
+}
+
+func TestSetNewFile(t *testing.T) {
+	path, cleanup := tempFile(t, "")
+	defer cleanup()
+
+	if err := Set(path, "user.name", "Michael Bluth"); err != nil {
+		t.Fatalf("expected no error creating the file, got %v", err)
+	}
+	if err := Set(path, "user.email", "mb@example.com"); err != nil {
+		t.Fatalf("expected no error adding to the file, got %v", err)
+	}
+	if buf, _ := ioutil.ReadFile(path); string(buf) != "[user]\n\tname = Michael Bluth\n\temail = mb@example.com\n" {
+		t.Fatalf("expected a new section, got %q", buf)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	for _, text := range []string{
+		"[user.name]\n\tfirst = Michael\n",
+		"[user] name = Michael Bluth\n",
+		"[user]\n\tname = one\n\tname = two\n",
+		"[user]\n\tname = \"unterminated\n",
+		"name = outside\n",
+	} {
+		path, cleanup := tempFile(t, text)
+		err := Set(path, "user.name", "Michael Bluth")
+		if _, ok := err.(*SyntaxError); !ok {
+			t.Errorf("expected a *SyntaxError setting user.name in %q, got %v", text, err)
+		}
+		cleanup()
+	}
+}
+
+func TestSetAll(t *testing.T) {
+	path, cleanup := tempFile(t, "[user]\n\tname = Lindsay Bluth\n")
+	defer cleanup()
+
+	if err := SetAll(path, "user.name", "Michael Bluth", "user.email", "mb@example.com"); err != nil {
+		t.Fatalf("expected no error setting both keys, got %v", err)
+	}
+	if buf, _ := ioutil.ReadFile(path); string(buf) != "[user]\n\tname = Michael Bluth\n\temail = mb@example.com\n" {
+		t.Errorf("expected both keys to be set, got %q", buf)
+	}
+	if buf, _ := ioutil.ReadFile(lockedfile.Backup(path)); string(buf) != "[user]\n\tname = Lindsay Bluth\n" {
+		t.Errorf("expected the file from before both keys were set to be kept, got %q", buf)
+	}
+}
+
+func TestApply(t *testing.T) {
+	path, cleanup := tempFile(t, sample)
+	defer cleanup()
+
+	err := Apply(path,
+		Change{Key: "user.name", Value: "Michael Bluth"},
+		Change{Key: "remote.origin.url", Unset: true},
+		Change{Key: "user.signingkey", Unset: true},
+		Change{Key: "committer.name", Unset: true},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	buf, _ := ioutil.ReadFile(path)
+	expected := `# Written by hand
+[core]
+	editor = vim ; for now
+[user]
+	name = Michael Bluth
+	email = "git+lb+mb@example.com"
+[remote "origin"]
+	bare
+[alias]
+	lg = "log --graph  # not a comment"
+	co = checkout
+[user]
+`
+	if string(buf) != expected {
+		t.Fatalf("expected the unset keys to be removed, got\n%s", buf)
+	}
+}
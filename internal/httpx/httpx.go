@@ -0,0 +1,151 @@
+// Package httpx is the HTTP client shared by pair's integrations with GitHub,
+// GitLab, Jira, Slack and team rosters. It retries failed requests with
+// exponential backoff, honors Retry-After, and turns rate limiting into a
+// clear error.
+package httpx
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Client sends requests, retrying those which fail for reasons likely to
+// pass: rate limiting, server errors and timeouts.
+type Client struct {
+	HTTP    *http.Client  // Client used for each attempt
+	Retries int           // How many times to retry a request
+	Backoff time.Duration // Wait before the first retry, doubling after each
+	MaxWait time.Duration // Longest wait for a rate limit to reset
+
+	sleep func(time.Duration) // time.Sleep, replaceable in tests
+}
+
+// New creates a Client whose attempts each time out after timeout.
+func New(timeout time.Duration) *Client {
+	return &Client{
+		HTTP:    &http.Client{Timeout: timeout},
+		Retries: 3,
+		Backoff: 500 * time.Millisecond,
+		MaxWait: time.Minute,
+		sleep:   time.Sleep,
+	}
+}
+
+// RateLimitError means a service refused a request for exceeding its rate
+// limit, and the limit won't reset soon enough to wait for it.
+type RateLimitError struct {
+	Host  string    // The service. e.g. api.github.com
+	Reset time.Time // When the limit resets, if known
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("rate limited by %s; try again later", e.Host)
+	}
+	return fmt.Sprintf("rate limited by %s; try again after %s", e.Host, e.Reset.Local().Format("15:04:05"))
+}
+
+// Get fetches url.
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends req, retrying as needed. Requests with a body are only retried if
+// it can be replayed, as it can for bodies given to http.NewRequest as a
+// *bytes.Buffer, *bytes.Reader or *strings.Reader. Responses which are still
+// failing after the last retry are returned as they are, except rate limits
+// which become a *RateLimitError.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	backoff := c.Backoff
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := c.HTTP.Do(req)
+		last := attempt >= c.Retries || (req.Body != nil && req.GetBody == nil)
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if nerr, ok := err.(net.Error); !ok || !nerr.Timeout() || !idempotent(req.Method) || last {
+				return nil, err
+			}
+			wait = backoff
+		case rateLimited(resp):
+			reset, ok := resetTime(resp, time.Now())
+			if last || (ok && time.Until(reset) > c.MaxWait) {
+				resp.Body.Close()
+				return nil, &RateLimitError{Host: req.URL.Host, Reset: reset}
+			}
+			wait = backoff
+			if ok {
+				wait = time.Until(reset)
+			}
+		case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+			if last {
+				return resp, nil
+			}
+			wait = backoff
+			if reset, ok := resetTime(resp, time.Now()); ok && time.Until(reset) <= c.MaxWait {
+				wait = time.Until(reset)
+			}
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+		if c.sleep != nil {
+			c.sleep(wait)
+		} else {
+			time.Sleep(wait)
+		}
+		backoff *= 2
+	}
+}
+
+// rateLimited reports whether resp refuses a request for exceeding a rate
+// limit: 429, or 403 with GitHub's rate limit headers.
+func rateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != "")
+}
+
+// resetTime returns when resp says to try again, from Retry-After as seconds
+// or a date, or else GitHub's X-RateLimit-Reset.
+func resetTime(resp *http.Response, now time.Time) (time.Time, bool) {
+	if after := resp.Header.Get("Retry-After"); after != "" {
+		if seconds, err := strconv.Atoi(after); err == nil {
+			return now.Add(time.Duration(seconds) * time.Second), true
+		}
+		if t, err := http.ParseTime(after); err == nil {
+			return t, true
+		}
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(reset, 0), true
+	}
+	return time.Time{}, false
+}
+
+// idempotent reports whether a request with method can safely be sent again
+// when it's unknown whether the first attempt arrived.
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
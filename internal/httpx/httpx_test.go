@@ -0,0 +1,110 @@
+package httpx
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testClient returns a Client which records how long it would have waited
+// instead of sleeping.
+func testClient(waits *[]time.Duration) *Client {
+	c := New(time.Second)
+	c.sleep = func(d time.Duration) { *waits = append(*waits, d) }
+	return c
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != `{"text":"hi"}` {
+			t.Fatalf("expected the body on every attempt, got %q", body)
+		}
+		if attempts < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	req, _ := http.NewRequest("POST", server.URL, strings.NewReader(`{"text":"hi"}`))
+	resp, err := testClient(&waits).Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected success after retrying, got %v, %v", resp, err)
+	}
+	resp.Body.Close()
+	if len(waits) != 2 || waits[0] != 500*time.Millisecond || waits[1] != time.Second {
+		t.Fatalf("expected exponential backoff between attempts, got %v", waits)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	resp, err := testClient(&waits).Get(server.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected success after waiting, got %v, %v", resp, err)
+	}
+	resp.Body.Close()
+	if len(waits) != 1 || waits[0] < 6*time.Second || waits[0] > 7*time.Second {
+		t.Fatalf("expected to wait as long as Retry-After says, got %v", waits)
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	_, err := testClient(&waits).Get(server.URL)
+	rateErr, ok := err.(*RateLimitError)
+	if !ok {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+	if rateErr.Reset.Unix() != reset || len(waits) != 0 {
+		t.Fatalf("expected to give up straight away until the reset, got %v after %v", rateErr, waits)
+	}
+	if !strings.HasPrefix(rateErr.Error(), "rate limited by 127.0.0.1:") {
+		t.Fatalf("expected the error to name the service, got %s", rateErr)
+	}
+}
+
+func TestNoRetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	resp, err := testClient(&waits).Get(server.URL)
+	if err != nil || resp.StatusCode != http.StatusNotFound || attempts != 1 {
+		t.Fatalf("expected a client error to be returned without retrying, got %v, %v after %d attempts", resp, err, attempts)
+	}
+	resp.Body.Close()
+}
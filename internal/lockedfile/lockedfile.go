@@ -0,0 +1,130 @@
+// Package lockedfile replaces files atomically while holding a lock, so two
+// pair commands running at once can't interleave their writes to the git
+// config or pair's own config, and a crash part way through never leaves a
+// truncated file behind. The previous version of each file is kept beside
+// it for `pair undo`.
+package lockedfile
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Timeout is how long Lock waits for another process to release a lock.
+var Timeout = 10 * time.Second
+
+// LockPath returns the file locked on behalf of path. It's separate from path
+// because path itself is replaced on every write, and it's never removed,
+// since removing it would let a waiting process lock a file nobody else will
+// see.
+func LockPath(path string) string {
+	return path + ".pair.lock"
+}
+
+// Backup returns where Write keeps the previous version of path.
+func Backup(path string) string {
+	return path + ".bak"
+}
+
+// Lock takes an exclusive lock on path, waiting up to Timeout for whoever has
+// it to finish. Call the returned func to release it.
+func Lock(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(LockPath(path), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(Timeout)
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out waiting for another pair command to finish writing %s", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return func() {
+		unlock(f)
+		f.Close()
+	}, nil
+}
+
+// Write replaces the file at path with data by writing a temporary file next
+// to it and renaming it into place, copying the old contents to Backup(path)
+// first. The caller should hold the lock on path. perm is used for a new
+// file; an existing file keeps its mode. Writing what's already there does
+// nothing, so the backup stays the last real change.
+func Write(path string, data []byte, perm os.FileMode) error {
+	old, err := ioutil.ReadFile(path)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if exists && bytes.Equal(old, data) {
+		return nil
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if exists {
+		if err := ioutil.WriteFile(Backup(path), old, perm); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Update locks path and replaces its contents with what update returns for
+// the current contents, which are nil if the file doesn't exist yet.
+func Update(path string, perm os.FileMode, update func([]byte) ([]byte, error)) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	old, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data, err := update(old)
+	if err != nil {
+		return err
+	}
+	return Write(path, data, perm)
+}
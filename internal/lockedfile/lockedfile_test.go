@@ -0,0 +1,97 @@
+package lockedfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "lockedfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestWrite(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "nested", "config")
+
+	if err := Write(path, []byte("one\n"), 0600); err != nil {
+		t.Fatalf("expected no error creating the file, got %v", err)
+	}
+	if _, err := os.Stat(Backup(path)); !os.IsNotExist(err) {
+		t.Errorf("expected no backup of a new file, got %v", err)
+	}
+	if err := Write(path, []byte("two\n"), 0644); err != nil {
+		t.Fatalf("expected no error replacing the file, got %v", err)
+	}
+	if err := Write(path, []byte("two\n"), 0644); err != nil {
+		t.Fatalf("expected no error rewriting the file, got %v", err)
+	}
+	if buf, _ := ioutil.ReadFile(path); string(buf) != "two\n" {
+		t.Errorf("expected the new contents, got %q", buf)
+	}
+	if buf, _ := ioutil.ReadFile(Backup(path)); string(buf) != "one\n" {
+		t.Errorf("expected the backup to keep the last change, got %q", buf)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected the file's mode to be kept, got %v", info.Mode())
+	}
+	entries, _ := ioutil.ReadDir(filepath.Dir(path))
+	if len(entries) != 2 {
+		t.Errorf("expected only the file and its backup, got %d files", len(entries))
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "config")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := Update(path, 0644, func(buf []byte) ([]byte, error) {
+				return append(buf, "x\n"...), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if buf, _ := ioutil.ReadFile(path); strings.Count(string(buf), "x\n") != 20 {
+		t.Errorf("expected every update to be kept, got %q", buf)
+	}
+}
+
+func TestLockTimeout(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "config")
+	defer func(timeout time.Duration) { Timeout = timeout }(Timeout)
+	Timeout = 100 * time.Millisecond
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatalf("expected no error locking, got %v", err)
+	}
+	if _, err := Lock(path); err == nil {
+		t.Fatalf("expected a second lock to time out")
+	}
+	unlock()
+	unlock, err = Lock(path)
+	if err != nil {
+		t.Fatalf("expected the lock to be free again, got %v", err)
+	}
+	unlock()
+}
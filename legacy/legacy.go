@@ -0,0 +1,582 @@
+// Package legacy implements the original pair commands, which keep the git
+// author in ~/.gitconfig_local and look people up in ~/.pairs: `pair USER1
+// USER2`, `pair` on its own and `pair -b BRANCH`.
+package legacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/audit"
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/i18n"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/trailer"
+	"github.com/keeferrourke/pair/tui"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/yaml.v1"
+)
+
+// GitConfigPath returns the git config file which holds the author info:
+// $PAIR_GIT_CONFIG, or else ~/.gitconfig_local.
+func GitConfigPath() string {
+	if configFile := os.ExpandEnv("$PAIR_GIT_CONFIG"); configFile != "" {
+		return configFile
+	}
+	return os.ExpandEnv("$HOME/.gitconfig_local")
+}
+
+// PairsPath returns the pairs files, separated by colons: $PAIR_FILE, or
+// else ~/.pairs.
+func PairsPath() string {
+	if pairsFile := os.ExpandEnv("$PAIR_FILE"); pairsFile != "" {
+		return pairsFile
+	}
+	return os.ExpandEnv("$HOME/.pairs")
+}
+
+// Pair sets the git author to the users in the pairs file and prints it, as
+// `pair USER1 USER2` always has. It reports whether it succeeded, having
+// printed any error.
+func Pair(usernames []string) bool {
+	return setAndPrintNewPairedUsers(PairsPath(), GitConfigPath(), requireEmailTemplate(), usernames)
+}
+
+// Current prints the git author, first switching to the pair last used in
+// the current repository if it differs, as `pair` on its own always has. It
+// reports whether it succeeded, having printed any error.
+func Current() bool {
+	configFile := GitConfigPath()
+	if usernames := lastPairInRepo(configFile); len(usernames) > 0 {
+		return setAndPrintNewPairedUsers(PairsPath(), configFile, requireEmailTemplate(), usernames)
+	}
+	return printCurrentPairedUsers(configFile)
+}
+
+// Branch switches to branch, creating it if needed, prefixed with the
+// usernames of the current pair unless prefix is false, as `pair -b BRANCH`
+// always has. A new branch starts from base, or else $PAIR_DEFAULT_BRANCH or
+// the repository's default branch, and tracks it if track is set. It reports
+// whether it succeeded, having printed any error.
+func Branch(branch string, prefix bool, base string, track bool) bool {
+	if base == "" {
+		base = os.Getenv("PAIR_DEFAULT_BRANCH")
+	}
+	if base == "" {
+		base = vcs.DefaultBranch()
+	}
+	if !prefix {
+		return checkout(branch, base, track)
+	}
+	return switchToPairBranch(GitConfigPath(), branch, requireEmailTemplate(), base, track)
+}
+
+// Environment documents the environment variables which configure pair, for
+// its help.
+const Environment = `ENVIRONMENT:
+   PAIR_FILE        YAML file with a map of usernames to full names (default: ~/.pairs).
+                    Separate several files with colons; later files take precedence.
+                    Files may be http(s) URLs, cached for an hour and used offline.
+   PAIR_TEAM_URL    URL of an organization roster merged beneath your own.
+   PAIR_TITLE       Set to 1 to show the pair and ticket in the terminal title.
+   PAIR_DEFAULT_BRANCH
+                    Branch that new branches start from (default: origin/HEAD, else main or master).
+   PAIR_STRICT      Set to 1 to require usernames in full rather than a unique prefix.
+   PAIR_LOG         Extra sinks for the audit log: syslog, journald or file:PATH.
+   PAIR_DNS_TIMEOUT How long to wait for reverse DNS when deriving PAIR_EMAIL (default: 2s).
+   PAIR_GIT_CONFIG  Git config file for reading and writing author info (default: ~/.gitconfig_local).
+   PAIR_GIT_BIN     Git binary to run, e.g. a wrapper script (default: git on your PATH).
+   PAIR_GIT_ARGS    Arguments passed to git before every command, e.g. -c protocol.file.allow=always.
+   PAIR_EMAIL       Email address to base derived email addresses on
+                    (default: git@ your domain, from reverse DNS or recent commits).
+`
+
+// requireEmailTemplate returns $PAIR_EMAIL, or else the default template from
+// reverse DNS or the repository's history, exiting if none is available. It's
+// only called by commands which derive emails, so nothing else waits on the
+// network.
+func requireEmailTemplate() string {
+	if emailTemplate := os.ExpandEnv("$PAIR_EMAIL"); emailTemplate != "" {
+		return emailTemplate
+	}
+	emailTemplate, err := GetDefaultEmailTemplate()
+	if err != nil {
+		emailTemplate, err = historyEmailTemplate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error: please set $PAIR_EMAIL to configure the pair email template")
+		os.Exit(1)
+	}
+	return emailTemplate
+}
+
+// lastPairInRepo returns the usernames of the last pair set in the current
+// repository, if it differs from the current git author.
+func lastPairInRepo(configFile string) []string {
+	repo := vcs.TopLevel()
+	if repo == "" {
+		return nil
+	}
+	sessions, err := session.All()
+	if err != nil {
+		return nil
+	}
+	last := session.LastIn(sessions, repo)
+	if last == nil {
+		return nil
+	}
+	if email, err := gitConfig(configFile, "user.email"); err == nil && email == last.Email {
+		return nil
+	}
+	var usernames []string
+	for _, a := range last.Authors {
+		usernames = append(usernames, a.Alias)
+	}
+	return usernames
+}
+
+func printCurrentPairedUsers(configFile string) bool {
+	name, err := gitConfig(configFile, "user.name")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to get current git author name: %v\n", err)
+		return false
+	}
+
+	email, err := gitConfig(configFile, "user.email")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to get current git author email: %v\n", err)
+		return false
+	}
+
+	fmt.Printf("%s <%s>\n", name, email)
+	return true
+}
+
+func setAndPrintNewPairedUsers(pairsFile string, configFile string, emailTemplate string, usernames []string) bool {
+	authorMap, conflicts, err := cfg.ReadLegacyFiles(cfg.SplitLegacyPaths(pairsFile))
+	if _, ok := err.(*cfg.StaleTeamError); ok {
+		fmt.Fprintf(os.Stderr, "warning: pairs file: %v\n", err)
+		err = nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to read authors from file (%s): %v", pairsFile, err)
+		return false
+	}
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", c)
+	}
+	var spinner *tui.Spinner
+	if os.Getenv("PAIR_TEAM_URL") != "" && tui.IsTerminal(os.Stderr) {
+		spinner = tui.StartSpinner(os.Stderr, i18n.T("Fetching the team roster"))
+	}
+	err = cfg.MergeLegacyTeam(authorMap)
+	spinner.Stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: team roster: %v\n", err)
+	}
+
+	// Guests given as "Name <email>" needn't be in the pairs file.
+	guests := make(map[string]*cfg.Author)
+	for i, username := range usernames {
+		guest, ok := cfg.ParseGuest(username)
+		if !ok {
+			continue
+		}
+		if name, taken := authorMap[guest.Alias]; taken && name != guest.Name {
+			fmt.Fprintf(os.Stderr, "error: guest %s would have the same username as %s (%s)\n", username, name, guest.Alias)
+			return false
+		}
+		authorMap[guest.Alias] = guest.Name
+		guests[guest.Alias] = guest
+		usernames[i] = guest.Alias
+	}
+
+	// Unless $PAIR_STRICT, a username can be shortened to a unique prefix of
+	// it or its full name.
+	if strict, _ := strconv.ParseBool(os.Getenv("PAIR_STRICT")); !strict {
+		for i, username := range usernames {
+			match, err := cfg.MatchAlias(username, authorMap)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				return false
+			}
+			usernames[i] = match
+		}
+	}
+
+	sort.Strings(usernames)
+
+	email, err := emailAddressForUsernames(emailTemplate, usernames)
+	if guest := guests[usernames[0]]; guest != nil && len(usernames) == 1 {
+		email = guest.Email
+	}
+
+	var name string
+
+	if err == nil {
+		name, err = namesForUsernames(usernames, authorMap)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return false
+	}
+
+	backup, err := vcs.BackupIdentity(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to back up git author info changed outside pair: %v\n", err)
+		return false
+	}
+	if backup != "" {
+		fmt.Fprintf(os.Stderr, "warning: git author info in %s was changed outside pair; the previous file is backed up to %s\n", configFile, backup)
+	}
+
+	// Both keys in one write, so the backup undo restores is a whole identity.
+	err = vcs.SetIdentity(name, email)
+	if _, ok := err.(*audit.SinkError); ok {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to set current git author: %v\n", err)
+		return false
+	}
+
+	err = recordSession(name, email, emailTemplate, usernames, authorMap, guests)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to record pairing session: %v\n", err)
+		return false
+	}
+
+	return printCurrentPairedUsers(configFile)
+}
+
+// recordSession saves the new pair as the current session and refreshes
+// anything derived from it, such as the commit template. Guests keep their
+// own emails.
+func recordSession(name string, email string, emailTemplate string, usernames []string, authorMap map[string]string, guests map[string]*cfg.Author) error {
+	var authors []*cfg.Author
+	for _, username := range usernames {
+		if guest := guests[username]; guest != nil {
+			authors = append(authors, guest)
+			continue
+		}
+		authorEmail, err := emailAddressForUsernames(emailTemplate, []string{username})
+		if err != nil {
+			return err
+		}
+		authors = append(authors, &cfg.Author{Name: authorMap[username], Alias: username, Email: authorEmail})
+	}
+
+	s, err := session.Start(name, email, authors)
+	if err != nil {
+		return err
+	}
+
+	if showTitle, _ := strconv.ParseBool(os.Getenv("PAIR_TITLE")); showTitle {
+		tui.SetTitle(tui.PairTitle(s.Aliases(), vcs.Ticket(vcs.CurrentBranch())))
+	}
+
+	return trailer.UpdateTemplate(s)
+}
+
+func switchToPairBranch(configFile string, branch string, emailTemplate string, base string, track bool) bool {
+	email, err := gitConfig(configFile, "user.email")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to get current git author email from config file: %s\n", configFile)
+		return false
+	}
+
+	templateUsername, _, err := SplitEmail(emailTemplate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to parse template email address: %s\n", emailTemplate)
+		return false
+	}
+
+	usernames, _, err := SplitEmail(email)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to parse email address: %s\n", email)
+		return false
+	}
+
+	// Remove any preceding e.g. "git+" from "git+lb+mb".
+	usernames = strings.TrimPrefix(usernames, templateUsername+"+")
+
+	return checkout(usernames+"/"+branch, base, track)
+}
+
+// checkout switches to branch, creating it from base if it doesn't exist,
+// with base as its upstream if track is set.
+func checkout(branch string, base string, track bool) bool {
+	cmd := vcs.Command("rev-parse", branch)
+	err := cmd.Run()
+
+	args := []string{"checkout"}
+
+	if err != nil {
+		// The branch does not exist, so create it with the `-b' flag.
+		args = append(args, "-b", branch)
+		if track {
+			args = append(args, "--track")
+		}
+		args = append(args, base)
+	} else {
+		// The branch already exists, so just switch to it.
+		args = append(args, branch)
+	}
+
+	cmd = vcs.Command(args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: unable to check out git branch: %s\n", branch)
+		return false
+	}
+
+	return true
+}
+
+// defaultDNSTimeout bounds reverse DNS lookups unless $PAIR_DNS_TIMEOUT says
+// otherwise, so a slow resolver can't hang pair.
+const defaultDNSTimeout = 2 * time.Second
+
+// dnsTimeout returns $PAIR_DNS_TIMEOUT (e.g. 500ms), or the default.
+func dnsTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(os.Getenv("PAIR_DNS_TIMEOUT")); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultDNSTimeout
+}
+
+// GetDefaultEmailTemplate determines a default email template from the current network.
+// The lookup gives up after the DNS timeout, or when interrupted with Ctrl-C.
+func GetDefaultEmailTemplate() (string, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, dnsTimeout())
+	defer cancel()
+
+	dnsNames, err := LookupReverseDNSNamesByInterface(ctx, "en0")
+	if err != nil {
+		return "", err
+	}
+
+	for _, dnsName := range dnsNames {
+		hostnameParts := strings.Split(dnsName, ".")
+		if len(hostnameParts) >= 3 {
+			return "git@" + strings.Join(hostnameParts[len(hostnameParts)-3:len(hostnameParts)-1], "."), nil
+		}
+	}
+
+	return "", errors.New("expected a hostname to be a fully-qualified domain name: " + strings.Join(dnsNames, ","))
+}
+
+// historyCommits is how many recent commits historyEmailTemplate looks at.
+const historyCommits = 500
+
+// historyEmailTemplate proposes git@ at the email domain most used by people
+// in the pairs files across the repository's recent commits.
+func historyEmailTemplate() (string, error) {
+	authorMap, _, err := cfg.ReadLegacyFiles(cfg.LegacyPaths())
+	if _, ok := err.(*cfg.StaleTeamError); err != nil && !ok {
+		return "", err
+	}
+	commits, err := vcs.RecentCommits(historyCommits)
+	if err != nil {
+		return "", err
+	}
+	domain := commonRosterDomain(authorMap, commits)
+	if domain == "" {
+		return "", errors.New("no commits by anyone in the pairs file")
+	}
+	return "git@" + domain, nil
+}
+
+// commonRosterDomain returns the most common email domain among commits by
+// people in authorMap, matched by name or by username, or the empty string.
+// Ties go to the domain seen first.
+func commonRosterDomain(authorMap map[string]string, commits []*vcs.Commit) string {
+	names := make(map[string]bool)
+	for _, name := range authorMap {
+		names[name] = true
+	}
+
+	counts := make(map[string]int)
+	best := ""
+	for _, c := range commits {
+		username, domain, err := SplitEmail(c.Email)
+		if err != nil || domain == "" {
+			continue
+		}
+		_, known := authorMap[username]
+		for _, name := range strings.Split(c.Author, " and ") {
+			known = known || names[name]
+		}
+		if !known {
+			continue
+		}
+		domain = strings.ToLower(domain)
+		counts[domain]++
+		if counts[domain] > counts[best] {
+			best = domain
+		}
+	}
+	return best
+}
+
+// LookupReverseDNSNamesByInterface finds the DNS names for the given network interface (e.g. "en0").
+// It stops early with ctx's error if ctx is done first.
+func LookupReverseDNSNamesByInterface(ctx context.Context, interfaceName string) ([]string, error) {
+	iface, err := net.InterfaceByName(interfaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ip, _, err := net.ParseCIDR(addr.String()); err == nil {
+			ips = append(ips, ip)
+		}
+	}
+
+	for _, ip := range reverseLookupOrder(ips) {
+		names, err := net.DefaultResolver.LookupAddr(ctx, ip.String())
+		if err == nil && len(names) > 0 {
+			return names, nil
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("reverse DNS lookup for %s: %v", ip, ctx.Err())
+		}
+	}
+
+	return nil, nil
+}
+
+// reverseLookupOrder returns the addresses worth a reverse DNS lookup, most
+// promising first: public IPv4 and IPv6 addresses, then private IPv4 ones.
+// Loopback, link-local and unique local IPv6 addresses rarely have PTR records
+// and are skipped.
+func reverseLookupOrder(ips []net.IP) []net.IP {
+	var public, private []net.IP
+	for _, ip := range ips {
+		switch {
+		case !ip.IsGlobalUnicast():
+		case ip.To4() == nil && ip[0]&0xfe == 0xfc:
+			// Unique local (fc00::/7).
+		case isPrivateIPv4(ip):
+			private = append(private, ip)
+		default:
+			public = append(public, ip)
+		}
+	}
+	return append(public, private...)
+}
+
+// isPrivateIPv4 reports whether ip is in one of the RFC 1918 ranges.
+func isPrivateIPv4(ip net.IP) bool {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return false
+	}
+	return ip4[0] == 10 ||
+		ip4[0] == 172 && ip4[1]&0xf0 == 16 ||
+		ip4[0] == 192 && ip4[1] == 168
+}
+
+// SplitEmail splits an email address into the username and the host.
+// An error is returned if the email does not contain a "@" character.
+func SplitEmail(email string) (string, string, error) {
+	parts := strings.Split(email, "@")
+	if len(parts) != 2 {
+		return "", "", errors.New("invalid email address: " + email)
+	}
+	return parts[0], parts[1], nil
+}
+
+// gitConfig retrieves the value of a property from a specific git config file.
+// It returns the value as a string along with any error that occurred.
+func gitConfig(configFile string, property string) (string, error) {
+	return vcs.GetConfig(configFile, property)
+}
+
+// setGitConfig sets the value of a property within a specific git config file,
+// recording the change in the audit log. It returns any error that occurred.
+func setGitConfig(configFile string, property string, value string) error {
+	err := vcs.SetConfig(configFile, property, value)
+	if _, ok := err.(*audit.SinkError); ok {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		return nil
+	}
+	return err
+}
+
+// readAuthorsByUsername gets a map of username -> full name for possible git authors.
+// pairs should be reader open to data containing a YAML map.
+func readAuthorsByUsername(pairs io.Reader) (map[string]string, error) {
+	var authorMap map[string]string
+
+	bytes, err := ioutil.ReadAll(pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(bytes, &authorMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return authorMap, nil
+}
+
+// emailAddressForUsernames generates an email address from a list of usernames.
+// For example, given "michael" and "lindsay" returns "michael+lindsay".
+func emailAddressForUsernames(emailTemplate string, usernames []string) (string, error) {
+	user, host, err := SplitEmail(emailTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(usernames) {
+	case 0:
+		return emailTemplate, nil
+	case 1:
+		return fmt.Sprintf("%s@%s", usernames[0], host), nil
+	default:
+		return fmt.Sprintf("%s+%s@%s", user, strings.Join(usernames, "+"), host), nil
+	}
+}
+
+// namesForUsernames joins names corresponding to usernames with " and ".
+// For example, given "michael" and "lindsay" returns "Michael Bluth and Lindsay Bluth".
+func namesForUsernames(usernames []string, authorMap map[string]string) (string, error) {
+	if len(usernames) == 0 {
+		return "", nil
+	}
+
+	var names []string
+
+	for _, username := range usernames {
+		name, ok := authorMap[username]
+		if !ok {
+			return "", errors.New("no such username: " + username)
+		}
+		names = append(names, name)
+	}
+
+	return strings.Join(names, " and "), nil
+}
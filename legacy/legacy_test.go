@@ -1,15 +1,35 @@
-package main
+package legacy
 
 import (
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/keeferrourke/pair/internal/lockedfile"
+	"github.com/keeferrourke/pair/vcs"
 )
 
+func TestMain(m *testing.M) {
+	// Keep session state written by the tests out of the real data and cache
+	// directories.
+	dataDir, err := ioutil.TempDir("", "pair-data")
+	if err != nil {
+		log.Fatal("unable to create temporary data directory")
+	}
+	os.Setenv("XDG_DATA_HOME", dataDir)
+	os.Setenv("XDG_CACHE_HOME", dataDir)
+
+	code := m.Run()
+	os.RemoveAll(dataDir)
+	os.Exit(code)
+}
+
 func TestNamesForUsernames(t *testing.T) {
 	names, err := namesForUsernames([]string{}, map[string]string{})
 	if names != "" {
@@ -41,7 +61,7 @@ func TestNamesForUsernames(t *testing.T) {
 	}
 }
 
-func ExampleEmailAddressForUsernames() {
+func Example_emailAddressForUsernames() {
 	email, _ := emailAddressForUsernames("git@example.com", []string{})
 	fmt.Println(email)
 	email, _ = emailAddressForUsernames("git@example.com", []string{"mb"})
@@ -81,6 +101,40 @@ func TestReadAuthorsByUsername(t *testing.T) {
 	}
 }
 
+func TestReverseLookupOrder(t *testing.T) {
+	var ips []net.IP
+	for _, addr := range []string{"fe80::1", "10.0.0.5", "127.0.0.1", "fd12:3456::1", "2001:db8::5", "203.0.113.7"} {
+		ips = append(ips, net.ParseIP(addr))
+	}
+
+	var got []string
+	for _, ip := range reverseLookupOrder(ips) {
+		got = append(got, ip.String())
+	}
+	if strings.Join(got, " ") != "2001:db8::5 203.0.113.7 10.0.0.5" {
+		t.Fatalf("expected public addresses before private ones and no link-local or ULA, got %v", got)
+	}
+}
+
+func TestCommonRosterDomain(t *testing.T) {
+	authorMap := map[string]string{"lb": "Lindsay Bluth", "mb": "Michael Bluth"}
+	commits := []*vcs.Commit{
+		{Author: "Buster Bluth", Email: "buster@gmail.com"},
+		{Author: "Buster Bluth", Email: "buster@gmail.com"},
+		{Author: "Buster Bluth", Email: "buster@gmail.com"},
+		{Author: "Lindsay Bluth", Email: "lindsay@gmail.com"},
+		{Author: "Lindsay Bluth and Michael Bluth", Email: "git+lb+mb@bluth.com"},
+		{Author: "Michael", Email: "mb@Bluth.com"},
+	}
+
+	if domain := commonRosterDomain(authorMap, commits); domain != "bluth.com" {
+		t.Fatalf("expected bluth.com, got %q", domain)
+	}
+	if domain := commonRosterDomain(map[string]string{}, commits); domain != "" {
+		t.Fatalf("expected no domain for an empty roster, got %q", domain)
+	}
+}
+
 func ExampleSplitEmail() {
 	user, host, err := SplitEmail("a@b.com")
 	fmt.Printf("error=%v user=%s host=%s\n", err, user, host)
@@ -118,7 +172,31 @@ func TestGitConfig(t *testing.T) {
 	}
 }
 
-func ExamplePrintCurrentPairedUsers() {
+func TestBackupExternalIdentity(t *testing.T) {
+	tempGitConfigFile, err := ioutil.TempFile(os.TempDir(), "pair-git-config")
+	if err != nil {
+		t.Fatal("unable to create temporary git config")
+	}
+	tempGitConfigPath := tempGitConfigFile.Name()
+	defer os.Remove(tempGitConfigPath) // clean up
+
+	setGitConfig(tempGitConfigPath, "user.name", "Michael Bluth")
+	if backup, err := vcs.BackupIdentity(tempGitConfigPath); err != nil || backup != "" {
+		t.Fatalf("expected no backup when only pair changed the file, got %q, %v", backup, err)
+	}
+
+	vcs.Command("config", "--file", tempGitConfigPath, "user.name", "Lindsay Bluth").Run()
+	backup, err := vcs.BackupIdentity(tempGitConfigPath)
+	if err != nil || backup == "" {
+		t.Fatalf("expected a backup after an outside change, got %q, %v", backup, err)
+	}
+	defer os.Remove(backup) // clean up
+	if value, _ := gitConfig(backup, "user.name"); value != "Lindsay Bluth" {
+		t.Fatalf("expected the backup to keep the outside change, got %s", value)
+	}
+}
+
+func Example_printCurrentPairedUsers() {
 	tempGitConfigFile, err := ioutil.TempFile(os.TempDir(), "pair-git-config")
 	if err != nil {
 		log.Fatal("unable to create temporary git config")
@@ -141,7 +219,30 @@ func ExamplePrintCurrentPairedUsers() {
 	// Michael Bluth <mb@example.com>
 }
 
-func ExampleSetAndPrintNewPairedUsers() {
+func TestSwitchBacksUpWholeIdentity(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "pair-switch")
+	defer os.RemoveAll(dir) // clean up
+	pairsFile, gitConfigFile := filepath.Join(dir, "pairs"), filepath.Join(dir, "gitconfig")
+	ioutil.WriteFile(pairsFile, []byte("---\nmb: Michael Bluth\nlb: Lindsay Bluth\n"), 0644)
+	defer os.Setenv("PAIR_GIT_CONFIG", os.Getenv("PAIR_GIT_CONFIG"))
+	os.Setenv("PAIR_GIT_CONFIG", gitConfigFile)
+
+	if !setAndPrintNewPairedUsers(pairsFile, gitConfigFile, "git@example.com", []string{"mb"}) {
+		t.Fatal("expected pairing as mb to succeed")
+	}
+	if !setAndPrintNewPairedUsers(pairsFile, gitConfigFile, "git@example.com", []string{"lb", "mb"}) {
+		t.Fatal("expected pairing as lb and mb to succeed")
+	}
+
+	backup := lockedfile.Backup(gitConfigFile)
+	name, _ := gitConfig(backup, "user.name")
+	email, _ := gitConfig(backup, "user.email")
+	if name != "Michael Bluth" || email != "mb@example.com" {
+		t.Fatalf("expected the backup to hold the previous identity, got %s <%s>", name, email)
+	}
+}
+
+func Example_setAndPrintNewPairedUsers() {
 	tempPairsFile, err := ioutil.TempFile(os.TempDir(), "pair-pairs")
 	if err != nil {
 		log.Fatal("unable to create temporary pairs file")
@@ -153,18 +254,20 @@ func ExampleSetAndPrintNewPairedUsers() {
 	if err != nil {
 		log.Fatal("unable to create temporary git config")
 	}
+	defer os.Setenv("PAIR_GIT_CONFIG", os.Getenv("PAIR_GIT_CONFIG"))
+	os.Setenv("PAIR_GIT_CONFIG", tempGitConfigFile.Name())
 
 	setAndPrintNewPairedUsers(tempPairsFile.Name(), tempGitConfigFile.Name(), "git@example.com", []string{"mb"})
 
 	value, err := gitConfig(tempGitConfigFile.Name(), "user.name")
 	if err != nil {
-		log.Fatal("unable to get git config after setting users: %v", err)
+		log.Fatalf("unable to get git config after setting users: %v", err)
 	}
 	fmt.Printf("user.name=%s\n", value)
 
 	value, err = gitConfig(tempGitConfigFile.Name(), "user.email")
 	if err != nil {
-		log.Fatal("unable to get git config after setting users: %v", err)
+		log.Fatalf("unable to get git config after setting users: %v", err)
 	}
 	fmt.Printf("user.email=%s\n", value)
 
@@ -173,3 +276,26 @@ func ExampleSetAndPrintNewPairedUsers() {
 	// user.name=Michael Bluth
 	// user.email=mb@example.com
 }
+
+func Example_setAndPrintNewPairedUsers_guest() {
+	tempPairsFile, err := ioutil.TempFile(os.TempDir(), "pair-pairs")
+	if err != nil {
+		log.Fatal("unable to create temporary pairs file")
+	}
+	defer os.Remove(tempPairsFile.Name()) // clean up
+	io.WriteString(tempPairsFile, "---\nmb: Michael Bluth")
+	tempPairsFile.Close()
+
+	tempGitConfigFile, err := ioutil.TempFile(os.TempDir(), "pair-git-config")
+	if err != nil {
+		log.Fatal("unable to create temporary git config")
+	}
+	defer os.Remove(tempGitConfigFile.Name()) // clean up
+	defer os.Setenv("PAIR_GIT_CONFIG", os.Getenv("PAIR_GIT_CONFIG"))
+	os.Setenv("PAIR_GIT_CONFIG", tempGitConfigFile.Name())
+
+	setAndPrintNewPairedUsers(tempPairsFile.Name(), tempGitConfigFile.Name(), "git@example.com", []string{"mb", "Guest Contributor <guest@example.org>"})
+
+	// Output:
+	// Guest Contributor and Michael Bluth <git+guest+mb@example.com>
+}
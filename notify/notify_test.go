@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSlack(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	if err := Slack(server.URL, "Time to rotate drivers"); err != nil {
+		t.Fatalf("expected no error posting to the webhook, got %v", err)
+	}
+	if got["text"] != "Time to rotate drivers" {
+		t.Fatalf("expected the message as text, got %v", got)
+	}
+}
+
+func TestSMTPFromEnv(t *testing.T) {
+	defer os.Setenv("PAIR_SMTP_URL", os.Getenv("PAIR_SMTP_URL"))
+	defer os.Setenv("PAIR_SMTP_FROM", os.Getenv("PAIR_SMTP_FROM"))
+	os.Setenv("PAIR_SMTP_FROM", "")
+
+	os.Setenv("PAIR_SMTP_URL", "")
+	if _, err := SMTPFromEnv(); err == nil {
+		t.Fatalf("expected an error without $PAIR_SMTP_URL")
+	}
+
+	os.Setenv("PAIR_SMTP_URL", "smtp://pair@example.com:secret@smtp.example.com")
+	s, err := SMTPFromEnv()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if s.Addr != "smtp.example.com:25" || s.From != "pair@example.com" || s.Auth == nil {
+		t.Fatalf("expected the server, sender and credentials from the URL, got %+v", s)
+	}
+}
+
+func TestEmail(t *testing.T) {
+	var sentTo []string
+	var sent string
+	s := &SMTP{Addr: "localhost:25", From: "pair@example.com", send: func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentTo, sent = to, string(msg)
+		return nil
+	}}
+	if err := s.Email([]string{"team@example.com"}, "Pairing digest", "3 sessions\n"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sentTo) != 1 || !strings.Contains(sent, "Subject: Pairing digest\r\n") || !strings.HasSuffix(sent, "\r\n\r\n3 sessions\r\n") {
+		t.Fatalf("expected a plain text email to the team, got %v %q", sentTo, sent)
+	}
+}
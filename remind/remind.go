@@ -0,0 +1,194 @@
+// Package remind schedules and delivers periodic reminders to rotate drivers
+// or re-confirm the pair. Reminders are run by the system's own scheduler: a
+// launchd agent on macOS and a systemd user timer elsewhere.
+package remind
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/shell"
+)
+
+// Label names the launchd agent and the systemd units.
+const Label = "com.github.keeferrourke.pair.remind"
+
+// unitName is the name of the systemd service and timer.
+const unitName = "pair-remind"
+
+// Unit is a file the scheduler needs to run reminders.
+type Unit struct {
+	Path    string
+	Content string
+}
+
+// Units returns the files which run `exe remind` every interval on goos.
+func Units(goos string, every time.Duration, exe string) []Unit {
+	if goos == "darwin" {
+		return []Unit{{
+			Path: filepath.Join(os.Getenv("HOME"), "Library", "LaunchAgents", Label+".plist"),
+			Content: fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>remind</string>
+	</array>
+	<key>StartInterval</key>
+	<integer>%d</integer>
+</dict>
+</plist>
+`, Label, xmlEscape(exe), int(every.Seconds())),
+		}}
+	}
+	dir := systemdDir()
+	return []Unit{
+		{
+			Path: filepath.Join(dir, unitName+".service"),
+			Content: fmt.Sprintf(`[Unit]
+Description=Remind the pair to rotate drivers
+
+[Service]
+Type=oneshot
+ExecStart=%s remind
+`, systemdQuote(exe)),
+		},
+		{
+			Path: filepath.Join(dir, unitName+".timer"),
+			Content: fmt.Sprintf(`[Unit]
+Description=Remind the pair to rotate drivers every %s
+
+[Timer]
+OnActiveSec=%s
+OnUnitActiveSec=%s
+
+[Install]
+WantedBy=timers.target
+`, every, systemdSpan(every), systemdSpan(every)),
+		},
+	}
+}
+
+// Install writes the units for goos and asks the scheduler to start running
+// them.
+func Install(goos string, every time.Duration, exe string) ([]Unit, error) {
+	if every < time.Minute {
+		return nil, fmt.Errorf("reminders must be at least a minute apart, got %s", every)
+	}
+	units := Units(goos, every, exe)
+	for _, u := range units {
+		if err := os.MkdirAll(filepath.Dir(u.Path), 0755); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(u.Path, []byte(u.Content), 0644); err != nil {
+			return nil, err
+		}
+	}
+	if goos == "darwin" {
+		run("launchctl", "unload", units[0].Path)
+		return units, run("launchctl", "load", units[0].Path)
+	}
+	if err := run("systemctl", "--user", "daemon-reload"); err != nil {
+		return units, err
+	}
+	return units, run("systemctl", "--user", "enable", "--now", unitName+".timer")
+}
+
+// Uninstall stops reminders on goos and removes their units.
+func Uninstall(goos string) ([]Unit, error) {
+	units := Units(goos, time.Hour, "")
+	if goos == "darwin" {
+		run("launchctl", "unload", units[0].Path)
+	} else {
+		run("systemctl", "--user", "disable", "--now", unitName+".timer")
+	}
+	var removed []Unit
+	for _, u := range units {
+		if err := os.Remove(u.Path); err == nil {
+			removed = append(removed, u)
+		} else if !os.IsNotExist(err) {
+			return removed, err
+		}
+	}
+	if goos != "darwin" {
+		run("systemctl", "--user", "daemon-reload")
+	}
+	return removed, nil
+}
+
+// Notify shows message as a desktop notification on goos: with osascript on
+// macOS, a toast through PowerShell on Windows, and notify-send elsewhere.
+func Notify(goos, message string) error {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title \"pair\"", appleScriptQuote(message))
+		return run("osascript", "-e", script)
+	case "windows":
+		return run("powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript(message))
+	}
+	return run("notify-send", "pair", message)
+}
+
+// toastScript returns a PowerShell script showing message as a Windows toast
+// notification.
+func toastScript(message string) string {
+	return `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$toast = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $toast.GetElementsByTagName('text')
+$text.Item(0).AppendChild($toast.CreateTextNode('pair')) > $null
+$text.Item(1).AppendChild($toast.CreateTextNode(` + shell.QuotePowerShell(message) + `)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('pair').Show([Windows.UI.Notifications.ToastNotification]::new($toast))
+`
+}
+
+// systemdDir is where systemd looks for user units.
+func systemdDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", "systemd", "user")
+}
+
+// systemdSpan formats d as a systemd time span, e.g. 2h30m -> "9000s".
+func systemdSpan(d time.Duration) string {
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+// systemdQuote quotes an executable path for ExecStart, doubling the % of
+// specifiers and the $ of variables so systemd doesn't expand them.
+func systemdQuote(s string) string {
+	s = strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+	if !strings.ContainsAny(s, " \t\"\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// xmlEscape escapes s for a plist string.
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// appleScriptQuote quotes s as an AppleScript string literal.
+func appleScriptQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// run runs a scheduler or notification command, including its output in
+// any error.
+func run(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package remind
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnits(t *testing.T) {
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	os.Setenv("HOME", "/home/mb")
+	os.Setenv("XDG_CONFIG_HOME", "")
+
+	units := Units("linux", 2*time.Hour, "/usr/local/bin/pair")
+	if len(units) != 2 || units[1].Path != "/home/mb/.config/systemd/user/pair-remind.timer" {
+		t.Fatalf("expected a systemd service and timer, got %v", units)
+	}
+	if !strings.Contains(units[0].Content, "ExecStart=/usr/local/bin/pair remind\n") {
+		t.Fatalf("expected the service to run pair remind, got %s", units[0].Content)
+	}
+	if !strings.Contains(units[1].Content, "OnUnitActiveSec=7200s\n") {
+		t.Fatalf("expected the timer to fire every 2h, got %s", units[1].Content)
+	}
+
+	units = Units("darwin", 30*time.Minute, "/Applications/My Tools/pair")
+	if len(units) != 1 || units[0].Path != "/home/mb/Library/LaunchAgents/"+Label+".plist" {
+		t.Fatalf("expected a launchd agent, got %v", units)
+	}
+	if !strings.Contains(units[0].Content, "<string>/Applications/My Tools/pair</string>") ||
+		!strings.Contains(units[0].Content, "<integer>1800</integer>") {
+		t.Fatalf("expected the agent to run pair every 1800s, got %s", units[0].Content)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := map[string]string{
+		"/usr/local/bin/pair":      "/usr/local/bin/pair",
+		"/opt/My Tools/pair":       `"/opt/My Tools/pair"`,
+		"/opt/100%/pair":           "/opt/100%%/pair",
+		"/opt/$HOME/pair":          "/opt/$$HOME/pair",
+		`/opt/"quoted" $dir/%pair`: `"/opt/\"quoted\" $$dir/%%pair"`,
+	}
+	for path, expected := range tests {
+		if got := systemdQuote(path); got != expected {
+			t.Errorf("expected %s to be quoted as %s, got %s", path, expected, got)
+		}
+	}
+}
+
+func TestInstallTooOften(t *testing.T) {
+	if _, err := Install("linux", time.Second, "pair"); err == nil {
+		t.Fatalf("expected an error for reminders every second")
+	}
+}
+
+func TestToastScript(t *testing.T) {
+	script := toastScript("Time's up, Lindsay!")
+	if !strings.Contains(script, "CreateTextNode('Time''s up, Lindsay!')") {
+		t.Fatalf("expected the message quoted for PowerShell, got %s", script)
+	}
+}
@@ -0,0 +1,195 @@
+// Package secret keeps credentials such as API tokens out of pair's config:
+// in the macOS keychain, in the Secret Service via secret-tool elsewhere, or
+// failing both in a file only its owner can read.
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/internal/lockedfile"
+	"gopkg.in/yaml.v3"
+)
+
+// service names pair's entries in the keychain and Secret Service.
+const service = "pair"
+
+// ErrNotFound is returned by Get for a secret which hasn't been stored.
+var ErrNotFound = errors.New("secret not found")
+
+// Store is somewhere secrets are kept, by name.
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+}
+
+// Default returns the best store available: the keychain on macOS, the
+// Secret Service if secret-tool is installed, or else File. Set
+// $PAIR_SECRET_BACKEND to keychain, secret-tool or file to choose one.
+func Default() Store {
+	backend := os.Getenv("PAIR_SECRET_BACKEND")
+	if backend == "" {
+		switch _, err := exec.LookPath("secret-tool"); {
+		case runtime.GOOS == "darwin":
+			backend = "keychain"
+		case err == nil:
+			backend = "secret-tool"
+		}
+	}
+	switch backend {
+	case "keychain":
+		return keychain{}
+	case "secret-tool":
+		return secretTool{}
+	}
+	return File(filepath.Join(cfg.ConfigDir(), "secrets.yml"))
+}
+
+// keychain keeps secrets in the macOS login keychain.
+type keychain struct{}
+
+func (keychain) Get(name string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", name, "-w").Output()
+	if exitCode(err) == 44 {
+		return "", ErrNotFound
+	}
+	return strings.TrimRight(string(out), "\n"), err
+}
+
+// Set hands the command to security on its stdin rather than as arguments,
+// so the secret never shows up in ps.
+func (keychain) Set(name, value string) error {
+	var args []string
+	for _, arg := range []string{"add-generic-password", "-U", "-s", service, "-a", name, "-w", value} {
+		args = append(args, securityQuote(arg))
+	}
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(strings.Join(args, " ") + "\n")
+	out, err := cmd.CombinedOutput()
+	if err == nil && len(bytes.TrimSpace(out)) > 0 {
+		// security -i reports a failed command but still exits 0.
+		err = errors.New(strings.TrimSpace(string(out)))
+	}
+	return err
+}
+
+// securityQuote quotes an argument for a command read by security -i.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (keychain) Delete(name string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", service, "-a", name).Run()
+	if exitCode(err) == 44 {
+		return ErrNotFound
+	}
+	return err
+}
+
+// secretTool keeps secrets in the Secret Service, such as GNOME Keyring or
+// KWallet, through libsecret's secret-tool.
+type secretTool struct{}
+
+func (secretTool) Get(name string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", name).Output()
+	if exitCode(err) == 1 {
+		return "", ErrNotFound
+	}
+	return strings.TrimRight(string(out), "\n"), err
+}
+
+func (secretTool) Set(name, value string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service+" "+name, "service", service, "account", name)
+	cmd.Stdin = strings.NewReader(value)
+	return cmd.Run()
+}
+
+func (secretTool) Delete(name string) error {
+	return exec.Command("secret-tool", "clear", "service", service, "account", name).Run()
+}
+
+// exitCode returns the exit status of a command which failed with err, or -1.
+func exitCode(err error) int {
+	if exit, ok := err.(*exec.ExitError); ok {
+		return exit.ExitCode()
+	}
+	return -1
+}
+
+// File keeps secrets in a YAML file at its path, readable only by its owner.
+type File string
+
+func (f File) read() (map[string]string, error) {
+	secrets := make(map[string]string)
+	buf, err := ioutil.ReadFile(string(f))
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secrets, yaml.Unmarshal(buf, &secrets)
+}
+
+// update replaces the file with change applied to the secrets in it, holding
+// the lock throughout so concurrent changes aren't lost.
+func (f File) update(change func(secrets map[string]string) error) error {
+	path := string(f)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return lockedfile.Update(path, 0600, func(buf []byte) ([]byte, error) {
+		// The rewrite keeps an existing file's mode, so tighten one left
+		// readable by others first, along with its backup.
+		for _, p := range []string{path, lockedfile.Backup(path)} {
+			if err := os.Chmod(p, 0600); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+		secrets := make(map[string]string)
+		if err := yaml.Unmarshal(buf, &secrets); err != nil {
+			return nil, err
+		}
+		if err := change(secrets); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(secrets)
+	})
+}
+
+func (f File) Get(name string) (string, error) {
+	secrets, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	value, ok := secrets[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (f File) Set(name, value string) error {
+	return f.update(func(secrets map[string]string) error {
+		secrets[name] = value
+		return nil
+	})
+}
+
+func (f File) Delete(name string) error {
+	return f.update(func(secrets map[string]string) error {
+		if _, ok := secrets[name]; !ok {
+			return ErrNotFound
+		}
+		delete(secrets, name)
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "secret")
+	defer os.RemoveAll(dir) // clean up
+	f := File(filepath.Join(dir, "pair", "secrets.yml"))
+
+	if _, err := f.Get("github"); err != ErrNotFound {
+		t.Fatalf("expected nothing stored yet, got %v", err)
+	}
+	if err := f.Set("github", "ghp_banana"); err != nil {
+		t.Fatalf("expected no error storing a secret, got %v", err)
+	}
+	f.Set("slack", "https://hooks.slack.com/services/T0/B0/x")
+	if value, err := f.Get("github"); err != nil || value != "ghp_banana" {
+		t.Fatalf("expected the stored secret, got %q, %v", value, err)
+	}
+	if info, _ := os.Stat(string(f)); info.Mode().Perm() != 0600 {
+		t.Fatalf("expected the file to be private, got %v", info.Mode())
+	}
+
+	if err := f.Delete("github"); err != nil {
+		t.Fatalf("expected no error removing a secret, got %v", err)
+	}
+	if _, err := f.Get("github"); err != ErrNotFound {
+		t.Fatalf("expected the secret to be gone, got %v", err)
+	}
+	if value, _ := f.Get("slack"); value == "" {
+		t.Fatalf("expected other secrets to be kept")
+	}
+	if err := f.Delete("github"); err != ErrNotFound {
+		t.Fatalf("expected removing a missing secret to say so, got %v", err)
+	}
+}
+
+func TestFileTightensPermissions(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "secret")
+	defer os.RemoveAll(dir) // clean up
+	f := File(filepath.Join(dir, "secrets.yml"))
+	ioutil.WriteFile(string(f), []byte("github: ghp_banana\n"), 0644)
+
+	if err := f.Set("slack", "https://hooks.slack.com/services/T0/B0/x"); err != nil {
+		t.Fatalf("expected no error storing a secret, got %v", err)
+	}
+	for _, path := range []string{string(f), string(f) + ".bak"} {
+		if info, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s to exist, got %v", path, err)
+		} else if info.Mode().Perm() != 0600 {
+			t.Fatalf("expected %s to be made private, got %v", path, info.Mode())
+		}
+	}
+	if value, _ := f.Get("github"); value != "ghp_banana" {
+		t.Fatalf("expected existing secrets to be kept, got %q", value)
+	}
+}
+
+func TestSecurityQuote(t *testing.T) {
+	for arg, expected := range map[string]string{
+		"ghp_banana": `"ghp_banana"`,
+		"two words":  `"two words"`,
+		`say "hi"`:   `"say \"hi\""`,
+		`back\slash`: `"back\\slash"`,
+	} {
+		if quoted := securityQuote(arg); quoted != expected {
+			t.Errorf("expected %q to be quoted as %s, got %s", arg, expected, quoted)
+		}
+	}
+}
+
+func TestDefault(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "secret")
+	defer os.RemoveAll(dir) // clean up
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	os.Setenv("PAIR_SECRET_BACKEND", "file")
+	defer os.Unsetenv("PAIR_SECRET_BACKEND")
+
+	if f, ok := Default().(File); !ok || string(f) != filepath.Join(dir, "pair", "secrets.yml") {
+		t.Fatalf("expected the file store in the config directory, got %v", Default())
+	}
+}
@@ -0,0 +1,346 @@
+// Package service answers questions about the current pair over a unix
+// socket, for prompts and editors which render too often to run pair each
+// time. Each query is a line naming what to ask, and each answer is a line.
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+	"github.com/keeferrourke/pair/vcs"
+)
+
+// Queries the server answers.
+const (
+	QueryPair     = "pair"     // Aliases of the current pair, e.g. lb+mb, or empty
+	QueryIdentity = "identity" // The git identity, e.g. Lindsay Bluth and Michael Bluth <git+lb+mb@example.com>, or in the repository at the directory after it
+	QueryAge      = "age"      // How long ago the session started, e.g. 1h05m
+	QueryPolicy   = "policy"   // ok, or stale or blocked once past session_ttl
+	QuerySession  = "session"  // The whole session, as JSON
+	QuerySummary  = "summary"  // The session's session.Summary, as JSON
+)
+
+// SocketPath returns where the server listens: pair.sock in
+// $XDG_RUNTIME_DIR, or else in pair's cache directory.
+func SocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "pair.sock")
+	}
+	return filepath.Join(cfg.CacheDir(), "pair.sock")
+}
+
+// Server answers queries about the session stored at SessionPath, reloading
+// it only when the file changes so answers are quick. The same goes for the
+// config at ConfigPath, if set, and each repository's git identity.
+type Server struct {
+	SessionPath string        // Where the session is stored
+	ConfigPath  string        // Where the config is stored, to reload TTL and OnStale from when it changes
+	TTL         time.Duration // session_ttl, or zero
+	OnStale     string        // What happens to stale sessions: warn or block
+
+	now        func() time.Time
+	readConfig func() (*cfg.Config, error) // cfg.ReadLocal, replaceable in tests
+	mu         sync.Mutex
+	session    *session.Session
+	stat       os.FileInfo // The session file when it was last loaded
+	configStat os.FileInfo // The config file when it was last loaded
+	identities map[string]*identity
+}
+
+// identity is the git identity in a repository, kept until any of the files
+// it came from change.
+type identity struct {
+	value string
+	files []string
+	stats []os.FileInfo
+}
+
+// NewServer creates a Server for the default session and config.
+func NewServer(config *cfg.Config) (*Server, error) {
+	ttl, err := config.TTL()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		SessionPath: session.DefaultPath(),
+		ConfigPath:  config.Path,
+		TTL:         ttl,
+		OnStale:     config.OnStale(),
+		now:         time.Now,
+		readConfig:  cfg.ReadLocal,
+	}
+	s.configStat, _ = os.Stat(config.Path)
+	return s, nil
+}
+
+// Listen listens on the socket at path, only for the current user. A socket
+// left behind by a server which has exited is replaced.
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("already serving on %s", path)
+	}
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Serve answers connections on l until it's closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		if _, err := fmt.Fprintln(conn, s.Answer(strings.TrimSpace(scanner.Text()))); err != nil {
+			return
+		}
+	}
+}
+
+// Answer answers a single query.
+func (s *Server) Answer(query string) string {
+	s.reloadConfig()
+	sess, err := s.current()
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	now := s.now()
+	arg := ""
+	if i := strings.IndexByte(query, ' '); i >= 0 {
+		query, arg = query[:i], strings.TrimSpace(query[i+1:])
+	}
+	switch query {
+	case QueryPair:
+		return sess.Aliases()
+	case QueryIdentity:
+		if arg != "" {
+			if root := repoRoot(arg); root != "" {
+				return s.repoIdentity(root)
+			}
+		}
+		if sess.ID == "" {
+			return ""
+		}
+		return fmt.Sprintf("%s <%s>", sess.Name, sess.Email)
+	case QuerySession:
+		return toJSON(sess)
+	case QuerySummary:
+		s.mu.Lock()
+		ttl := s.TTL
+		s.mu.Unlock()
+		return toJSON(sess.Summarize(ttl, now))
+	case QueryAge:
+		if sess.ID == "" {
+			return ""
+		}
+		return session.FormatAge(now.Sub(sess.Started))
+	case QueryPolicy:
+		s.mu.Lock()
+		ttl, onStale := s.TTL, s.OnStale
+		s.mu.Unlock()
+		switch {
+		case !sess.Stale(ttl, now):
+			return "ok"
+		case onStale == cfg.Block:
+			return "blocked"
+		}
+		return "stale"
+	}
+	return "error: unknown query " + query
+}
+
+// toJSON encodes v on a single line, or describes why it can't.
+func toJSON(v interface{}) string {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(buf)
+}
+
+// reloadConfig rereads TTL and OnStale if the config at ConfigPath has
+// changed. A config which can't be read leaves them as they were.
+func (s *Server) reloadConfig() {
+	if s.ConfigPath == "" || s.readConfig == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat, _ := os.Stat(s.ConfigPath)
+	if sameFile(stat, s.configStat) {
+		return
+	}
+	s.configStat = stat
+	config, err := s.readConfig()
+	if err != nil {
+		return
+	}
+	if ttl, err := config.TTL(); err == nil {
+		s.TTL = ttl
+	}
+	s.OnStale = config.OnStale()
+}
+
+// repoIdentity returns the git identity in effect in the repository at root,
+// running git only when it or the files it's configured by have changed.
+func (s *Server) repoIdentity(root string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id := s.identities[root]; id != nil && id.fresh() {
+		return id.value
+	}
+	id := &identity{files: []string{
+		s.SessionPath,
+		vcs.IdentityFile(),
+		filepath.Join(os.Getenv("HOME"), ".gitconfig"),
+		filepath.Join(root, ".git", "config"),
+	}}
+	for _, file := range id.files {
+		stat, _ := os.Stat(file)
+		id.stats = append(id.stats, stat)
+	}
+	name, err := vcs.Git("-C", root, "config", "user.name")
+	if err != nil {
+		return ""
+	}
+	email, _ := vcs.Git("-C", root, "config", "user.email")
+	id.value = fmt.Sprintf("%s <%s>", name, email)
+	if s.identities == nil {
+		s.identities = make(map[string]*identity)
+	}
+	s.identities[root] = id
+	return id.value
+}
+
+// fresh reports whether none of the files the identity came from have
+// changed.
+func (id *identity) fresh() bool {
+	for i, file := range id.files {
+		stat, _ := os.Stat(file)
+		if !sameFile(stat, id.stats[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// repoRoot returns the root of the working tree holding dir, found by
+// looking for .git rather than running git, or "" if there isn't one.
+func repoRoot(dir string) string {
+	dir = filepath.Clean(dir)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// current returns the session, reloading it if the file has changed.
+func (s *Server) current() (*session.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stat, _ := os.Stat(s.SessionPath)
+	if s.session != nil && sameFile(stat, s.stat) {
+		return s.session, nil
+	}
+	sess, err := session.Load(s.SessionPath)
+	if err != nil {
+		return nil, err
+	}
+	s.session, s.stat = sess, stat
+	return sess, nil
+}
+
+// sameFile reports whether a and b, either of which may be nil for a missing
+// file, describe the same version of a file.
+func sameFile(a, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return os.SameFile(a, b) && a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+// Query asks the server listening at path a single query.
+func Query(path, query string) (string, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+	if _, err := fmt.Fprintln(conn, query); err != nil {
+		return "", err
+	}
+	answer, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(answer, "\n"), nil
+}
+
+// CurrentSession asks the server listening at path for the current session.
+func CurrentSession(path string) (*session.Session, error) {
+	sess := &session.Session{}
+	if err := queryJSON(path, QuerySession, sess); err != nil {
+		return nil, err
+	}
+	sess.Path = session.DefaultPath()
+	return sess, nil
+}
+
+// CurrentSummary asks the server listening at path for the current session's
+// summary.
+func CurrentSummary(path string) (*session.Summary, error) {
+	sum := &session.Summary{}
+	if err := queryJSON(path, QuerySummary, sum); err != nil {
+		return nil, err
+	}
+	return sum, nil
+}
+
+// queryJSON asks the server listening at path query, decoding the answer
+// into out.
+func queryJSON(path, query string, out interface{}) error {
+	answer, err := Query(path, query)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(answer, "error: ") {
+		return errors.New(strings.TrimPrefix(answer, "error: "))
+	}
+	return json.Unmarshal([]byte(answer), out)
+}
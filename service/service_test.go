@@ -0,0 +1,115 @@
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+)
+
+func TestServe(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "service")
+	defer os.RemoveAll(dir) // clean up
+
+	started := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s := &session.Session{
+		ID:      "abc",
+		Name:    "Lindsay Bluth and Michael Bluth",
+		Email:   "git+lb+mb@example.com",
+		Authors: []*cfg.Author{{Alias: "lb"}, {Alias: "mb"}},
+		Started: started,
+		Path:    filepath.Join(dir, "session.yml"),
+	}
+	s.Save()
+
+	server := &Server{SessionPath: s.Path, TTL: 8 * time.Hour, OnStale: cfg.Block}
+	server.now = func() time.Time { return started.Add(65 * time.Minute) }
+	socket := filepath.Join(dir, "pair.sock")
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("expected no error listening, got %v", err)
+	}
+	defer l.Close()
+	go server.Serve(l)
+
+	if _, err := Listen(socket); err == nil {
+		t.Fatalf("expected an error listening while another server is running")
+	}
+
+	for query, expected := range map[string]string{
+		QueryPair:     "lb+mb",
+		QueryIdentity: "Lindsay Bluth and Michael Bluth <git+lb+mb@example.com>",
+		QueryAge:      "1h05m",
+		QueryPolicy:   "ok",
+		"weather":     "error: unknown query weather",
+	} {
+		if answer, err := Query(socket, query); err != nil || answer != expected {
+			t.Fatalf("expected %q for %s, got %q, %v", expected, query, answer, err)
+		}
+	}
+
+	if sess, err := CurrentSession(socket); err != nil || sess.ID != "abc" || len(sess.Authors) != 2 {
+		t.Fatalf("expected the session, got %v, %v", sess, err)
+	}
+	if sum, err := CurrentSummary(socket); err != nil || sum.String() != "lb+mb 1h05m ok" {
+		t.Fatalf("expected the session's summary, got %v, %v", sum, err)
+	}
+	if answer, _ := Query(socket, QueryIdentity+" "+dir); answer != "Lindsay Bluth and Michael Bluth <git+lb+mb@example.com>" {
+		t.Fatalf("expected the session's identity outside a repository, got %q", answer)
+	}
+
+	server.now = func() time.Time { return started.Add(9 * time.Hour) }
+	if answer, _ := Query(socket, QueryPolicy); answer != "blocked" {
+		t.Fatalf("expected a stale session to be blocked, got %q", answer)
+	}
+
+	s.Authors = s.Authors[:1]
+	s.Save()
+	if answer, _ := Query(socket, QueryPair); answer != "lb" {
+		t.Fatalf("expected the session to be reloaded when it changes, got %q", answer)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "service")
+	defer os.RemoveAll(dir) // clean up
+
+	path := filepath.Join(dir, "config.yml")
+	ioutil.WriteFile(path, []byte("session_ttl: 8h\n"), 0644)
+	reads := 0
+	server := &Server{ConfigPath: path, TTL: 8 * time.Hour, OnStale: cfg.Warn}
+	server.readConfig = func() (*cfg.Config, error) {
+		reads++
+		return cfg.NewFromFile(path)
+	}
+	server.configStat, _ = os.Stat(path)
+
+	server.reloadConfig()
+	if reads != 0 {
+		t.Fatalf("expected an unchanged config not to be read again")
+	}
+	ioutil.WriteFile(path, []byte("session_ttl: 2h\npolicy:\n  stale: block\n"), 0644)
+	server.reloadConfig()
+	if reads != 1 || server.TTL != 2*time.Hour || server.OnStale != cfg.Block {
+		t.Fatalf("expected the changed config to be reloaded, got %d reads, %v, %v", reads, server.TTL, server.OnStale)
+	}
+}
+
+func TestRepoRoot(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "service")
+	defer os.RemoveAll(dir) // clean up
+
+	nested := filepath.Join(dir, "repo", "cmd", "pair")
+	os.MkdirAll(nested, 0755)
+	os.Mkdir(filepath.Join(dir, "repo", ".git"), 0755)
+	if root := repoRoot(nested); root != filepath.Join(dir, "repo") {
+		t.Fatalf("expected the repository's root, got %q", root)
+	}
+	if root := repoRoot(dir); root != "" {
+		t.Fatalf("expected no root outside a repository, got %q", root)
+	}
+}
@@ -0,0 +1,355 @@
+// Package session records who is currently pairing, so that features which
+// credit co-authors don't need to reverse-engineer the git identity.
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/vcs"
+	"gopkg.in/yaml.v2"
+)
+
+// Session describes the active pair. Serializes to YAML, or to JSON in the
+// session history.
+type Session struct {
+	ID             string        `yaml:"id" json:"id"`                                               // Random identifier for this session
+	Name           string        `yaml:"name" json:"name"`                                           // Composed author name. e.g. Lindsay Bluth and Michael Bluth
+	Email          string        `yaml:"email" json:"email"`                                         // Composed author email. e.g. git+lb+mb@example.com
+	Committer      string        `yaml:"committer,omitempty" json:"committer,omitempty"`             // Committer name, if not the pair. See cfg.AttributeAuthorOnly
+	CommitterEmail string        `yaml:"committer_email,omitempty" json:"committer_email,omitempty"` // Committer email, if not the pair
+	Authors        []*cfg.Author `yaml:"authors" json:"authors"`                                     // Everyone in the pair
+	Started        time.Time     `yaml:"started" json:"started"`                                     // When the pair was set
+	Expires        time.Time     `yaml:"expires,omitempty" json:"expires,omitempty"`                 // When the pair expires, if set with pair with --for
+	Ended          time.Time     `yaml:"ended,omitempty" json:"ended"`                               // When the pair changed again
+	Commits        []string      `yaml:"commits,omitempty" json:"commits,omitempty"`                 // Commits made during the session
+	Repo           string        `yaml:"repo,omitempty" json:"repo,omitempty"`                       // Repository the pair was set in, if any
+	Branch         string        `yaml:"branch,omitempty" json:"branch,omitempty"`                   // Branch checked out when the pair was set, if any
+	LastCommit     time.Time     `yaml:"last_commit,omitempty" json:"last_commit,omitempty"`         // When the last commit was logged
+	Reason         string        `yaml:"reason,omitempty" json:"reason,omitempty"`                   // Why pair started the session itself, if it did
+	Mob            bool          `yaml:"mob,omitempty" json:"mob,omitempty"`                         // Whether the authors take turns driving, first author first
+	Path           string        `yaml:"-" json:"-"`                                                 // Where this session is stored
+}
+
+// DefaultPath returns the location of the session file.
+func DefaultPath() string {
+	return filepath.Join(cfg.DataDir(), "session.yml")
+}
+
+// New starts a session for the composed identity and the authors in it, to be
+// saved at the default location.
+func New(name, email string, authors []*cfg.Author) *Session {
+	return &Session{
+		ID:      newID(),
+		Name:    name,
+		Email:   email,
+		Authors: authors,
+		Started: time.Now(),
+		Path:    DefaultPath(),
+	}
+}
+
+// Start replaces the current session with a new one, archiving the old
+// session to the history first and updating the prompt state file.
+func Start(name, email string, authors []*cfg.Author) (*Session, error) {
+	old, err := Current()
+	if err != nil {
+		return nil, err
+	}
+	if old.ID != "" {
+		old.Ended = time.Now()
+		if err := Archive(HistoryPath(), old); err != nil {
+			return nil, err
+		}
+	}
+	s := New(name, email, authors)
+	s.Repo = vcs.TopLevel()
+	s.Branch = vcs.CurrentBranch()
+	if err := s.Save(); err != nil {
+		return nil, err
+	}
+	if err := WriteState(StatePath(), s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().Format("20060102150405")
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Load reads the session stored at path. A missing file is not an error; it
+// yields an empty session which will be saved to path.
+func Load(path string) (*Session, error) {
+	s := Session{Path: path}
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(buf, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Current loads the session from the default location.
+func Current() (*Session, error) {
+	return Load(DefaultPath())
+}
+
+// Save saves the session to disk, creating its directory if necessary.
+func (s *Session) Save() error {
+	buf, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path, buf, 0644)
+}
+
+// Environment returns the git environment variables that attribute commits
+// to the session's composed identity, and to its committer if it has one, as
+// KEY=value pairs.
+func (s *Session) Environment() []string {
+	committer, committerEmail := s.Name, s.Email
+	if s.Committer != "" {
+		committer, committerEmail = s.Committer, s.CommitterEmail
+	}
+	return []string{
+		"GIT_AUTHOR_NAME=" + s.Name,
+		"GIT_AUTHOR_EMAIL=" + s.Email,
+		"GIT_COMMITTER_NAME=" + committer,
+		"GIT_COMMITTER_EMAIL=" + committerEmail,
+	}
+}
+
+// Stale reports whether the session has outlived ttl, or its own expiry, as
+// of now. See Deadline.
+func (s *Session) Stale(ttl time.Duration, now time.Time) bool {
+	deadline := s.Deadline(ttl)
+	return s.ID != "" && !deadline.IsZero() && now.After(deadline)
+}
+
+// Deadline returns when the session goes stale: when it expires if it was
+// given an expiry, otherwise ttl after it started. It's zero when there's
+// neither, so the session never goes stale.
+func (s *Session) Deadline(ttl time.Duration) time.Time {
+	if !s.Expires.IsZero() {
+		return s.Expires
+	}
+	if ttl > 0 {
+		return s.Started.Add(ttl)
+	}
+	return time.Time{}
+}
+
+// Duration returns how long the session lasted, or has lasted as of now if
+// it's still going.
+func (s *Session) Duration(now time.Time) time.Duration {
+	end := s.Ended
+	if end.IsZero() {
+		end = now
+	}
+	if d := end.Sub(s.Started); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Idle returns how long it's been since the session started or last logged a
+// commit, whichever is later.
+func (s *Session) Idle(now time.Time) time.Duration {
+	last := s.Started
+	if s.LastCommit.After(last) {
+		last = s.LastCommit
+	}
+	return now.Sub(last)
+}
+
+// CoAuthors returns the members of the pair who aren't already credited by
+// the composed author email. In a mob, that's everyone but the driver.
+func (s *Session) CoAuthors() []*cfg.Author {
+	if s.Mob && len(s.Authors) > 0 {
+		return s.Authors[1:]
+	}
+	var coauthors []*cfg.Author
+	for _, a := range s.Authors {
+		if a.Email != s.Email {
+			coauthors = append(coauthors, a)
+		}
+	}
+	return coauthors
+}
+
+// Rotate returns the authors of a mob once the driver hands over to the next
+// in turn: everyone moves up one, and the driver goes last.
+func (s *Session) Rotate() []*cfg.Author {
+	if len(s.Authors) == 0 {
+		return nil
+	}
+	return append(append([]*cfg.Author{}, s.Authors[1:]...), s.Authors[0])
+}
+
+// Note is the structured pairing metadata attached to a commit with git notes.
+type Note struct {
+	Session    string   `json:"session"`
+	Driver     string   `json:"driver,omitempty"`
+	Navigators []string `json:"navigators,omitempty"`
+	Started    string   `json:"started"`
+	Duration   string   `json:"duration"`
+}
+
+// Note describes the session as of now, for attaching to a commit. The first
+// author is considered the driver and everyone else a navigator.
+func (s *Session) Note(now time.Time) ([]byte, error) {
+	n := Note{
+		Session:  s.ID,
+		Started:  s.Started.Format(time.RFC3339),
+		Duration: now.Sub(s.Started).Round(time.Second).String(),
+	}
+	for i, a := range s.Authors {
+		if i == 0 {
+			n.Driver = a.Alias
+		} else {
+			n.Navigators = append(n.Navigators, a.Alias)
+		}
+	}
+	return json.Marshal(n)
+}
+
+// HistoryPath returns the location of the session history, a file of JSON
+// encoded sessions, one per line.
+func HistoryPath() string {
+	return filepath.Join(cfg.DataDir(), "history.jsonl")
+}
+
+// Archive appends s to the session history at path.
+func Archive(path string, s *Session) error {
+	buf, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(buf, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// History reads every session archived at path, oldest first. A missing
+// history is empty.
+func History(path string) ([]*Session, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sessions []*Session
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var s Session
+		if err := dec.Decode(&s); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, nil
+}
+
+// LastPaired returns when each alias last started a session among sessions.
+func LastPaired(sessions []*Session) map[string]time.Time {
+	last := make(map[string]time.Time)
+	for _, s := range sessions {
+		for _, a := range s.Authors {
+			if s.Started.After(last[a.Alias]) {
+				last[a.Alias] = s.Started
+			}
+		}
+	}
+	return last
+}
+
+// Includes reports whether the author with alias is part of the session.
+func (s *Session) Includes(alias string) bool {
+	for _, a := range s.Authors {
+		if a.Alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// LastIn returns the most recently started of sessions set in the repository
+// at repo, or nil if there isn't one.
+func LastIn(sessions []*Session, repo string) *Session {
+	var last *Session
+	for _, s := range sessions {
+		if s.Repo == repo && len(s.Authors) > 0 && (last == nil || s.Started.After(last.Started)) {
+			last = s
+		}
+	}
+	return last
+}
+
+// Filter selects sessions from the history. Its zero value selects every
+// session.
+type Filter struct {
+	Since time.Time // Started on or after, unless zero
+	With  []string  // Aliases which must all be in the session
+	Repo  string    // Repository the pair was set in, unless empty
+}
+
+// Match reports whether s is selected by the filter.
+func (f Filter) Match(s *Session) bool {
+	if s.Started.Before(f.Since) || f.Repo != "" && s.Repo != f.Repo {
+		return false
+	}
+	for _, alias := range f.With {
+		if !s.Includes(alias) {
+			return false
+		}
+	}
+	return true
+}
+
+// All returns every archived session followed by the current one, if any.
+func All() ([]*Session, error) {
+	sessions, err := History(HistoryPath())
+	if err != nil {
+		return nil, err
+	}
+	current, err := Current()
+	if err != nil {
+		return nil, err
+	}
+	if current.ID != "" {
+		sessions = append(sessions, current)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,316 @@
+package session
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+func TestLoadMissing(t *testing.T) {
+	s, err := Load("/nonexistent/session.yml")
+	if err != nil {
+		t.Fatalf("expected no error loading a missing session, got %v", err)
+	}
+	if len(s.Authors) != 0 {
+		t.Fatalf("expected missing session to have no authors, got %v", s.Authors)
+	}
+	if s.Path != "/nonexistent/session.yml" {
+		t.Fatalf("expected Path to be set appropriately, was %v", s.Path)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pair-session")
+	if err != nil {
+		t.Fatalf("couldn't make tempdir during test set up: %v", err)
+	}
+	defer os.RemoveAll(dir) // clean up
+
+	s := &Session{
+		Name:    "Lindsay Bluth and Michael Bluth",
+		Email:   "git+lb+mb@example.com",
+		Authors: []*cfg.Author{{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"}},
+		Started: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+		Path:    filepath.Join(dir, "nested", "session.yml"),
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("error saving session: %v", err)
+	}
+
+	loaded, err := Load(s.Path)
+	if err != nil {
+		t.Fatalf("error loading session: %v", err)
+	}
+	if loaded.Name != s.Name || loaded.Email != s.Email || !loaded.Started.Equal(s.Started) {
+		t.Fatalf("loaded session differs from saved session, got %+v", loaded)
+	}
+	if len(loaded.Authors) != 1 || *loaded.Authors[0] != *s.Authors[0] {
+		t.Fatalf("loaded authors differ from saved authors, got %v", loaded.Authors)
+	}
+}
+
+func TestStale(t *testing.T) {
+	started := time.Date(2019, 1, 2, 9, 0, 0, 0, time.UTC)
+	s := &Session{ID: "abc", Started: started}
+	if s.Stale(0, started.Add(72*time.Hour)) {
+		t.Fatalf("expected a session without a TTL never to go stale")
+	}
+	if s.Stale(8*time.Hour, started.Add(7*time.Hour)) {
+		t.Fatalf("expected a 7h old session to be fresh with an 8h TTL")
+	}
+	if !s.Stale(8*time.Hour, started.Add(9*time.Hour)) {
+		t.Fatalf("expected a 9h old session to be stale with an 8h TTL")
+	}
+	s.Expires = started.Add(4 * time.Hour)
+	if !s.Stale(0, started.Add(5*time.Hour)) || !s.Stale(8*time.Hour, started.Add(5*time.Hour)) {
+		t.Fatalf("expected a session to go stale when it expires, whatever the TTL")
+	}
+	if s.Stale(2*time.Hour, started.Add(3*time.Hour)) {
+		t.Fatalf("expected a session's own expiry to take precedence over the TTL")
+	}
+}
+
+func TestCoAuthors(t *testing.T) {
+	s := &Session{
+		Email: "mb@example.com",
+		Authors: []*cfg.Author{
+			{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"},
+			{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		},
+	}
+	coauthors := s.CoAuthors()
+	if len(coauthors) != 1 || coauthors[0].Alias != "lb" {
+		t.Fatalf("expected the committer to be excluded from co-authors, got %v", coauthors)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	s := &Session{
+		Email: "gb@example.com",
+		Mob:   true,
+		Authors: []*cfg.Author{
+			{Name: "George Bluth", Alias: "gb", Email: "gb@example.com"},
+			{Name: "Lindsay Bluth", Alias: "lb"},
+			{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		},
+	}
+	if coauthors := s.CoAuthors(); len(coauthors) != 2 || coauthors[0].Alias != "lb" || coauthors[1].Alias != "mb" {
+		t.Fatalf("expected everyone but the driver to be a co-author, got %v", coauthors)
+	}
+	rotated := s.Rotate()
+	if len(rotated) != 3 || rotated[0].Alias != "lb" || rotated[1].Alias != "mb" || rotated[2].Alias != "gb" {
+		t.Fatalf("expected the driver to go last, got %v", rotated)
+	}
+	if s.Authors[0].Alias != "gb" {
+		t.Fatalf("expected rotating to leave the session alone, got %v", s.Authors)
+	}
+}
+
+func TestNew(t *testing.T) {
+	a, b := New("", "", nil), New("", "", nil)
+	if a.ID == "" || a.ID == b.ID {
+		t.Fatalf("expected sessions to get distinct IDs, got %q and %q", a.ID, b.ID)
+	}
+	if a.Path != DefaultPath() {
+		t.Fatalf("expected new session to be stored at the default path, was %v", a.Path)
+	}
+}
+
+func ExampleSession_Note() {
+	s := &Session{
+		ID:      "0123456789abcdef",
+		Started: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+		Authors: []*cfg.Author{{Alias: "mb"}, {Alias: "lb"}},
+	}
+	note, _ := s.Note(s.Started.Add(90 * time.Minute))
+	fmt.Println(string(note))
+
+	// Output:
+	// {"session":"0123456789abcdef","driver":"mb","navigators":["lb"],"started":"2019-01-02T03:04:05Z","duration":"1h30m0s"}
+}
+
+func TestArchiveAndHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pair-session")
+	if err != nil {
+		t.Fatalf("couldn't make tempdir during test set up: %v", err)
+	}
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, "history.jsonl")
+
+	sessions, err := History(path)
+	if err != nil || len(sessions) != 0 {
+		t.Fatalf("expected a missing history to be empty, got %v, %v", sessions, err)
+	}
+
+	for _, id := range []string{"first", "second"} {
+		if err := Archive(path, &Session{ID: id, Commits: []string{"abc123"}}); err != nil {
+			t.Fatalf("error archiving session: %v", err)
+		}
+	}
+	sessions, err = History(path)
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "first" || sessions[1].ID != "second" {
+		t.Fatalf("expected both sessions in order, got %v", sessions)
+	}
+	if len(sessions[1].Commits) != 1 {
+		t.Fatalf("expected commits to be archived, got %v", sessions[1].Commits)
+	}
+}
+
+func ExampleSession_Environment() {
+	s := &Session{Name: "Lindsay Bluth and Michael Bluth", Email: "git+lb+mb@example.com"}
+	for _, kv := range s.Environment() {
+		fmt.Println(kv)
+	}
+
+	// Output:
+	// GIT_AUTHOR_NAME=Lindsay Bluth and Michael Bluth
+	// GIT_AUTHOR_EMAIL=git+lb+mb@example.com
+	// GIT_COMMITTER_NAME=Lindsay Bluth and Michael Bluth
+	// GIT_COMMITTER_EMAIL=git+lb+mb@example.com
+}
+
+func ExampleSession_Environment_committer() {
+	s := &Session{
+		Name:           "Lindsay Bluth and Michael Bluth",
+		Email:          "git+lb+mb@example.com",
+		Committer:      "Michael Bluth",
+		CommitterEmail: "mb@example.com",
+	}
+	for _, kv := range s.Environment() {
+		fmt.Println(kv)
+	}
+
+	// Output:
+	// GIT_AUTHOR_NAME=Lindsay Bluth and Michael Bluth
+	// GIT_AUTHOR_EMAIL=git+lb+mb@example.com
+	// GIT_COMMITTER_NAME=Michael Bluth
+	// GIT_COMMITTER_EMAIL=mb@example.com
+}
+
+func TestLastPaired(t *testing.T) {
+	monday := time.Date(2019, 1, 7, 9, 0, 0, 0, time.UTC)
+	tuesday := monday.Add(24 * time.Hour)
+	last := LastPaired([]*Session{
+		{Started: tuesday, Authors: []*cfg.Author{{Alias: "lb"}, {Alias: "mb"}}},
+		{Started: monday, Authors: []*cfg.Author{{Alias: "gb"}, {Alias: "mb"}}},
+	})
+	if !last["mb"].Equal(tuesday) || !last["lb"].Equal(tuesday) || !last["gb"].Equal(monday) {
+		t.Fatalf("expected the latest session per alias, got %v", last)
+	}
+	if _, ok := last["bb"]; ok {
+		t.Fatalf("expected no entry for someone who never paired")
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	s := &Session{Authors: []*cfg.Author{{Alias: "lb"}, {Alias: "mb"}}}
+	if !s.Includes("lb") || s.Includes("gb") {
+		t.Fatalf("expected only lb and mb to be included")
+	}
+}
+
+func TestWriteState(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "state")
+	defer os.RemoveAll(dir) // clean up
+	path := filepath.Join(dir, "pair", "current")
+
+	s := New("Lindsay Bluth and Michael Bluth", "git+lb+mb@example.com", []*cfg.Author{{Alias: "lb"}, {Alias: "mb"}})
+	if err := WriteState(path, s); err != nil {
+		t.Fatalf("expected no error writing state, got %v", err)
+	}
+	buf, _ := ioutil.ReadFile(path)
+	if string(buf) != "lb+mb\n" {
+		t.Fatalf("expected the aliases in the state file, got %q", buf)
+	}
+}
+
+func TestLastIn(t *testing.T) {
+	monday := time.Date(2019, 1, 7, 9, 0, 0, 0, time.UTC)
+	tuesday := monday.Add(24 * time.Hour)
+	sessions := []*Session{
+		{ID: "a", Repo: "/src/pair", Started: monday, Authors: []*cfg.Author{{Alias: "lb"}, {Alias: "mb"}}},
+		{ID: "b", Repo: "/src/other", Started: tuesday, Authors: []*cfg.Author{{Alias: "gb"}, {Alias: "mb"}}},
+	}
+	if last := LastIn(sessions, "/src/pair"); last == nil || last.ID != "a" {
+		t.Fatalf("expected the last session in /src/pair, got %v", last)
+	}
+	if last := LastIn(sessions, "/src/nowhere"); last != nil {
+		t.Fatalf("expected no session for a repository never paired in, got %v", last)
+	}
+}
+
+func TestIdle(t *testing.T) {
+	monday := time.Date(2019, 1, 7, 9, 0, 0, 0, time.UTC)
+	s := &Session{Started: monday}
+	if idle := s.Idle(monday.Add(3 * time.Hour)); idle != 3*time.Hour {
+		t.Fatalf("expected idle time since the start, got %v", idle)
+	}
+	s.LastCommit = monday.Add(2 * time.Hour)
+	if idle := s.Idle(monday.Add(3 * time.Hour)); idle != time.Hour {
+		t.Fatalf("expected idle time since the last commit, got %v", idle)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	monday := time.Date(2019, 1, 7, 9, 0, 0, 0, time.UTC)
+	s := &Session{Started: monday}
+	if d := s.Duration(monday.Add(time.Hour)); d != time.Hour {
+		t.Fatalf("expected a session still going to last until now, got %v", d)
+	}
+	s.Ended = monday.Add(30 * time.Minute)
+	if d := s.Duration(monday.Add(time.Hour)); d != 30*time.Minute {
+		t.Fatalf("expected an ended session to last until it ended, got %v", d)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	monday := time.Date(2019, 1, 7, 9, 0, 0, 0, time.UTC)
+	s := &Session{
+		Started: monday,
+		Repo:    "/src/bluth",
+		Authors: []*cfg.Author{{Alias: "lb"}, {Alias: "mb"}},
+	}
+	if !(Filter{}).Match(s) {
+		t.Fatalf("expected an empty filter to match every session")
+	}
+	if !(Filter{Since: monday, With: []string{"lb"}, Repo: "/src/bluth"}).Match(s) {
+		t.Fatalf("expected the filter to match")
+	}
+	for _, f := range []Filter{
+		{Since: monday.Add(time.Hour)},
+		{With: []string{"lb", "gb"}},
+		{Repo: "/src/banana-stand"},
+	} {
+		if f.Match(s) {
+			t.Fatalf("expected %+v not to match", f)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	started := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	s := &Session{ID: "abc", Authors: []*cfg.Author{{Alias: "lb"}, {Alias: "mb"}}, Started: started}
+	if sum := s.Summarize(8*time.Hour, started.Add(65*time.Minute)); sum.String() != "lb+mb 1h05m ok" {
+		t.Fatalf("expected a fresh pair, got %q", sum)
+	}
+	sum := s.Summarize(8*time.Hour, started.Add(9*time.Hour))
+	if sum.String() != "lb+mb 9h00m expired" || !sum.Expires.Equal(started.Add(8*time.Hour)) {
+		t.Fatalf("expected the pair to have expired at 17:00, got %q, %v", sum, sum.Expires)
+	}
+
+	s.Authors = s.Authors[:1]
+	if sum := s.Summarize(8*time.Hour, started.Add(9*time.Hour)); sum.String() != "lb 9h00m ok" || sum.Expires != nil {
+		t.Fatalf("expected just you never to expire, got %q", sum)
+	}
+	if sum := (&Session{}).Summarize(0, started); sum.String() != "" || sum.State != Fresh {
+		t.Fatalf("expected an empty summary when not pairing, got %q", sum)
+	}
+}
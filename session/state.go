@@ -0,0 +1,50 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+// StatePath returns the location of the prompt state file, which holds the
+// current pair's aliases so prompts and status bars can `cat` it instead of
+// running pair.
+func StatePath() string {
+	return filepath.Join(cfg.CacheDir(), "current")
+}
+
+// Aliases returns the aliases of the session's authors joined with "+".
+// For example, "lb+mb".
+func (s *Session) Aliases() string {
+	aliases := make([]string, len(s.Authors))
+	for i, a := range s.Authors {
+		aliases[i] = a.Alias
+	}
+	return strings.Join(aliases, "+")
+}
+
+// WriteState writes the aliases of s to the prompt state file at path,
+// replacing it atomically so readers never see a partial write.
+func WriteState(path string, s *Session) error {
+	return writeState(path, s.Aliases())
+}
+
+// WriteExpiredState is like WriteState, but marks the pair as expired, e.g.
+// "lb+mb (expired)", so prompts show it needs renewing.
+func WriteExpiredState(path string, s *Session) error {
+	return writeState(path, s.Aliases()+" (expired)")
+}
+
+func writeState(path, state string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(state+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// Summary is what prompts and status bars show of the session: who's
+// pairing, for how long, and whether the pair has expired. Its JSON and
+// String forms are stable, for scripts.
+type Summary struct {
+	Pair    string     `json:"pair"`              // Aliases joined with "+". e.g. lb+mb, or empty when not pairing
+	Aliases []string   `json:"aliases"`           // Everyone's alias. e.g. [lb, mb]
+	Age     string     `json:"age"`               // How long ago the session started. e.g. 1h05m
+	State   string     `json:"state"`             // Fresh or Expired
+	Started *time.Time `json:"started,omitempty"` // When the pair was set
+	Expires *time.Time `json:"expires,omitempty"` // When the pair expires, if it does
+}
+
+// Summary states.
+const (
+	Fresh   = "ok"
+	Expired = "expired"
+)
+
+// Summarize describes the session as of now, given the session_ttl. Only a
+// pair expires, never just you; see Stale.
+func (s *Session) Summarize(ttl time.Duration, now time.Time) *Summary {
+	sum := &Summary{Aliases: []string{}, State: Fresh}
+	if s.ID == "" {
+		return sum
+	}
+	for _, a := range s.Authors {
+		sum.Aliases = append(sum.Aliases, a.Alias)
+	}
+	sum.Pair = s.Aliases()
+	sum.Age = FormatAge(now.Sub(s.Started))
+	started := s.Started
+	sum.Started = &started
+	if len(s.Authors) < 2 {
+		return sum
+	}
+	if deadline := s.Deadline(ttl); !deadline.IsZero() {
+		sum.Expires = &deadline
+	}
+	if s.Stale(ttl, now) {
+		sum.State = Expired
+	}
+	return sum
+}
+
+// String formats the summary as a single line of its pair, age and state
+// separated by spaces, e.g. "lb+mb 1h05m ok", or an empty line when not
+// pairing.
+func (m *Summary) String() string {
+	if m.Pair == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s %s %s", m.Pair, m.Age, m.State)
+}
+
+// FormatAge formats d compactly for a prompt, e.g. 1h05m or 12m.
+func FormatAge(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,141 @@
+// Package shell renders environment variables in the syntax of the shells
+// pair can export its identity to.
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Detect guesses the shell pair is running under from $SHELL, recognizing
+// PowerShell by $PSModulePath. It falls back to bash.
+func Detect() string {
+	switch name := strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe"); name {
+	case "sh", "bash", "zsh", "fish":
+		return name
+	}
+	if os.Getenv("PSModulePath") != "" {
+		return "powershell"
+	}
+	return "bash"
+}
+
+// Quote quotes s for a POSIX shell.
+func Quote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// QuoteFish quotes s for fish, which only treats \\ and \' specially within
+// single quotes.
+func QuoteFish(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+// QuotePowerShell quotes s for PowerShell, where single quotes are escaped
+// by doubling them.
+func QuotePowerShell(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// Exports returns a line setting each KEY=value in env, in the syntax of the
+// named shell.
+func Exports(name string, env []string) (string, error) {
+	var b strings.Builder
+	for _, kv := range env {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return "", fmt.Errorf("invalid environment variable: %s", kv)
+		}
+		switch name {
+		case "sh", "bash", "zsh":
+			fmt.Fprintf(&b, "export %s=%s\n", parts[0], Quote(parts[1]))
+		case "fish":
+			fmt.Fprintf(&b, "set -gx %s %s\n", parts[0], QuoteFish(parts[1]))
+		case "powershell", "pwsh":
+			fmt.Fprintf(&b, "$env:%s = %s\n", parts[0], QuotePowerShell(parts[1]))
+		default:
+			return "", fmt.Errorf("unsupported shell: %s", name)
+		}
+	}
+	return b.String(), nil
+}
+
+// wrappedCommands lists the subcommands after which the shell wrapper
+// re-exports the pair into the current shell.
+var wrappedCommands = []string{"with", "self", "me"}
+
+// Init returns a shell function wrapping the pair binary for the named shell.
+// After any command that changes the pair, the function evaluates the output
+// of `pair env` so the new identity applies to the current shell alone, and
+// notes that this shell changed the pair.
+func Init(name string) (string, error) {
+	switch name {
+	case "sh", "bash", "zsh":
+		return fmt.Sprintf(`pair() {
+	case "$1" in
+	%s)
+		command pair "$@" && eval "$(command pair env --shell %s)" && __pair_started=1
+		;;
+	*)
+		command pair "$@"
+		;;
+	esac
+}
+`, strings.Join(wrappedCommands, "|"), name), nil
+	case "fish":
+		return fmt.Sprintf(`function pair
+	switch $argv[1]
+	case %s
+		command pair $argv; and command pair env --shell fish | source; and set -g __pair_started 1
+	case '*'
+		command pair $argv
+	end
+end
+`, strings.Join(wrappedCommands, " ")), nil
+	case "powershell", "pwsh":
+		return fmt.Sprintf(`function pair {
+	$pairExe = (Get-Command pair -CommandType Application | Select-Object -First 1).Source
+	& $pairExe @args
+	if ($LASTEXITCODE -eq 0 -and $args.Count -gt 0 -and @(%s) -contains $args[0]) {
+		& $pairExe env --shell powershell | Out-String | Invoke-Expression
+		$global:__pairStarted = $true
+	}
+}
+`, "'"+strings.Join(wrappedCommands, "', '")+"'"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s", name)
+	}
+}
+
+// UnpairOnExit returns code for the named shell which, when the shell exits,
+// reverts to your personal identity if the shell changed the pair through
+// the Init wrapper. Borrowed workstations then never keep your pair active.
+func UnpairOnExit(name string) (string, error) {
+	switch name {
+	case "sh", "bash":
+		return `trap '[ -n "$__pair_started" ] && command pair self >/dev/null 2>&1' EXIT
+`, nil
+	case "zsh":
+		return `__pair_unpair() { [[ -n "$__pair_started" ]] && command pair self >/dev/null 2>&1 }
+autoload -Uz add-zsh-hook && add-zsh-hook zshexit __pair_unpair
+`, nil
+	case "fish":
+		return `function __pair_unpair --on-event fish_exit
+	set -q __pair_started; and command pair self >/dev/null 2>&1
+end
+`, nil
+	case "powershell", "pwsh":
+		return `Register-EngineEvent -SourceIdentifier PowerShell.Exiting -Action {
+	if ($global:__pairStarted) {
+		$pairExe = (Get-Command pair -CommandType Application | Select-Object -First 1).Source
+		& $pairExe self | Out-Null
+	}
+} | Out-Null
+`, nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s", name)
+	}
+}
@@ -0,0 +1,98 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func ExampleExports() {
+	exports, _ := Exports("bash", []string{"GIT_AUTHOR_NAME=Michael Bluth", "GIT_AUTHOR_EMAIL=mb@example.com"})
+	fmt.Print(exports)
+
+	// Output:
+	// export GIT_AUTHOR_NAME='Michael Bluth'
+	// export GIT_AUTHOR_EMAIL='mb@example.com'
+}
+
+func TestQuote(t *testing.T) {
+	if q := Quote("Gob's boat"); q != `'Gob'\''s boat'` {
+		t.Fatalf("expected single quotes to be escaped, got %s", q)
+	}
+	if q := QuoteFish(`Gob's \boat`); q != `'Gob\'s \\boat'` {
+		t.Fatalf("expected fish quotes and backslashes to be escaped, got %s", q)
+	}
+}
+
+func ExampleExports_fish() {
+	exports, _ := Exports("fish", []string{"GIT_AUTHOR_NAME=Michael Bluth"})
+	fmt.Print(exports)
+
+	// Output:
+	// set -gx GIT_AUTHOR_NAME 'Michael Bluth'
+}
+
+func ExampleExports_powershell() {
+	exports, _ := Exports("powershell", []string{"GIT_AUTHOR_NAME=Gob's Boat"})
+	fmt.Print(exports)
+
+	// Output:
+	// $env:GIT_AUTHOR_NAME = 'Gob''s Boat'
+}
+
+func TestDetect(t *testing.T) {
+	shell, psModulePath := os.Getenv("SHELL"), os.Getenv("PSModulePath")
+	defer os.Setenv("SHELL", shell)
+	defer os.Setenv("PSModulePath", psModulePath)
+
+	os.Setenv("SHELL", "/usr/local/bin/fish")
+	if name := Detect(); name != "fish" {
+		t.Fatalf("expected fish from $SHELL, got %s", name)
+	}
+	os.Setenv("SHELL", "")
+	os.Setenv("PSModulePath", `C:\Program Files\PowerShell\Modules`)
+	if name := Detect(); name != "powershell" {
+		t.Fatalf("expected powershell from $PSModulePath, got %s", name)
+	}
+	os.Setenv("PSModulePath", "")
+	if name := Detect(); name != "bash" {
+		t.Fatalf("expected bash by default, got %s", name)
+	}
+}
+
+func TestInit(t *testing.T) {
+	for _, name := range []string{"bash", "zsh", "fish", "powershell"} {
+		script, err := Init(name)
+		if err != nil {
+			t.Fatalf("expected no error for %s, got %v", name, err)
+		}
+		if !strings.Contains(script, "env --shell "+name) {
+			t.Fatalf("expected %s wrapper to evaluate pair env, got %s", name, script)
+		}
+	}
+	if _, err := Init("tcsh"); err == nil {
+		t.Fatalf("expected an error for an unsupported shell")
+	}
+}
+
+func TestUnpairOnExit(t *testing.T) {
+	for _, name := range []string{"bash", "zsh", "fish", "powershell"} {
+		script, err := UnpairOnExit(name)
+		if err != nil {
+			t.Fatalf("expected no error for %s, got %v", name, err)
+		}
+		if !strings.Contains(script, " self") || !strings.Contains(strings.ToLower(script), "started") {
+			t.Fatalf("expected %s to revert with pair self only after pairing, got %s", name, script)
+		}
+	}
+	if _, err := UnpairOnExit("tcsh"); err == nil {
+		t.Fatalf("expected an error for an unsupported shell")
+	}
+}
+
+func TestExportsUnsupportedShell(t *testing.T) {
+	if _, err := Exports("tcsh", []string{"A=1"}); err == nil {
+		t.Fatalf("expected an error for an unsupported shell")
+	}
+}
@@ -0,0 +1,195 @@
+// Package signing inspects the commit signing setup, so that problems with a
+// paired identity's key surface before a signed commit fails.
+package signing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Key describes an OpenPGP key as reported by gpg.
+type Key struct {
+	ID      string    // Key ID. e.g. 1234ABCD5678EF90
+	Expires time.Time // Zero if the key never expires
+	Revoked bool      // Whether gpg considers the key revoked
+	UIDs    []string  // User IDs. e.g. Michael Bluth <mb@example.com>
+}
+
+// Expired reports whether the key has expired as of now.
+func (k *Key) Expired(now time.Time) bool {
+	return !k.Expires.IsZero() && now.After(k.Expires)
+}
+
+// HasEmail reports whether one of the key's user IDs carries email.
+func (k *Key) HasEmail(email string) bool {
+	for _, uid := range k.UIDs {
+		if strings.Contains(strings.ToLower(uid), "<"+strings.ToLower(email)+">") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseGPG reads the first public key from `gpg --with-colons` output.
+func ParseGPG(r io.Reader) (*Key, error) {
+	var key *Key
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 10 {
+			continue
+		}
+		switch fields[0] {
+		case "pub":
+			if key != nil {
+				return key, nil
+			}
+			key = &Key{ID: fields[4], Revoked: fields[1] == "r"}
+			if fields[6] != "" {
+				secs, err := strconv.ParseInt(fields[6], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid expiry for key %s: %v", key.ID, err)
+				}
+				key.Expires = time.Unix(secs, 0)
+			}
+		case "uid":
+			if key != nil {
+				key.UIDs = append(key.UIDs, fields[9])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("no public key found")
+	}
+	return key, nil
+}
+
+// LookupGPG asks gpg (or program, if set) for the public key id.
+func LookupGPG(program string, id string) (*Key, error) {
+	if program == "" {
+		program = "gpg"
+	}
+	output, err := exec.Command(program, "--with-colons", "--list-keys", id).Output()
+	if err != nil {
+		return nil, fmt.Errorf("key %s not found: %v", id, err)
+	}
+	return ParseGPG(strings.NewReader(string(output)))
+}
+
+// ParseAllowedSigners returns the principals listed in an ssh allowed signers
+// file, keyed by principal.
+func ParseAllowedSigners(r io.Reader) (map[string]bool, error) {
+	principals := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, p := range strings.Split(strings.Fields(line)[0], ",") {
+			principals[strings.ToLower(p)] = true
+		}
+	}
+	return principals, scanner.Err()
+}
+
+// IsSSHKey reports whether key is an SSH public key, as opposed to a GPG key
+// id.
+func IsSSHKey(key string) bool {
+	for _, prefix := range []string{"ssh-", "ecdsa-", "sk-ssh-", "sk-ecdsa-"} {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedSigners formats the lines of an ssh allowed signers file letting
+// each email in keys sign commits with its SSH public key, sorted by email.
+func AllowedSigners(keys map[string]string) string {
+	var emails []string
+	for email := range keys {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	var b strings.Builder
+	for _, email := range emails {
+		fmt.Fprintf(&b, "%s namespaces=\"git\" %s\n", email, keys[email])
+	}
+	return b.String()
+}
+
+// Config is the subset of git configuration that controls commit signing.
+type Config struct {
+	Format         string // gpg.format: openpgp (default), ssh or x509
+	Program        string // gpg.program
+	Key            string // user.signingkey
+	AllowedSigners string // gpg.ssh.allowedSignersFile
+}
+
+// Verify checks that cfg can sign commits as each of emails, returning every
+// problem found.
+func Verify(cfg Config, emails []string, now time.Time) []error {
+	if cfg.Key == "" {
+		return []error{fmt.Errorf("user.signingkey is not set")}
+	}
+
+	var problems []error
+	switch cfg.Format {
+	case "", "openpgp":
+		key, err := LookupGPG(cfg.Program, cfg.Key)
+		if err != nil {
+			return []error{err}
+		}
+		if key.Revoked {
+			problems = append(problems, fmt.Errorf("key %s has been revoked", key.ID))
+		}
+		if key.Expired(now) {
+			problems = append(problems, fmt.Errorf("key %s expired on %s", key.ID, key.Expires.Format("2006-01-02")))
+		}
+		for _, email := range emails {
+			if !key.HasEmail(email) {
+				problems = append(problems, fmt.Errorf("key %s has no user ID for %s", key.ID, email))
+			}
+		}
+	case "ssh":
+		if !strings.HasPrefix(cfg.Key, "key::") {
+			if _, err := os.Stat(cfg.Key); err != nil {
+				problems = append(problems, fmt.Errorf("ssh signing key %s: %v", cfg.Key, err))
+			}
+		}
+		if cfg.AllowedSigners == "" {
+			problems = append(problems, fmt.Errorf("gpg.ssh.allowedSignersFile is not set"))
+			break
+		}
+		f, err := os.Open(cfg.AllowedSigners)
+		if err != nil {
+			problems = append(problems, err)
+			break
+		}
+		principals, err := ParseAllowedSigners(f)
+		f.Close()
+		if err != nil {
+			problems = append(problems, err)
+			break
+		}
+		for _, email := range emails {
+			if !principals[strings.ToLower(email)] {
+				problems = append(problems, fmt.Errorf("%s is not an allowed signer in %s", email, cfg.AllowedSigners))
+			}
+		}
+	default:
+		problems = append(problems, fmt.Errorf("unable to verify gpg.format %s", cfg.Format))
+	}
+	return problems
+}
@@ -0,0 +1,89 @@
+package signing
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const gpgOutput = `tru::1:1546300800:0:3:1:5
+pub:e:255:22:1234ABCD5678EF90:1546300800:1577836800::u:::scESC::::::ed25519:::0:
+fpr:::::::::AAAABBBBCCCCDDDDEEEE1234ABCD5678EF90:
+uid:e::::1546300800::HASH::Michael Bluth <mb@example.com>::::::::::0:
+uid:e::::1546300800::HASH::Michael Bluth <git+lb+mb@example.com>::::::::::0:
+sub:e:255:18:0000111122223333:1546300800:1577836800:::::e::::::cv25519::
+`
+
+func TestParseGPG(t *testing.T) {
+	key, err := ParseGPG(strings.NewReader(gpgOutput))
+	if err != nil {
+		t.Fatalf("expected no error parsing gpg output, got %v", err)
+	}
+	if key.ID != "1234ABCD5678EF90" {
+		t.Fatalf("expected key ID 1234ABCD5678EF90, got %s", key.ID)
+	}
+	if len(key.UIDs) != 2 {
+		t.Fatalf("expected two user IDs, got %v", key.UIDs)
+	}
+	if !key.Expired(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected key to be expired in 2020")
+	}
+	if key.Expired(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected key to be valid in mid 2019")
+	}
+	if !key.HasEmail("GIT+lb+mb@example.com") {
+		t.Fatalf("expected key to have a user ID for the pair email")
+	}
+	if key.HasEmail("lb@example.com") {
+		t.Fatalf("expected key to have no user ID for lb@example.com")
+	}
+
+	if _, err := ParseGPG(strings.NewReader("")); err == nil {
+		t.Fatalf("expected an error when gpg reports no key")
+	}
+}
+
+func TestParseAllowedSigners(t *testing.T) {
+	principals, err := ParseAllowedSigners(strings.NewReader(`# team signers
+mb@example.com,git+lb+mb@example.com ssh-ed25519 AAAAC3Nza
+lb@example.com namespaces="git" ssh-ed25519 AAAAC3Nzb
+`))
+	if err != nil {
+		t.Fatalf("expected no error parsing allowed signers, got %v", err)
+	}
+	for _, p := range []string{"mb@example.com", "git+lb+mb@example.com", "lb@example.com"} {
+		if !principals[p] {
+			t.Fatalf("expected %s to be an allowed signer, got %v", p, principals)
+		}
+	}
+	if len(principals) != 3 {
+		t.Fatalf("expected three principals, got %v", principals)
+	}
+}
+
+func TestVerifyWithoutKey(t *testing.T) {
+	problems := Verify(Config{}, []string{"mb@example.com"}, time.Now())
+	if len(problems) != 1 {
+		t.Fatalf("expected a single problem when no key is configured, got %v", problems)
+	}
+}
+
+func ExampleAllowedSigners() {
+	fmt.Print(AllowedSigners(map[string]string{
+		"mb@example.com": "ssh-ed25519 AAAAC3Nza mb",
+		"lb@example.com": "ssh-ed25519 AAAAC3Nzb lb",
+	}))
+	// Output:
+	// lb@example.com namespaces="git" ssh-ed25519 AAAAC3Nzb lb
+	// mb@example.com namespaces="git" ssh-ed25519 AAAAC3Nza mb
+}
+
+func TestIsSSHKey(t *testing.T) {
+	if !IsSSHKey("ssh-ed25519 AAAAC3Nza") || !IsSSHKey("ecdsa-sha2-nistp256 AAAAE2Vj") {
+		t.Fatalf("expected ssh public keys to be recognized")
+	}
+	if IsSSHKey("3AA5C34371567BD2") {
+		t.Fatalf("expected a gpg key id not to be an ssh key")
+	}
+}
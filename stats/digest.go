@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Pairing is the time two people spent pairing.
+type Pairing struct {
+	A, B string // Aliases, in order
+	Totals
+}
+
+// Pairings returns every pair of people who paired, longest first, then
+// with the most commits.
+func (s *Summary) Pairings() []*Pairing {
+	var pairings []*Pairing
+	for a, row := range s.Matrix {
+		for b, t := range row {
+			if a < b {
+				pairings = append(pairings, &Pairing{A: a, B: b, Totals: *t})
+			}
+		}
+	}
+	sort.Slice(pairings, func(i, j int) bool {
+		if pairings[i].Duration != pairings[j].Duration {
+			return pairings[i].Duration > pairings[j].Duration
+		}
+		if pairings[i].Commits != pairings[j].Commits {
+			return pairings[i].Commits > pairings[j].Commits
+		}
+		return pairings[i].A+"+"+pairings[i].B < pairings[j].A+"+"+pairings[j].B
+	})
+	return pairings
+}
+
+// WriteDigest writes a short plain text summary, suitable for an email or a
+// chat message.
+func (s *Summary) WriteDigest(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pairing from %s to %s\n\n", s.From.Format("2006-01-02"), s.To.Format("2006-01-02"))
+	if len(s.Timeline) == 0 {
+		b.WriteString("No pairing sessions.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	var paired Totals
+	for _, e := range s.Timeline {
+		if strings.Contains(e.Aliases, "+") {
+			paired.Sessions++
+			paired.Commits += e.Commits
+			paired.Duration += e.Duration
+		}
+	}
+	fmt.Fprintf(&b, "%s, %s, %s.\n", plural(paired.Sessions, "pairing session"), hours(paired.Duration), plural(paired.Commits, "commit"))
+
+	if pairings := s.Pairings(); len(pairings) > 0 {
+		b.WriteString("\nPairs:\n")
+		for _, p := range pairings {
+			fmt.Fprintf(&b, "  %s+%s  %s, %s\n", p.A, p.B, hours(p.Duration), plural(p.Commits, "commit"))
+		}
+	}
+
+	b.WriteString("\nPeople:\n")
+	for _, p := range s.People {
+		fmt.Fprintf(&b, "  %s (%s)  paired %s, solo %s\n", p.Name, p.Alias, hours(p.Paired.Duration), hours(p.Solo.Duration))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Week returns the week before now, for a weekly digest.
+func Week(now time.Time) (from, to time.Time) {
+	return now.AddDate(0, 0, -7), now
+}
+
+// plural formats a count of things. e.g. 1 commit, 2 commits
+func plural(n int, thing string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, thing)
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// pairingJSON is a Pairing as written by WriteJSON.
+type pairingJSON struct {
+	A        string  `json:"a"`
+	B        string  `json:"b"`
+	Sessions int     `json:"sessions"`
+	Commits  int     `json:"commits"`
+	Hours    float64 `json:"hours"`
+	Last     string  `json:"last,omitempty"`
+}
+
+func newPairingJSON(p *Pairing) pairingJSON {
+	j := pairingJSON{A: p.A, B: p.B, Sessions: p.Sessions, Commits: p.Commits, Hours: p.Duration.Hours()}
+	if !p.Last.IsZero() {
+		j.Last = p.Last.Format(time.RFC3339)
+	}
+	return j
+}
+
+// WriteJSON writes the pairings, and the unpaired from Unpaired, as JSON.
+func (s *Summary) WriteJSON(w io.Writer, unpaired []*Pairing) error {
+	out := struct {
+		Pairs       []pairingJSON `json:"pairs"`
+		NeverPaired [][2]string   `json:"never_paired"`
+	}{Pairs: []pairingJSON{}, NeverPaired: [][2]string{}}
+	for _, p := range s.Pairings() {
+		out.Pairs = append(out.Pairs, newPairingJSON(p))
+	}
+	for _, p := range unpaired {
+		out.NeverPaired = append(out.NeverPaired, [2]string{p.A, p.B})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteCSV writes a row for each pairing, followed by the unpaired from
+// Unpaired with nothing to count, for a spreadsheet.
+func (s *Summary) WriteCSV(w io.Writer, unpaired []*Pairing) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"a", "b", "sessions", "commits", "hours", "last"})
+	for _, p := range append(s.Pairings(), unpaired...) {
+		j := newPairingJSON(p)
+		out.Write([]string{j.A, j.B, fmt.Sprint(j.Sessions), fmt.Sprint(j.Commits), fmt.Sprintf("%.1f", j.Hours), j.Last})
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// page is the HTML report. It's self-contained, with inline styles and no
+// scripts, so it can be attached to a retro or opened from disk.
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"hours":   hours,
+	"date":    func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	"day":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"percent": func(part, whole time.Duration) string { return fmt.Sprintf("%.1f%%", 100*float64(part)/float64(whole)) },
+	"shade": func(part, whole time.Duration) template.CSS {
+		return template.CSS(fmt.Sprintf("background: rgba(46, 125, 50, %.2f)", float64(part)/float64(whole)))
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ddd; padding: 0.3em 0.6em; text-align: left; }
+td.n { text-align: right; }
+.bar { background: #2e7d32; height: 0.8em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if not .Summary.People}}<p>No pairing sessions yet.</p>{{else}}
+<h2>Totals</h2>
+<table>
+<tr><th>Alias</th><th>Name</th><th>Paired</th><th>Paired commits</th><th>Solo</th><th>Solo commits</th></tr>
+{{range .Summary.People}}<tr><td>{{.Alias}}</td><td>{{.Name}}</td><td class="n">{{hours .Paired.Duration}}</td><td class="n">{{.Paired.Commits}}</td><td class="n">{{hours .Solo.Duration}}</td><td class="n">{{.Solo.Commits}}</td></tr>
+{{end}}</table>
+
+<h2>Pairing matrix</h2>
+<p>Hours spent pairing, with commits made together in brackets.</p>
+<table>
+<tr><th></th>{{range .Summary.People}}<th>{{.Alias}}</th>{{end}}</tr>
+{{range $a := .Summary.People}}<tr><th>{{$a.Alias}}</th>{{range $b := $.Summary.People}}{{with $.Summary.Between $a.Alias $b.Alias}}<td class="n" style="{{shade .Duration $.Longest}}">{{if .Sessions}}{{hours .Duration}} ({{.Commits}}){{end}}</td>{{end}}{{end}}</tr>
+{{end}}</table>
+
+<h2>Timeline</h2>
+<table>
+<tr><th>Started</th><th>Pair</th><th>Duration</th><th>Commits</th><th></th></tr>
+{{range .Summary.Timeline}}<tr><td>{{date .Started}}</td><td>{{.Label}}</td><td class="n">{{hours .Duration}}</td><td class="n">{{.Commits}}</td><td style="width: 15em"><div class="bar" style="width: {{percent .Duration $.LongestSession}}"></div></td></tr>
+{{end}}</table>
+{{end}}
+<p><small>Generated by pair on {{day .Generated}}.</small></p>
+</body>
+</html>
+`))
+
+// WriteHTML writes the summary as a self-contained HTML page with the
+// pairing matrix, per-person totals and a timeline of sessions.
+func (s *Summary) WriteHTML(w io.Writer, title string, generated time.Time) error {
+	data := struct {
+		Title                   string
+		Summary                 *Summary
+		Longest, LongestSession time.Duration
+		Generated               time.Time
+	}{Title: title, Summary: s, Longest: time.Nanosecond, LongestSession: time.Nanosecond, Generated: generated}
+	for _, row := range s.Matrix {
+		for _, t := range row {
+			if t.Duration > data.Longest {
+				data.Longest = t.Duration
+			}
+		}
+	}
+	for _, e := range s.Timeline {
+		if e.Duration > data.LongestSession {
+			data.LongestSession = e.Duration
+		}
+	}
+	return page.Execute(w, data)
+}
+
+// hours formats d in hours to one decimal place. e.g. 2.5h
+func hours(d time.Duration) string {
+	return fmt.Sprintf("%.1fh", d.Hours())
+}
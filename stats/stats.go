@@ -0,0 +1,165 @@
+// Package stats summarizes the session history: who paired with whom, for
+// how long, and how many commits they made together.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+)
+
+// Totals counts pairing activity.
+type Totals struct {
+	Sessions int
+	Commits  int
+	Duration time.Duration
+	Last     time.Time // When the last session started, or the last commit was made
+}
+
+func (t *Totals) add(s *session.Session, d time.Duration) {
+	t.Sessions++
+	t.Commits += len(s.Commits)
+	t.Duration += d
+	if s.Started.After(t.Last) {
+		t.Last = s.Started
+	}
+}
+
+// Person is someone who appears in the session history.
+type Person struct {
+	Alias  string
+	Name   string // Label of the author. e.g. Lindsay (she/her)
+	Solo   Totals // Time spent working alone
+	Paired Totals // Time spent pairing with anyone
+}
+
+// Summary is the pairing activity across a session history.
+type Summary struct {
+	From, To time.Time
+	People   []*Person                     // Everyone, sorted by alias
+	Matrix   map[string]map[string]*Totals // Pairing between two aliases, in both directions
+	Timeline []*Entry                      // Sessions, oldest first
+}
+
+// Entry is a session on the timeline.
+type Entry struct {
+	Label    string // Composed label of the authors. e.g. Lindsay and Michael Bluth
+	Aliases  string // e.g. lb+mb
+	Started  time.Time
+	Duration time.Duration
+	Commits  int
+}
+
+// Commit is a commit in the git history and everyone credited with it.
+type Commit struct {
+	Authors []*cfg.Author // The author and co-authors
+	Time    time.Time     // When it was authored
+}
+
+// FromCommits summarizes the commits made in [from, to), for when there's no
+// session history to go by, such as on a teammate's machine. Each commit
+// counts as a session of no time at all.
+func FromCommits(commits []*Commit, from, to time.Time) *Summary {
+	sessions := make([]*session.Session, len(commits))
+	for i, c := range commits {
+		sessions[i] = &session.Session{Authors: c.Authors, Started: c.Time, Ended: c.Time, Commits: []string{""}}
+	}
+	s := Summarize(sessions, from, to, time.Time{})
+	s.Timeline = nil
+	return s
+}
+
+// Summarize summarizes the sessions started in [from, to). A zero from or to
+// is unbounded. Sessions still going are counted up to now.
+func Summarize(sessions []*session.Session, from, to, now time.Time) *Summary {
+	summary := &Summary{From: from, To: to, Matrix: make(map[string]map[string]*Totals)}
+	people := make(map[string]*Person)
+	for _, s := range sessions {
+		if len(s.Authors) == 0 || s.Started.Before(from) || !to.IsZero() && !s.Started.Before(to) {
+			continue
+		}
+		d := s.Duration(now)
+		summary.Timeline = append(summary.Timeline, &Entry{
+			Label:    cfg.ComposeLabel(s.Authors),
+			Aliases:  s.Aliases(),
+			Started:  s.Started,
+			Duration: d,
+			Commits:  len(s.Commits),
+		})
+
+		for _, a := range s.Authors {
+			p := people[a.Alias]
+			if p == nil {
+				p = &Person{Alias: a.Alias}
+				people[a.Alias] = p
+			}
+			p.Name = a.Label()
+			if len(s.Authors) == 1 {
+				p.Solo.add(s, d)
+			} else {
+				p.Paired.add(s, d)
+			}
+			for _, b := range s.Authors {
+				if a.Alias == b.Alias {
+					continue
+				}
+				row := summary.Matrix[a.Alias]
+				if row == nil {
+					row = make(map[string]*Totals)
+					summary.Matrix[a.Alias] = row
+				}
+				if row[b.Alias] == nil {
+					row[b.Alias] = &Totals{}
+				}
+				row[b.Alias].add(s, d)
+			}
+		}
+	}
+
+	for _, p := range people {
+		summary.People = append(summary.People, p)
+	}
+	sort.Slice(summary.People, func(i, j int) bool { return summary.People[i].Alias < summary.People[j].Alias })
+	sort.SliceStable(summary.Timeline, func(i, j int) bool { return summary.Timeline[i].Started.Before(summary.Timeline[j].Started) })
+	return summary
+}
+
+// Between returns the totals for a pairing with b, which are zero if they
+// never paired.
+func (s *Summary) Between(a, b string) Totals {
+	if t := s.Matrix[a][b]; t != nil {
+		return *t
+	}
+	return Totals{}
+}
+
+// Unpaired returns every two of aliases, and of the people in the summary,
+// who never paired, in order.
+func (s *Summary) Unpaired(aliases []string) []*Pairing {
+	seen := make(map[string]bool)
+	var everyone []string
+	for _, alias := range aliases {
+		if !seen[alias] {
+			seen[alias] = true
+			everyone = append(everyone, alias)
+		}
+	}
+	for _, p := range s.People {
+		if !seen[p.Alias] {
+			seen[p.Alias] = true
+			everyone = append(everyone, p.Alias)
+		}
+	}
+	sort.Strings(everyone)
+	var unpaired []*Pairing
+	for i, a := range everyone {
+		for _, b := range everyone[i+1:] {
+			if s.Matrix[a][b] == nil {
+				unpaired = append(unpaired, &Pairing{A: a, B: b})
+			}
+		}
+	}
+	return unpaired
+}
@@ -0,0 +1,143 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+)
+
+var (
+	lb  = &cfg.Author{Name: "Lindsay Bluth", Alias: "lb", DisplayName: "Lindsay"}
+	mb  = &cfg.Author{Name: "Michael Bluth", Alias: "mb"}
+	gb  = &cfg.Author{Name: "George Bluth", Alias: "gb"}
+	day = time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+)
+
+func sessions() []*session.Session {
+	return []*session.Session{
+		{Authors: []*cfg.Author{lb, mb}, Started: day, Ended: day.Add(2 * time.Hour), Commits: []string{"a", "b"}},
+		{Authors: []*cfg.Author{mb}, Started: day.Add(2 * time.Hour), Ended: day.Add(3 * time.Hour), Commits: []string{"c"}},
+		{Authors: []*cfg.Author{gb, mb}, Started: day.Add(3 * time.Hour), Commits: []string{"d"}},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize(sessions(), time.Time{}, time.Time{}, day.Add(4*time.Hour))
+	if len(s.People) != 3 || s.People[0].Alias != "gb" {
+		t.Fatalf("expected everyone sorted by alias, got %v", s.People)
+	}
+	mbTotals := s.People[2]
+	if mbTotals.Paired.Duration != 3*time.Hour || mbTotals.Paired.Commits != 3 || mbTotals.Solo.Duration != time.Hour {
+		t.Fatalf("expected mb to have paired 3h for 3 commits and worked alone 1h, got %+v", mbTotals)
+	}
+	if between := s.Between("lb", "mb"); between.Duration != 2*time.Hour || between != s.Between("mb", "lb") {
+		t.Fatalf("expected lb and mb to have paired 2h either way round, got %+v", between)
+	}
+	if between := s.Between("lb", "gb"); between.Sessions != 0 {
+		t.Fatalf("expected lb and gb never to have paired, got %+v", between)
+	}
+
+	s = Summarize(sessions(), day.Add(time.Hour), day.Add(3*time.Hour), day.Add(4*time.Hour))
+	if len(s.Timeline) != 1 || s.Timeline[0].Aliases != "mb" {
+		t.Fatalf("expected only the session started in range, got %v", s.Timeline)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	s := Summarize(sessions(), time.Time{}, time.Time{}, day.Add(4*time.Hour))
+	if err := s.WriteHTML(&buf, "Pairing <retro>", day); err != nil {
+		t.Fatalf("expected no error writing HTML, got %v", err)
+	}
+	html := buf.String()
+	for _, expected := range []string{
+		"<title>Pairing &lt;retro&gt;</title>",
+		"<td>lb</td><td>Lindsay</td>",
+		">2.0h (2)</td>",
+		"<td>Lindsay and Michael Bluth</td>",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Fatalf("expected the page to contain %s, got %s", expected, html)
+		}
+	}
+	if strings.Contains(html, "<script") {
+		t.Fatalf("expected a page without scripts")
+	}
+}
+
+func TestWriteDigest(t *testing.T) {
+	var buf bytes.Buffer
+	from, to := Week(day.Add(4 * time.Hour))
+	s := Summarize(sessions(), from, to, to)
+	if err := s.WriteDigest(&buf); err != nil {
+		t.Fatalf("expected no error writing the digest, got %v", err)
+	}
+	expected := `Pairing from 2026-10-05 to 2026-10-12
+
+2 pairing sessions, 3.0h, 3 commits.
+
+Pairs:
+  lb+mb  2.0h, 2 commits
+  gb+mb  1.0h, 1 commit
+
+People:
+  George Bluth (gb)  paired 1.0h, solo 0.0h
+  Lindsay (lb)  paired 2.0h, solo 0.0h
+  Michael Bluth (mb)  paired 3.0h, solo 1.0h
+`
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestFromCommits(t *testing.T) {
+	commits := []*Commit{
+		{Authors: []*cfg.Author{lb, mb}, Time: day},
+		{Authors: []*cfg.Author{lb, mb}, Time: day.Add(time.Hour)},
+		{Authors: []*cfg.Author{mb}, Time: day.Add(2 * time.Hour)},
+	}
+	s := FromCommits(commits, time.Time{}, time.Time{})
+	if between := s.Between("lb", "mb"); between.Commits != 2 || between.Duration != 0 || !between.Last.Equal(day.Add(time.Hour)) {
+		t.Fatalf("expected lb and mb to have made 2 commits together, last at 10:00, got %+v", between)
+	}
+	if len(s.People) != 2 || s.People[1].Solo.Commits != 1 {
+		t.Fatalf("expected mb to have made a commit alone, got %v", s.People)
+	}
+	unpaired := s.Unpaired([]string{"gb", "mb"})
+	if len(unpaired) != 2 || unpaired[0].A != "gb" || unpaired[0].B != "lb" || unpaired[1].B != "mb" {
+		t.Fatalf("expected gb never to have paired with lb or mb, got %v", unpaired)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	s := Summarize(sessions(), time.Time{}, time.Time{}, day.Add(4*time.Hour))
+	if err := s.WriteCSV(&buf, s.Unpaired(nil)); err != nil {
+		t.Fatalf("expected no error writing CSV, got %v", err)
+	}
+	expected := `a,b,sessions,commits,hours,last
+lb,mb,1,2,2.0,2026-10-12T09:00:00Z
+gb,mb,1,1,1.0,2026-10-12T12:00:00Z
+gb,lb,0,0,0.0,
+`
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	s := Summarize(sessions(), time.Time{}, time.Time{}, day.Add(4*time.Hour))
+	if err := s.WriteJSON(&buf, s.Unpaired(nil)); err != nil {
+		t.Fatalf("expected no error writing JSON, got %v", err)
+	}
+	for _, expected := range []string{`"a": "lb",`, `"hours": 2,`, `"never_paired": [`, `"gb",`} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("expected the JSON to contain %s, got %s", expected, buf.String())
+		}
+	}
+}
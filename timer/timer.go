@@ -0,0 +1,73 @@
+// Package timer counts down a driver's turn at the keyboard. Time is read
+// from a Clock, so the countdown can be tested without waiting for it.
+package timer
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// System is the clock on the wall.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Timer counts down a turn.
+type Timer struct {
+	Duration time.Duration // How long the turn lasts. e.g. 15m
+	Tick     time.Duration // How often the time left is reported, or zero for never
+	Clock    Clock         // Where the time comes from, System if nil
+}
+
+// Run counts down until the time is up, calling tick with the time left, a
+// whole number of seconds, every Tick along the way. It returns early when
+// stop is closed, reporting whether the time ran out.
+func (t *Timer) Run(stop <-chan struct{}, tick func(left time.Duration)) bool {
+	clock := t.Clock
+	if clock == nil {
+		clock = System
+	}
+	end := clock.Now().Add(t.Duration)
+	for {
+		left := end.Sub(clock.Now())
+		if left <= 0 {
+			return true
+		}
+		wait := left
+		if t.Tick > 0 {
+			if tick != nil {
+				tick(left.Round(time.Second))
+			}
+			if t.Tick < wait {
+				wait = t.Tick
+			}
+		}
+		select {
+		case <-clock.After(wait):
+		case <-stop:
+			return false
+		}
+	}
+}
+
+// Format formats the time left as minutes and seconds, e.g. 14:05, with
+// hours in front once there are any, e.g. 1:02:00.
+func Format(left time.Duration) string {
+	s := int(left.Round(time.Second).Seconds())
+	if s < 0 {
+		s = 0
+	}
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
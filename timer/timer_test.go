@@ -0,0 +1,67 @@
+package timer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock passes time instantly whenever it's waited on.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestRun(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)}
+	timer := &Timer{Duration: 150 * time.Second, Tick: time.Minute, Clock: clock}
+	var ticks []time.Duration
+	if !timer.Run(nil, func(left time.Duration) { ticks = append(ticks, left) }) {
+		t.Fatalf("expected the time to run out")
+	}
+	expected := []time.Duration{150 * time.Second, 90 * time.Second, 30 * time.Second}
+	if !reflect.DeepEqual(ticks, expected) {
+		t.Fatalf("expected ticks at %v, got %v", expected, ticks)
+	}
+	if elapsed := clock.now.Sub(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)); elapsed != 150*time.Second {
+		t.Fatalf("expected to wait exactly 2m30s, waited %v", elapsed)
+	}
+}
+
+func TestRunStopped(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+	timer := &Timer{Duration: time.Hour, Clock: &blockedClock{}}
+	if timer.Run(stop, nil) {
+		t.Fatalf("expected a stopped timer not to run out")
+	}
+}
+
+// blockedClock never lets any time pass.
+type blockedClock struct{}
+
+func (blockedClock) Now() time.Time                         { return time.Time{} }
+func (blockedClock) After(d time.Duration) <-chan time.Time { return nil }
+
+func TestFormat(t *testing.T) {
+	for left, expected := range map[time.Duration]string{
+		15 * time.Minute:                      "15:00",
+		65*time.Second + 400*time.Millisecond: "1:05",
+		time.Hour + 2*time.Minute:             "1:02:00",
+		-time.Second:                          "0:00",
+	} {
+		if formatted := Format(left); formatted != expected {
+			t.Fatalf("expected %v to be formatted %s, got %s", left, expected, formatted)
+		}
+	}
+}
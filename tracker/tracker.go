@@ -0,0 +1,174 @@
+// Package tracker looks up tickets in an issue tracker, Jira or GitHub
+// Issues, so branches can be named after what they're for.
+package tracker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/github"
+	"github.com/keeferrourke/pair/internal/httpx"
+)
+
+// Timeout bounds each request, so naming a branch offline falls back to the
+// bare ticket quickly.
+const Timeout = 5 * time.Second
+
+// Tracker is somewhere tickets can be looked up by ID.
+type Tracker interface {
+	// IsTicket reports whether id looks like one of the tracker's tickets,
+	// such as ONCALL-843 for Jira or #843 for GitHub.
+	IsTicket(id string) bool
+	// Title returns the title of the ticket with id.
+	Title(id string) (string, error)
+}
+
+// New returns the tracker described by the tracker setting, authenticating
+// with token, from pair auth.
+func New(c *cfg.Tracker, token string) (Tracker, error) {
+	client := httpx.New(Timeout)
+	client.Retries = 0
+	switch c.Kind {
+	case cfg.TrackJira:
+		return &jira{site: strings.TrimRight(c.Site, "/"), token: token, http: client}, nil
+	case cfg.TrackGitHub:
+		owner, repo := splitRepo(c.Repo)
+		if owner == "" {
+			return nil, fmt.Errorf("tracker.repo must be like owner/repo, got %q", c.Repo)
+		}
+		gh := github.NewClient(token)
+		gh.HTTP = client
+		if c.Site != "" {
+			gh.BaseURL = c.Site
+		}
+		return &issues{client: gh, owner: owner, repo: repo}, nil
+	}
+	return nil, fmt.Errorf("unsupported tracker %q; use %s or %s", c.Kind, cfg.TrackJira, cfg.TrackGitHub)
+}
+
+func splitRepo(repo string) (string, string) {
+	fields := strings.Split(repo, "/")
+	if len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+		return "", ""
+	}
+	return fields[0], fields[1]
+}
+
+// jiraKey matches a Jira issue key. e.g. ONCALL-843
+var jiraKey = regexp.MustCompile(`^[A-Z][A-Z0-9]+-[0-9]+$`)
+
+// jira looks tickets up with Jira's REST API, with an email:token pair.
+type jira struct {
+	site  string
+	token string
+	http  *httpx.Client
+}
+
+func (j *jira) IsTicket(id string) bool {
+	return jiraKey.MatchString(id)
+}
+
+func (j *jira) Title(id string) (string, error) {
+	req, err := http.NewRequest("GET", j.site+"/rest/api/2/issue/"+url.PathEscape(id)+"?fields=summary", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if j.token != "" {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(j.token)))
+	}
+	resp, err := j.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("GET %s: %s", id, resp.Status)
+	}
+	var issue struct {
+		Fields struct {
+			Summary string `json:"summary"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(buf, &issue); err != nil {
+		return "", err
+	}
+	return issue.Fields.Summary, nil
+}
+
+// issueNumber matches a GitHub issue number. e.g. #843 or 843
+var issueNumber = regexp.MustCompile(`^#?([0-9]+)$`)
+
+// issues looks tickets up in a GitHub repository's issues.
+type issues struct {
+	client      *github.Client
+	owner, repo string
+}
+
+func (i *issues) IsTicket(id string) bool {
+	return issueNumber.MatchString(id)
+}
+
+func (i *issues) Title(id string) (string, error) {
+	m := issueNumber.FindStringSubmatch(id)
+	if m == nil {
+		return "", fmt.Errorf("%s isn't an issue number", id)
+	}
+	number, _ := strconv.Atoi(m[1])
+	issue, err := i.client.Issue(i.owner, i.repo, number)
+	if err != nil {
+		return "", err
+	}
+	return issue.Title, nil
+}
+
+// MaxSlug is the longest slug Slug makes, so branch names stay readable.
+const MaxSlug = 40
+
+// Slug turns a ticket's title into something fit for a branch name: lower
+// case words joined by hyphens, cut at a word to at most MaxSlug bytes. e.g.
+// "Fix login timeout (again!)" becomes "fix-login-timeout-again".
+func Slug(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	slug := ""
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if len(next) > MaxSlug {
+			if slug == "" {
+				slug = word[:MaxSlug]
+			}
+			break
+		}
+		slug = next
+	}
+	return slug
+}
+
+// Branch returns the name to give a branch for ticket id with title: the
+// id, and the title's slug if it has one, without any leading #. e.g.
+// ONCALL-843-fix-login-timeout
+func Branch(id, title string) string {
+	id = strings.TrimPrefix(id, "#")
+	if slug := Slug(title); slug != "" {
+		return id + "-" + slug
+	}
+	return id
+}
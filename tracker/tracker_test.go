@@ -0,0 +1,77 @@
+package tracker
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+func TestJira(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/ONCALL-843" || r.URL.Query().Get("fields") != "summary" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("lb@example.com:secret")) {
+			t.Fatalf("expected basic auth, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"key": "ONCALL-843", "fields": {"summary": "Fix login timeout"}}`))
+	}))
+	defer server.Close()
+
+	tr, err := New(&cfg.Tracker{Kind: cfg.TrackJira, Site: server.URL + "/"}, "lb@example.com:secret")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !tr.IsTicket("ONCALL-843") || tr.IsTicket("843") || tr.IsTicket("fix-login") {
+		t.Fatalf("expected only Jira keys to be tickets")
+	}
+	if title, err := tr.Title("ONCALL-843"); err != nil || title != "Fix login timeout" {
+		t.Fatalf("expected the issue's summary, got %q, %v", title, err)
+	}
+	if _, err := tr.Title("ONCALL-1"); err == nil {
+		t.Fatalf("expected an error for a missing issue")
+	}
+}
+
+func TestGitHub(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/bluth/banana-stand/issues/843" {
+			t.Fatalf("unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"number": 843, "title": "Fix login timeout"}`))
+	}))
+	defer server.Close()
+
+	tr, err := New(&cfg.Tracker{Kind: cfg.TrackGitHub, Site: server.URL, Repo: "bluth/banana-stand"}, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !tr.IsTicket("#843") || !tr.IsTicket("843") || tr.IsTicket("ONCALL-843") {
+		t.Fatalf("expected only issue numbers to be tickets")
+	}
+	if title, err := tr.Title("#843"); err != nil || title != "Fix login timeout" {
+		t.Fatalf("expected the issue's title, got %q, %v", title, err)
+	}
+	if _, err := New(&cfg.Tracker{Kind: cfg.TrackGitHub, Repo: "banana-stand"}, ""); err == nil {
+		t.Fatalf("expected an error for a repo without an owner")
+	}
+}
+
+func TestBranch(t *testing.T) {
+	for _, test := range []struct {
+		id, title, expected string
+	}{
+		{"ONCALL-843", "Fix login timeout", "ONCALL-843-fix-login-timeout"},
+		{"#843", "Fix login timeout (again!)", "843-fix-login-timeout-again"},
+		{"ONCALL-843", "", "ONCALL-843"},
+		{"ONCALL-843", "The stair car's brakes fail when reversing down the driveway at night", "ONCALL-843-the-stair-car-s-brakes-fail-when"},
+	} {
+		if branch := Branch(test.id, test.title); branch != test.expected {
+			t.Errorf("expected %q for %q, got %q", test.expected, test.title, branch)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package trailer formats the git trailers used to credit co-authors, and
+// manages the commit message template that carries them.
+package trailer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+)
+
+// CoAuthoredBy is the trailer key recognized by GitHub and GitLab.
+const CoAuthoredBy = "Co-authored-by"
+
+// CoAuthor formats a single co-author trailer for a.
+// For example, "Co-authored-by: Lindsay Bluth <lb@example.com>".
+func CoAuthor(a *cfg.Author) string {
+	return fmt.Sprintf("%s: %s <%s>", CoAuthoredBy, a.Name, a.Email)
+}
+
+// Trailers returns a co-author trailer for each co-author in s.
+func Trailers(s *session.Session) []string {
+	var trailers []string
+	for _, a := range s.CoAuthors() {
+		trailers = append(trailers, CoAuthor(a))
+	}
+	return trailers
+}
+
+// Template returns the contents of a commit message template crediting the
+// co-authors of s. The leading blank lines leave room for the message itself.
+func Template(s *session.Session) string {
+	var b strings.Builder
+	b.WriteString("\n\n")
+	for _, t := range Trailers(s) {
+		b.WriteString(t)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// TemplatePath returns the location of the managed commit template.
+func TemplatePath() string {
+	return filepath.Join(cfg.DataDir(), "commit-template")
+}
+
+// WriteTemplate writes the commit template for s to path.
+func WriteTemplate(path string, s *session.Session) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(Template(s)), 0644)
+}
+
+// UpdateTemplate rewrites the managed commit template for s, but only if it
+// has previously been installed.
+func UpdateTemplate(s *session.Session) error {
+	path := TemplatePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	return WriteTemplate(path, s)
+}
+
+// Parse returns the co-author trailers found in a commit message, normalized
+// to the canonical CoAuthoredBy key.
+func Parse(message string) []string {
+	var trailers []string
+	prefix := strings.ToLower(CoAuthoredBy) + ":"
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToLower(line), prefix) {
+			value := strings.TrimSpace(line[len(prefix):])
+			trailers = append(trailers, CoAuthoredBy+": "+value)
+		}
+	}
+	return trailers
+}
+
+// Missing returns the co-authors in s who aren't credited by a trailer in
+// message, going by their email addresses and ignoring case. Comment lines,
+// which git strips, don't count.
+func Missing(message string, s *session.Session) []*cfg.Author {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	credited := strings.ToLower(strings.Join(Parse(strings.Join(lines, "\n")), "\n"))
+	var missing []*cfg.Author
+	for _, a := range s.CoAuthors() {
+		if !strings.Contains(credited, "<"+strings.ToLower(a.Email)+">") {
+			missing = append(missing, a)
+		}
+	}
+	return missing
+}
+
+// SquashMessage builds the body of a squash commit from the messages of the
+// commits being squashed: a bulleted list of their subjects, followed by each
+// distinct co-author trailer they carried. Trailers are considered the same
+// when their email addresses match, ignoring case.
+func SquashMessage(messages []string) string {
+	var b strings.Builder
+	var trailers []string
+	seen := make(map[string]bool)
+
+	for _, message := range messages {
+		subject := strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+		if subject != "" {
+			fmt.Fprintf(&b, "* %s\n", subject)
+		}
+		for _, t := range Parse(message) {
+			key := strings.ToLower(t)
+			if i := strings.LastIndex(key, "<"); i >= 0 {
+				key = key[i:]
+			}
+			if !seen[key] {
+				seen[key] = true
+				trailers = append(trailers, t)
+			}
+		}
+	}
+
+	if len(trailers) > 0 {
+		b.WriteString("\n")
+		b.WriteString(strings.Join(trailers, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Append adds each of trailers to message unless it's already there. Trailers
+// go after the message body but before any comment lines git adds, separated
+// by a blank line unless the message already ends in trailers.
+func Append(message string, trailers []string) string {
+	lines := strings.Split(message, "\n")
+	cut := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			cut = i
+			break
+		}
+	}
+	body := strings.TrimRight(strings.Join(lines[:cut], "\n"), "\n")
+	comments := strings.Join(lines[cut:], "\n")
+
+	existing := make(map[string]bool)
+	for _, t := range Parse(body) {
+		existing[strings.ToLower(t)] = true
+	}
+	var missing []string
+	for _, t := range trailers {
+		if !existing[strings.ToLower(t)] {
+			missing = append(missing, t)
+		}
+	}
+	if len(missing) == 0 {
+		return message
+	}
+
+	paragraphs := strings.Split(body, "\n\n")
+	if last := paragraphs[len(paragraphs)-1]; len(Parse(last)) == 0 || strings.TrimSpace(body) == "" {
+		body += "\n"
+	}
+	body += "\n" + strings.Join(missing, "\n") + "\n"
+	if comments != "" {
+		body += comments
+	}
+	return body
+}
@@ -0,0 +1,99 @@
+package trailer
+
+import (
+	"fmt"
+
+	"github.com/keeferrourke/pair/cfg"
+	"github.com/keeferrourke/pair/session"
+)
+
+func ExampleCoAuthor() {
+	fmt.Println(CoAuthor(&cfg.Author{Name: "Lindsay Bluth", Email: "lb@example.com"}))
+
+	// Output:
+	// Co-authored-by: Lindsay Bluth <lb@example.com>
+}
+
+func ExampleTemplate() {
+	s := &session.Session{
+		Email: "git+lb+mb@example.com",
+		Authors: []*cfg.Author{
+			{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"},
+			{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		},
+	}
+	fmt.Print(Template(s))
+
+	// Output:
+	//
+	//
+	// Co-authored-by: Lindsay Bluth <lb@example.com>
+	// Co-authored-by: Michael Bluth <mb@example.com>
+}
+
+func ExampleMissing() {
+	s := &session.Session{
+		Email: "mb@example.com",
+		Authors: []*cfg.Author{
+			{Name: "Gob Bluth", Alias: "gob", Email: "gob@example.com"},
+			{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"},
+			{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		},
+	}
+	message := `Fix the banana stand
+
+Co-authored-by: Lindsay <LB@example.com>
+# Co-authored-by: Gob Bluth <gob@example.com>`
+	for _, a := range Missing(message, s) {
+		fmt.Println(a.Alias)
+	}
+
+	// Output:
+	// gob
+}
+
+func ExampleParse() {
+	message := `Fix the banana stand
+
+co-authored-by: Lindsay Bluth <lb@example.com>
+Signed-off-by: Michael Bluth <mb@example.com>`
+	fmt.Println(Parse(message))
+
+	// Output:
+	// [Co-authored-by: Lindsay Bluth <lb@example.com>]
+}
+
+func ExampleSquashMessage() {
+	fmt.Print(SquashMessage([]string{
+		"Add the stair car\n\nCo-authored-by: Lindsay Bluth <lb@example.com>",
+		"Fix the stair car\n\nCo-authored-by: Lindsay Bluth <LB@example.com>\nCo-authored-by: George Bluth <gb@example.com>",
+	}))
+
+	// Output:
+	// * Add the stair car
+	// * Fix the stair car
+	//
+	// Co-authored-by: Lindsay Bluth <lb@example.com>
+	// Co-authored-by: George Bluth <gb@example.com>
+}
+
+func ExampleAppend() {
+	fmt.Println(Append("Fix the stair car\n# Please enter the commit message\n", []string{
+		"Co-authored-by: Lindsay Bluth <lb@example.com>",
+	}))
+	fmt.Println(Append("Fix the stair car\n\nCo-authored-by: Lindsay Bluth <lb@example.com>", []string{
+		"Co-authored-by: Lindsay Bluth <lb@example.com>",
+		"Co-authored-by: George Bluth <gb@example.com>",
+	}))
+
+	// Output:
+	// Fix the stair car
+	//
+	// Co-authored-by: Lindsay Bluth <lb@example.com>
+	// # Please enter the commit message
+	//
+	// Fix the stair car
+	//
+	// Co-authored-by: Lindsay Bluth <lb@example.com>
+	// Co-authored-by: George Bluth <gb@example.com>
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// SGR codes for Paint. They're all one or two digits, and Plain pads to the
+// same width as the one digit codes so painted table cells still line up.
+const (
+	Plain  = "0"
+	Bold   = "1"
+	Faint  = "2"
+	Red    = "31"
+	Green  = "32"
+	Yellow = "33"
+	Cyan   = "36"
+)
+
+// ColorEnabled reports whether output written to w should be colored: w is a
+// terminal, $NO_COLOR is unset (see https://no-color.org) and $TERM isn't
+// dumb. Output piped to another program or a file is left plain.
+func ColorEnabled(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(w)
+}
+
+// IsTerminal reports whether w is a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Paint wraps text in the SGR escape sequence for code, followed by a reset.
+func Paint(code, text string) string {
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
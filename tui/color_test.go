@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	if ColorEnabled(&bytes.Buffer{}) {
+		t.Fatalf("expected no color for a buffer")
+	}
+
+	f, err := ioutil.TempFile("", "pair-color")
+	if err != nil {
+		t.Fatalf("unable to create temporary file: %v", err)
+	}
+	defer os.Remove(f.Name()) // clean up
+	defer f.Close()
+	if ColorEnabled(f) {
+		t.Fatalf("expected no color for a regular file")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	if s := Paint(Yellow, "warning:"); s != "\x1b[33mwarning:\x1b[0m" {
+		t.Fatalf("expected a yellow warning, got %q", s)
+	}
+	if len(Paint(Plain, "lb")) != len(Paint(Bold, "lb")) {
+		t.Fatalf("expected plain and bold cells to be the same width")
+	}
+}
@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+// ErrCancelled is returned when someone backs out of picking.
+var ErrCancelled = errors.New("cancelled")
+
+// Picker is a fuzzy-searchable multi-select over the people you can pair
+// with. The order they're picked in is kept, so whoever is picked first
+// drives.
+type Picker struct {
+	Authors []*cfg.Author    // Everyone who can be picked, you first if you're included
+	Query   string           // What's been typed to narrow the list
+	Cursor  int              // Index of the selected row in Matches
+	Picked  []*cfg.Author    // Who's been picked, in order
+	Now     func() time.Time // Clock used to tell who's away; defaults to time.Now
+	you     *cfg.Author
+}
+
+// NewPicker creates a picker over you, if you're set, and then roster.
+func NewPicker(you *cfg.Author, roster []*cfg.Author) *Picker {
+	p := &Picker{you: you}
+	if you != nil {
+		p.Authors = append(p.Authors, you)
+	}
+	p.Authors = append(p.Authors, roster...)
+	return p
+}
+
+func (p *Picker) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+// Matches returns the authors matching the query, best first: those whose
+// alias starts with it, then those with it anywhere in their alias, name or
+// display name, then those with its letters in order.
+func (p *Picker) Matches() []*cfg.Author {
+	type match struct {
+		a    *cfg.Author
+		rank int
+	}
+	var matches []match
+	for _, a := range p.Authors {
+		if rank, ok := fuzzyRank(p.Query, a); ok {
+			matches = append(matches, match{a, rank})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].rank < matches[j].rank })
+	authors := make([]*cfg.Author, len(matches))
+	for i, m := range matches {
+		authors[i] = m.a
+	}
+	return authors
+}
+
+// fuzzyRank ranks how well query matches a, lower being better, and reports
+// whether it matches at all.
+func fuzzyRank(query string, a *cfg.Author) (int, bool) {
+	query = strings.ToLower(query)
+	if strings.HasPrefix(strings.ToLower(a.Alias), query) {
+		return 0, true
+	}
+	fields := []string{a.Alias, a.Name, a.DisplayName}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return 1, true
+		}
+	}
+	for _, field := range fields {
+		if subsequence(query, strings.ToLower(field)) {
+			return 2, true
+		}
+	}
+	return 0, false
+}
+
+// subsequence reports whether the letters of query appear in text in order.
+func subsequence(query, text string) bool {
+	for _, r := range query {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
+
+// Move moves the cursor by delta rows, staying within the matches.
+func (p *Picker) Move(delta int) {
+	p.Cursor += delta
+	if n := len(p.Matches()); p.Cursor >= n {
+		p.Cursor = n - 1
+	}
+	if p.Cursor < 0 {
+		p.Cursor = 0
+	}
+}
+
+// Type changes the query, keeping the cursor on a match.
+func (p *Picker) Type(query string) {
+	p.Query = query
+	p.Move(0)
+}
+
+// Toggle picks the selected author, or unpicks them if they're picked
+// already.
+func (p *Picker) Toggle() {
+	matches := p.Matches()
+	if len(matches) == 0 {
+		return
+	}
+	a := matches[p.Cursor]
+	for i, picked := range p.Picked {
+		if picked == a {
+			p.Picked = append(p.Picked[:i], p.Picked[i+1:]...)
+			return
+		}
+	}
+	p.Picked = append(p.Picked, a)
+}
+
+// Aliases returns the aliases of the picked authors, in the order picked.
+func (p *Picker) Aliases() []string {
+	aliases := make([]string, len(p.Picked))
+	for i, a := range p.Picked {
+		aliases[i] = a.Alias
+	}
+	return aliases
+}
+
+// Render draws the query and the matches, numbering picked authors in the
+// order they were picked.
+func (p *Picker) Render() string {
+	var b strings.Builder
+	b.WriteString("pair with — type to search, ↑/↓ move, Tab pick, Enter pair, Esc cancel\n\n")
+	fmt.Fprintf(&b, "> %s\n\n", p.Query)
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for i, a := range p.Matches() {
+		marker := " "
+		if i == p.Cursor {
+			marker = ">"
+		}
+		order := " "
+		for n, picked := range p.Picked {
+			if picked == a {
+				order = strconv.Itoa(n + 1)
+			}
+		}
+		note := ""
+		if a == p.you {
+			note = "(you)"
+		}
+		if a.AwayOn(p.now()) {
+			fmt.Fprintf(w, "%s %s %s%s\t%s\t(away)%s\n", marker, order, dim, a.Alias, a.Label(), reset)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s %s\t%s\t%s\n", marker, order, a.Alias, a.Label(), note)
+	}
+	w.Flush()
+	if len(p.Picked) > 0 {
+		fmt.Fprintf(&b, "\n%s drives.\n", p.Picked[0].Label())
+	}
+	return b.String()
+}
+
+// Run drives the picker on t until the user pairs, returning the aliases
+// picked in order, or ErrCancelled if they back out. Enter with nobody
+// picked pairs with the selected author.
+func (p *Picker) Run(t *Terminal) ([]string, error) {
+	for {
+		t.Draw(p.Render())
+		k, err := t.ReadKey()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case k.Code == KeyUp:
+			p.Move(-1)
+		case k.Code == KeyDown:
+			p.Move(1)
+		case k.Code == KeyRune && k.Rune == '\t':
+			p.Toggle()
+			p.Type("")
+		case k.Code == KeyRune:
+			p.Type(p.Query + string(k.Rune))
+		case k.Code == KeyBackspace:
+			if q := []rune(p.Query); len(q) > 0 {
+				p.Type(string(q[:len(q)-1]))
+			}
+		case k.Code == KeyEnter:
+			if len(p.Picked) == 0 {
+				p.Toggle()
+			}
+			if len(p.Picked) > 0 {
+				return p.Aliases(), nil
+			}
+		case k.Code == KeyEscape || k.Code == KeyInterrupt:
+			return nil, ErrCancelled
+		}
+	}
+}
+
+// PickPlain asks who to pair with on w and reads the answer from r, for when
+// there's no terminal for a Picker. The answer is numbers from the list or
+// aliases, separated by spaces or commas, in driving order.
+func PickPlain(r io.Reader, w io.Writer, you *cfg.Author, roster []*cfg.Author) ([]string, error) {
+	p := NewPicker(you, roster)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for i, a := range p.Authors {
+		fmt.Fprintf(tw, "%d)\t%s\t%s\n", i+1, a.Alias, a.Label())
+	}
+	tw.Flush()
+	fmt.Fprint(w, "Pair with (numbers or aliases, driver first): ")
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return nil, ErrCancelled
+	}
+	var aliases []string
+	for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r' }) {
+		if n, err := strconv.Atoi(field); err == nil {
+			if n < 1 || n > len(p.Authors) {
+				return nil, fmt.Errorf("%d isn't in the list", n)
+			}
+			field = p.Authors[n-1].Alias
+		}
+		aliases = append(aliases, field)
+	}
+	if len(aliases) == 0 {
+		return nil, ErrCancelled
+	}
+	return aliases, nil
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+func newTestPicker() *Picker {
+	config, _ := newTestEditor()
+	config.Teammates = append(config.Teammates, &cfg.Author{Name: "Buster Bluth", Alias: "bb"})
+	return NewPicker(config.Author, config.Teammates)
+}
+
+func aliases(authors []*cfg.Author) []string {
+	var aliases []string
+	for _, a := range authors {
+		aliases = append(aliases, a.Alias)
+	}
+	return aliases
+}
+
+func TestPickerMatches(t *testing.T) {
+	p := newTestPicker()
+	for query, expected := range map[string][]string{
+		"":      {"mb", "lb", "gb", "bb"},
+		"b":     {"bb", "mb", "lb", "gb"},
+		"lind":  {"lb"},
+		"BUST":  {"bb"},
+		"gbl":   {"gb"},
+		"zzz":   nil,
+		"y blu": {"lb"},
+	} {
+		p.Type(query)
+		if got := aliases(p.Matches()); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %q to match %v, got %v", query, expected, got)
+		}
+	}
+}
+
+func TestPickerToggle(t *testing.T) {
+	p := newTestPicker()
+	p.Type("lind")
+	p.Toggle()
+	p.Type("")
+	p.Toggle()
+	p.Move(2)
+	p.Toggle()
+	if got := p.Aliases(); !reflect.DeepEqual(got, []string{"lb", "mb", "gb"}) {
+		t.Fatalf("expected aliases in the order picked, got %v", got)
+	}
+	p.Move(-2)
+	p.Toggle()
+	if got := p.Aliases(); !reflect.DeepEqual(got, []string{"lb", "gb"}) {
+		t.Fatalf("expected toggling again to unpick, got %v", got)
+	}
+	if render := p.Render(); !strings.Contains(render, "Lindsay Bluth drives.") {
+		t.Fatalf("expected the first picked to drive, got\n%s", render)
+	}
+}
+
+func TestPickPlain(t *testing.T) {
+	p := newTestPicker()
+	var w bytes.Buffer
+	got, err := PickPlain(strings.NewReader("3, lb\n"), &w, p.Authors[0], p.Authors[1:])
+	if err != nil || !reflect.DeepEqual(got, []string{"gb", "lb"}) {
+		t.Fatalf("expected numbers and aliases in order, got %v, %v", got, err)
+	}
+	if !strings.Contains(w.String(), "3)  gb") {
+		t.Fatalf("expected a numbered list, got\n%s", w.String())
+	}
+	if _, err := PickPlain(strings.NewReader("9\n"), &w, p.Authors[0], p.Authors[1:]); err == nil {
+		t.Fatalf("expected an error for a number not in the list")
+	}
+	if _, err := PickPlain(strings.NewReader(""), &w, p.Authors[0], p.Authors[1:]); err != ErrCancelled {
+		t.Fatalf("expected no answer to cancel, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+// RosterEditor is an editable table of teammates.
+type RosterEditor struct {
+	Rows    []*cfg.Author    // Teammates being edited
+	Cursor  int              // Index of the selected row
+	Message string           // Status or validation message
+	Dirty   bool             // Whether rows changed since the last save
+	Now     func() time.Time // Clock used to tell who's away; defaults to time.Now
+	you     *cfg.Author
+}
+
+// Escape sequences for greying out unavailable teammates.
+const (
+	dim   = "\x1b[2m"
+	reset = "\x1b[0m"
+)
+
+func (e *RosterEditor) now() time.Time {
+	if e.Now == nil {
+		return time.Now()
+	}
+	return e.Now()
+}
+
+// NewRosterEditor creates an editor over a copy of config's teammates.
+func NewRosterEditor(config *cfg.Config) *RosterEditor {
+	e := &RosterEditor{you: config.Author}
+	for _, a := range config.Teammates {
+		row := *a
+		e.Rows = append(e.Rows, &row)
+	}
+	return e
+}
+
+// Move moves the cursor by delta rows, staying within the table.
+func (e *RosterEditor) Move(delta int) {
+	e.Cursor += delta
+	if e.Cursor >= len(e.Rows) {
+		e.Cursor = len(e.Rows) - 1
+	}
+	if e.Cursor < 0 {
+		e.Cursor = 0
+	}
+}
+
+// Delete removes the selected row.
+func (e *RosterEditor) Delete() {
+	if len(e.Rows) == 0 {
+		return
+	}
+	e.Rows = append(e.Rows[:e.Cursor], e.Rows[e.Cursor+1:]...)
+	e.Dirty = true
+	e.Move(0)
+}
+
+// Validate checks row against the rest of the table: alias and name are
+// required, emails must look like emails, and aliases and emails must be
+// unique.
+func (e *RosterEditor) Validate(row *cfg.Author) error {
+	if row.Alias == "" || row.Name == "" {
+		return errors.New("alias and name are required")
+	}
+	if strings.ContainsAny(row.Alias, " \t+") {
+		return errors.New("alias can't contain spaces or +")
+	}
+	if row.Email != "" && !strings.Contains(row.Email, "@") {
+		return fmt.Errorf("%s is not an email address", row.Email)
+	}
+	others := e.Rows
+	if e.you != nil {
+		others = append([]*cfg.Author{e.you}, others...)
+	}
+	for _, other := range others {
+		if other == row {
+			continue
+		}
+		if other.Alias == row.Alias {
+			return fmt.Errorf("alias %s is already taken by %s", row.Alias, other.Name)
+		}
+		if row.Email != "" && strings.EqualFold(other.Email, row.Email) {
+			return fmt.Errorf("email %s already belongs to %s", row.Email, other.Name)
+		}
+	}
+	return nil
+}
+
+// Apply writes the edited rows back into config's teammates.
+func (e *RosterEditor) Apply(config *cfg.Config) {
+	config.Teammates = e.Rows
+	e.Dirty = false
+}
+
+// Render draws the table, highlighting the selected row.
+func (e *RosterEditor) Render() string {
+	var b strings.Builder
+	b.WriteString("pair team edit — ↑/↓ move, a add, e edit, d delete, s save, q quit\n\n")
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  ALIAS\tNAME\tEMAIL")
+	for i, row := range e.Rows {
+		marker := " "
+		if i == e.Cursor {
+			marker = ">"
+		}
+		if row.AwayOn(e.now()) {
+			fmt.Fprintf(w, "%s %s%s\t%s\t%s\t(away)%s\n", marker, dim, row.Alias, row.Name, row.Email, reset)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\n", marker, row.Alias, row.Name, row.Email)
+	}
+	w.Flush()
+	if len(e.Rows) == 0 {
+		b.WriteString("  (no teammates; press a to add one)\n")
+	}
+	if e.Message != "" {
+		b.WriteString("\n" + e.Message + "\n")
+	}
+	return b.String()
+}
+
+// Run drives the editor on t until the user quits, calling save whenever they
+// ask to save.
+func (e *RosterEditor) Run(t *Terminal, save func() error) error {
+	for {
+		t.Draw(e.Render())
+		k, err := t.ReadKey()
+		if err != nil {
+			return err
+		}
+		e.Message = ""
+		switch {
+		case k.Code == KeyUp || k.Rune == 'k':
+			e.Move(-1)
+		case k.Code == KeyDown || k.Rune == 'j':
+			e.Move(1)
+		case k.Rune == 'a':
+			row := &cfg.Author{}
+			e.Rows = append(e.Rows, row)
+			e.Cursor = len(e.Rows) - 1
+			if !e.edit(t, row) {
+				e.Rows = e.Rows[:len(e.Rows)-1]
+				e.Move(0)
+			}
+		case k.Code == KeyEnter || k.Rune == 'e':
+			if len(e.Rows) > 0 {
+				e.edit(t, e.Rows[e.Cursor])
+			}
+		case k.Rune == 'd':
+			e.Delete()
+		case k.Rune == 's':
+			if err := save(); err != nil {
+				e.Message = "error: " + err.Error()
+			} else {
+				e.Message = "Saved."
+			}
+		case k.Rune == 'q' || k.Code == KeyEscape || k.Code == KeyInterrupt:
+			if e.Dirty && k.Rune == 'q' {
+				e.Message = "Unsaved changes: press s to save, or Esc to discard and quit."
+				continue
+			}
+			return nil
+		}
+	}
+}
+
+// edit prompts for each field of row until it validates, reporting whether
+// the edit was accepted.
+func (e *RosterEditor) edit(t *Terminal, row *cfg.Author) bool {
+	edited := *row
+	for {
+		t.Draw(e.Render())
+		fields := []struct {
+			label string
+			value *string
+		}{{"Alias", &edited.Alias}, {"Name", &edited.Name}, {"Email", &edited.Email}}
+		for _, f := range fields {
+			value, ok, err := t.Prompt(f.label, *f.value)
+			if err != nil || !ok {
+				e.Message = "Edit cancelled."
+				return false
+			}
+			*f.value = value
+		}
+		original := *row
+		*row = edited
+		if err := e.Validate(row); err != nil {
+			*row = original
+			e.Message = "error: " + err.Error()
+			continue
+		}
+		e.Dirty = true
+		return true
+	}
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keeferrourke/pair/cfg"
+)
+
+func newTestEditor() (*cfg.Config, *RosterEditor) {
+	config := &cfg.Config{
+		Author: &cfg.Author{Name: "Michael Bluth", Alias: "mb", Email: "mb@example.com"},
+		Teammates: []*cfg.Author{
+			&cfg.Author{Name: "Lindsay Bluth", Alias: "lb", Email: "lb@example.com"},
+			&cfg.Author{Name: "George Bluth", Alias: "gb"},
+		},
+	}
+	return config, NewRosterEditor(config)
+}
+
+func TestRosterEditorValidate(t *testing.T) {
+	_, e := newTestEditor()
+	if err := e.Validate(e.Rows[0]); err != nil {
+		t.Fatalf("expected an existing row to be valid, got %v", err)
+	}
+	invalid := []*cfg.Author{
+		{Name: "Buster Bluth"},
+		{Alias: "bb"},
+		{Alias: "b b", Name: "Buster Bluth"},
+		{Alias: "bb", Name: "Buster Bluth", Email: "buster"},
+		{Alias: "mb", Name: "Maeby Fünke"},
+		{Alias: "bb", Name: "Buster Bluth", Email: "LB@example.com"},
+	}
+	for _, row := range invalid {
+		if err := e.Validate(row); err == nil {
+			t.Fatalf("expected %+v to be invalid", row)
+		}
+	}
+}
+
+func TestRosterEditorDeleteAndApply(t *testing.T) {
+	config, e := newTestEditor()
+	e.Move(5)
+	if e.Cursor != 1 {
+		t.Fatalf("expected cursor to stop at the last row, got %d", e.Cursor)
+	}
+	e.Delete()
+	if len(e.Rows) != 1 || e.Cursor != 0 || !e.Dirty {
+		t.Fatalf("expected the last row to be deleted, got %v at %d", e.Rows, e.Cursor)
+	}
+	if len(config.Teammates) != 2 {
+		t.Fatalf("expected config to be untouched until applied")
+	}
+	e.Apply(config)
+	if len(config.Teammates) != 1 || config.Teammates[0].Alias != "lb" || e.Dirty {
+		t.Fatalf("expected applied config to match the editor, got %v", config.Teammates)
+	}
+}
+
+func TestRosterEditorRender(t *testing.T) {
+	_, e := newTestEditor()
+	out := e.Render()
+	if !strings.Contains(out, "> lb") || !strings.Contains(out, "  gb") {
+		t.Fatalf("expected the selected row to be marked, got %s", out)
+	}
+
+	e.Rows[1].Status, e.Rows[1].Until = cfg.Away, "2026-11-01"
+	e.Now = func() time.Time { return time.Date(2026, 10, 31, 12, 0, 0, 0, time.UTC) }
+	if out := e.Render(); !strings.Contains(out, dim+"gb") || !strings.Contains(out, "(away)") {
+		t.Fatalf("expected away teammates to be greyed out, got %q", out)
+	}
+}
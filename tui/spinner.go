@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// spinnerFrames are drawn in turn in front of the spinner's message.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Timing of the spinner. Nothing is drawn for operations quicker than
+// spinnerDelay, so fast responses don't flicker.
+var (
+	spinnerDelay    = 250 * time.Millisecond
+	spinnerInterval = 100 * time.Millisecond
+)
+
+// Spinner shows that a slow operation, such as a network request, is still
+// under way. A nil *Spinner is valid and does nothing.
+type Spinner struct {
+	w       io.Writer
+	message string
+	stop    chan struct{}
+	done    sync.WaitGroup
+	drawn   bool
+}
+
+// StartSpinner draws a spinner and message on w, which should be a terminal,
+// until Stop is called.
+func StartSpinner(w io.Writer, message string) *Spinner {
+	s := &Spinner{w: w, message: message, stop: make(chan struct{})}
+	s.done.Add(1)
+	go s.run()
+	return s
+}
+
+func (s *Spinner) run() {
+	defer s.done.Done()
+	select {
+	case <-s.stop:
+		return
+	case <-time.After(spinnerDelay):
+	}
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		fmt.Fprintf(s.w, "\r%s %s", spinnerFrames[frame%len(spinnerFrames)], s.message)
+		s.drawn = true
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop removes the spinner from the terminal. Call it before writing anything
+// else to the spinner's writer.
+func (s *Spinner) Stop() {
+	if s == nil {
+		return
+	}
+	close(s.stop)
+	s.done.Wait()
+	if s.drawn {
+		fmt.Fprint(s.w, "\r\x1b[K")
+	}
+}
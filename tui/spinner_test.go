@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpinner(t *testing.T) {
+	defer func(delay, interval time.Duration) {
+		spinnerDelay, spinnerInterval = delay, interval
+	}(spinnerDelay, spinnerInterval)
+	spinnerDelay, spinnerInterval = time.Millisecond, time.Millisecond
+
+	var buf bytes.Buffer
+	s := StartSpinner(&buf, "Fetching the team roster")
+	time.Sleep(20 * time.Millisecond)
+	s.Stop()
+	out := buf.String()
+	if !strings.HasPrefix(out, "\r| Fetching the team roster") {
+		t.Fatalf("expected the spinner to be drawn, got %q", out)
+	}
+	if !strings.HasSuffix(out, "\r\x1b[K") {
+		t.Fatalf("expected the spinner to be cleared, got %q", out)
+	}
+}
+
+func TestSpinnerQuick(t *testing.T) {
+	var buf bytes.Buffer
+	StartSpinner(&buf, "Fetching the team roster").Stop()
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing drawn for a quick operation, got %q", buf.String())
+	}
+
+	var s *Spinner
+	s.Stop() // a nil spinner is a no-op
+}
@@ -0,0 +1,127 @@
+// Package tui implements the small full-screen terminal interfaces pair
+// offers for people who would rather not edit YAML or remember aliases.
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal is returned by Open when stdin or stdout isn't a terminal.
+var ErrNotTerminal = errors.New("not a terminal")
+
+// Key codes for keys without a printable rune.
+const (
+	KeyRune = iota
+	KeyUp
+	KeyDown
+	KeyEnter
+	KeyBackspace
+	KeyEscape
+	KeyInterrupt
+)
+
+// Key is a single key press.
+type Key struct {
+	Code int  // One of the Key* constants
+	Rune rune // The character typed, when Code is KeyRune
+}
+
+// Terminal is the controlling terminal, switched into raw mode.
+type Terminal struct {
+	in    *os.File
+	out   io.Writer
+	r     *bufio.Reader
+	state *term.State
+}
+
+// Open switches the terminal on stdin into raw mode. Callers must Close it to
+// restore the terminal.
+func Open() (*Terminal, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, ErrNotTerminal
+	}
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	return &Terminal{in: os.Stdin, out: os.Stdout, r: bufio.NewReader(os.Stdin), state: state}, nil
+}
+
+// Close clears the screen and restores the terminal to its original mode.
+func (t *Terminal) Close() error {
+	t.Clear()
+	return term.Restore(int(t.in.Fd()), t.state)
+}
+
+// Clear clears the screen and moves the cursor home.
+func (t *Terminal) Clear() {
+	fmt.Fprint(t.out, "\x1b[2J\x1b[H")
+}
+
+// Draw clears the screen and writes text, translating newlines for raw mode.
+func (t *Terminal) Draw(text string) {
+	t.Clear()
+	fmt.Fprint(t.out, strings.Replace(text, "\n", "\r\n", -1))
+}
+
+// ReadKey waits for the next key press.
+func (t *Terminal) ReadKey() (Key, error) {
+	r, _, err := t.r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	switch r {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case 127, '\b':
+		return Key{Code: KeyBackspace}, nil
+	case 3:
+		return Key{Code: KeyInterrupt}, nil
+	case 27:
+		if t.r.Buffered() >= 2 {
+			seq := make([]byte, 2)
+			io.ReadFull(t.r, seq)
+			switch string(seq) {
+			case "[A":
+				return Key{Code: KeyUp}, nil
+			case "[B":
+				return Key{Code: KeyDown}, nil
+			}
+		}
+		return Key{Code: KeyEscape}, nil
+	}
+	return Key{Code: KeyRune, Rune: r}, nil
+}
+
+// Prompt reads a line of input below the current screen, starting from
+// initial. Enter accepts the line; Escape cancels, returning initial and
+// false.
+func (t *Terminal) Prompt(label, initial string) (string, bool, error) {
+	line := []rune(initial)
+	for {
+		fmt.Fprintf(t.out, "\r\x1b[K%s: %s", label, string(line))
+		k, err := t.ReadKey()
+		if err != nil {
+			return initial, false, err
+		}
+		switch k.Code {
+		case KeyEnter:
+			return strings.TrimSpace(string(line)), true, nil
+		case KeyEscape, KeyInterrupt:
+			return initial, false, nil
+		case KeyBackspace:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+			}
+		case KeyRune:
+			line = append(line, k.Rune)
+		}
+	}
+}
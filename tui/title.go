@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TitleSequence returns the OSC escape sequence setting the terminal window
+// and tab title to title.
+func TitleSequence(title string) string {
+	title = strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, title)
+	return "\x1b]0;" + title + "\x07"
+}
+
+// PairTitle returns the title for a terminal configured for aliases, such as
+// "lb+mb", working on ticket, which may be empty.
+func PairTitle(aliases, ticket string) string {
+	if ticket == "" {
+		return "pair: " + aliases
+	}
+	return fmt.Sprintf("pair: %s · %s", aliases, ticket)
+}
+
+// SetTitle sets the title of the controlling terminal. It writes to the
+// terminal directly, so it works even when stdout is being captured, such as
+// by eval "$(pair with --export lb)".
+func SetTitle(title string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+	_, err = fmt.Fprint(tty, TitleSequence(title))
+	return err
+}
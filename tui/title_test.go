@@ -0,0 +1,12 @@
+package tui
+
+import "testing"
+
+func TestTitleSequence(t *testing.T) {
+	if seq := TitleSequence(PairTitle("lb+mb", "ONCALL-843")); seq != "\x1b]0;pair: lb+mb · ONCALL-843\x07" {
+		t.Fatalf("expected an OSC 0 title sequence, got %q", seq)
+	}
+	if seq := TitleSequence("lb\x07\x1b]0;evil"); seq != "\x1b]0;lb]0;evil\x07" {
+		t.Fatalf("expected control characters to be stripped, got %q", seq)
+	}
+}
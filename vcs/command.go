@@ -0,0 +1,41 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// How git is run, as set by Configure.
+var (
+	binary     = "git"
+	globalArgs []string
+)
+
+// Configure sets the git binary to run, for systems with several git
+// installs or a wrapper script, and arguments to pass before every git
+// command, e.g. -c protocol.file.allow=always. Empty values keep the
+// defaults. $PAIR_GIT_BIN and $PAIR_GIT_ARGS take precedence over both.
+func Configure(path string, args []string) {
+	if path != "" {
+		binary = path
+	}
+	if len(args) > 0 {
+		globalArgs = args
+	}
+}
+
+// Command returns a command running git with args, using the configured
+// binary and global arguments. $PAIR_GIT_ARGS is split on whitespace, so
+// its arguments can't contain spaces.
+func Command(args ...string) *exec.Cmd {
+	bin := binary
+	if env := os.Getenv("PAIR_GIT_BIN"); env != "" {
+		bin = env
+	}
+	global := globalArgs
+	if env := os.Getenv("PAIR_GIT_ARGS"); env != "" {
+		global = strings.Fields(env)
+	}
+	return exec.Command(bin, append(append([]string{}, global...), args...)...)
+}
@@ -0,0 +1,375 @@
+// Package vcs wraps the version control commands pair relies on.
+package vcs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keeferrourke/pair/audit"
+	"github.com/keeferrourke/pair/internal/gitconfig"
+	"github.com/keeferrourke/pair/internal/lockedfile"
+)
+
+// Git runs git with the given arguments and returns its output with any
+// trailing newline removed.
+func Git(args ...string) (string, error) {
+	cmd := Command(args...)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// InRepo reports whether the working directory is inside a git repository.
+func InRepo() bool {
+	_, err := Git("rev-parse", "--git-dir")
+	return err == nil
+}
+
+// Messages returns the full commit messages for revRange (e.g. "main..HEAD"),
+// oldest first.
+func Messages(revRange string) ([]string, error) {
+	output, err := Git("log", "--reverse", "--format=%B%x00", revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, message := range strings.Split(output, "\x00") {
+		message = strings.TrimSpace(message)
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// TopLevel returns the root of the working tree, or the empty string outside
+// a repository.
+func TopLevel() string {
+	root, err := Git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// IdentityFile returns the git config file pair writes the author to:
+// $PAIR_GIT_CONFIG if set, otherwise ~/.gitconfig_local, which is meant to be
+// included from ~/.gitconfig.
+func IdentityFile() string {
+	if path := os.Getenv("PAIR_GIT_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".gitconfig_local")
+}
+
+// GetConfig returns the value of key in the git config file at file. It
+// reads the file itself, running git only for files it can't parse.
+func GetConfig(file, key string) (string, error) {
+	value, err := gitconfig.Get(file, key)
+	if _, ok := err.(*gitconfig.SyntaxError); ok {
+		return Git("config", "--file", file, key)
+	}
+	return value, err
+}
+
+// SetConfig sets key to value in the git config file at file, recording the
+// change in the audit log. Like GetConfig, it runs git only for files it
+// can't parse.
+func SetConfig(file, key, value string) error {
+	return setConfig(file, gitconfig.Change{Key: key, Value: value})
+}
+
+// setConfig makes changes in a single write to the git config file at file,
+// then records them in the audit log. Like audit.Record, a *audit.SinkError
+// means the changes were made but couldn't be sent to a log sink.
+func setConfig(file string, changes ...gitconfig.Change) error {
+	old := make([]string, len(changes))
+	for i, c := range changes {
+		old[i], _ = GetConfig(file, c.Key)
+	}
+	err := gitconfig.Apply(file, changes...)
+	if _, ok := err.(*gitconfig.SyntaxError); ok {
+		err = gitApply(file, changes)
+	}
+	if err != nil {
+		return err
+	}
+	var sinkErr error
+	for i, c := range changes {
+		err := audit.Record("git config", file, c.Key, old[i], c.Value)
+		if _, ok := err.(*audit.SinkError); ok {
+			sinkErr = err
+		} else if err != nil {
+			return err
+		}
+	}
+	return sinkErr
+}
+
+// gitApply makes changes to the git config file at file by running git, one
+// at a time.
+func gitApply(file string, changes []gitconfig.Change) error {
+	for _, c := range changes {
+		if !c.Unset {
+			if _, err := Git("config", "--file", file, c.Key, c.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		_, err := Git("config", "--file", file, "--unset-all", c.Key)
+		if exit, ok := err.(*exec.ExitError); ok && exit.ExitCode() == 5 {
+			continue // It wasn't set
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetIdentity sets the author name and email in IdentityFile, and makes
+// them the committer too. Like audit.Record, a *audit.SinkError means the
+// identity was set but the change couldn't be sent to a log sink.
+func SetIdentity(name, email string) error {
+	return SetSplitIdentity(name, email, "", "")
+}
+
+// SetSplitIdentity sets the author name and email in IdentityFile, with
+// committerName and committerEmail as the committer, or the author too if
+// they're empty. Git reads the committer keys from version 2.22.
+func SetSplitIdentity(name, email, committerName, committerEmail string) error {
+	changes := []gitconfig.Change{{Key: "user.name", Value: name}, {Key: "user.email", Value: email}}
+	committer := []gitconfig.Change{{Key: "committer.name", Value: committerName}, {Key: "committer.email", Value: committerEmail}}
+	for _, c := range committer {
+		c.Unset = c.Value == ""
+		changes = append(changes, c)
+	}
+	return setConfig(IdentityFile(), changes...)
+}
+
+// RestoreIdentity puts the git config file at file back the way it was
+// before pair last changed it, and returns the author it now sets. The file
+// it replaces becomes the backup, so restoring again redoes the change. Like
+// SetIdentity, a *audit.SinkError means only the audit log sinks failed.
+func RestoreIdentity(file string) (string, string, error) {
+	keys := []string{"user.name", "user.email"}
+	old := make([]string, len(keys))
+	for i, key := range keys {
+		old[i], _ = GetConfig(file, key)
+	}
+	err := lockedfile.Update(file, 0644, func([]byte) ([]byte, error) {
+		backup, err := ioutil.ReadFile(lockedfile.Backup(file))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("there's nothing to undo; %s has no backup", file)
+		}
+		return backup, err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	restored := make([]string, len(keys))
+	var sinkErr error
+	for i, key := range keys {
+		restored[i], _ = GetConfig(file, key)
+		err := audit.Record("git config", file, key, old[i], restored[i])
+		if _, ok := err.(*audit.SinkError); ok {
+			sinkErr = err
+		} else if err != nil {
+			return "", "", err
+		}
+	}
+	return restored[0], restored[1], sinkErr
+}
+
+// BackupIdentity copies the git config file at file aside if the author in
+// it differs from what pair last set, according to the audit log, so
+// something else's changes aren't silently overwritten. It returns the path
+// of the backup, or "" if none was needed.
+func BackupIdentity(file string) (string, error) {
+	entries, err := audit.Read(audit.Path())
+	if err != nil {
+		return "", err
+	}
+	changed := false
+	for _, key := range []string{"user.name", "user.email"} {
+		current, err := GetConfig(file, key)
+		if err != nil {
+			continue // unset, so there's nothing to lose
+		}
+		if last, ok := audit.Last(entries, file, key); ok && last != current {
+			changed = true
+		}
+	}
+	if !changed {
+		return "", nil
+	}
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	backup := file + ".pair-backup-" + time.Now().Format("20060102T150405")
+	return backup, ioutil.WriteFile(backup, buf, 0600)
+}
+
+// CurrentBranch returns the name of the checked out branch, or the empty
+// string when HEAD is detached or there's no repository.
+func CurrentBranch() string {
+	branch, err := Git("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return branch
+}
+
+// DefaultBranch returns the branch new branches should start from: the one
+// origin/HEAD points to, locally if it's checked out there and otherwise on
+// origin, else main or master, whichever exists, else HEAD.
+func DefaultBranch() string {
+	if remote, err := Git("symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if local := strings.TrimPrefix(remote, "origin/"); branchExists(local) {
+			return local
+		}
+		return remote
+	}
+	for _, branch := range []string{"main", "master"} {
+		if branchExists(branch) {
+			return branch
+		}
+	}
+	return "HEAD"
+}
+
+// branchExists reports whether there's a local branch called branch.
+func branchExists(branch string) bool {
+	_, err := Git("rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+var ticketPattern = regexp.MustCompile(`[A-Z][A-Z0-9]+-[0-9]+`)
+
+// Ticket returns the issue tracker key in branch, such as ONCALL-843 in
+// "lb+mb/ONCALL-843", or the empty string if there isn't one.
+func Ticket(branch string) string {
+	return ticketPattern.FindString(branch)
+}
+
+// ConfigValue returns the effective value of a git config key, or the empty
+// string if it isn't set.
+func ConfigValue(key string) string {
+	value, err := Git("config", key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// ConfigSection returns the effective values of the git config keys in
+// section, such as git-together, keyed by the rest of their names. e.g.
+// authors.lb for git-together.authors.lb
+func ConfigSection(section string) (map[string]string, error) {
+	values := make(map[string]string)
+	output, err := Git("config", "--get-regexp", "^"+regexp.QuoteMeta(section)+`\.`)
+	if exit, ok := err.(*exec.ExitError); ok && exit.ExitCode() == 1 {
+		return values, nil // Nothing's set
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) == 2 {
+			values[strings.TrimPrefix(fields[0], section+".")] = fields[1]
+		}
+	}
+	return values, nil
+}
+
+// Commit is a single commit as reported by git log.
+type Commit struct {
+	Hash    string
+	Author  string    // Author name. e.g. Lindsay Bluth and Michael Bluth
+	Email   string    // Author email. e.g. git+lb+mb@example.com
+	Time    time.Time // When it was authored
+	Message string    // Full commit message, subject first
+}
+
+// Subject returns the first line of the commit message.
+func (c *Commit) Subject() string {
+	return strings.SplitN(c.Message, "\n", 2)[0]
+}
+
+// Commits returns the commits in revRange (e.g. "main..HEAD"), oldest first.
+func Commits(revRange string) ([]*Commit, error) {
+	output, err := Git("log", "--reverse", "--format="+commitFormat, revRange)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommits(output), nil
+}
+
+// RecentCommits returns up to the last n commits on the current branch,
+// newest first.
+func RecentCommits(n int) ([]*Commit, error) {
+	output, err := Git("log", "-n", strconv.Itoa(n), "--format="+commitFormat)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommits(output), nil
+}
+
+// Shortlog returns the output of `git shortlog -sne` for the repository at
+// dir: everyone who's authored a commit on its current branch, with their
+// email and how many commits they made.
+func Shortlog(dir string) (string, error) {
+	return Git("-C", dir, "shortlog", "-sne", "HEAD")
+}
+
+// commitFormat separates the fields of a commit with \x1f and commits with \x00.
+const commitFormat = "%H%x1f%an%x1f%ae%x1f%at%x1f%B%x00"
+
+// parseCommits parses the output of git log --format=commitFormat.
+func parseCommits(output string) []*Commit {
+	var commits []*Commit
+	for _, record := range strings.Split(output, "\x00") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\r\n"), "\x1f", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		seconds, _ := strconv.ParseInt(fields[3], 10, 64)
+		commits = append(commits, &Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Time:    time.Unix(seconds, 0),
+			Message: strings.TrimSpace(fields[4]),
+		})
+	}
+	return commits
+}
+
+// NotesRef is the notes ref pair records its metadata under.
+const NotesRef = "refs/notes/pair"
+
+// AddNote attaches message to commit under NotesRef, replacing any existing
+// note.
+func AddNote(commit string, message string) error {
+	_, err := Git("notes", "--ref", NotesRef, "add", "--force", "--message", message, commit)
+	return err
+}
+
+// ShowNote returns the note attached to commit under NotesRef.
+func ShowNote(commit string) (string, error) {
+	return Git("notes", "--ref", NotesRef, "show", commit)
+}
@@ -0,0 +1,144 @@
+package vcs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseCommits(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected []*Commit
+	}{
+		{"empty", "", nil},
+		{
+			"one",
+			"aaaa\x1fMichael Bluth\x1fmb@example.com\x1f1500000000\x1fFix the stair car\n\x00",
+			[]*Commit{{Hash: "aaaa", Author: "Michael Bluth", Email: "mb@example.com", Time: time.Unix(1500000000, 0), Message: "Fix the stair car"}},
+		},
+		{
+			"several, newline separated",
+			"aaaa\x1fMichael Bluth\x1fmb@example.com\x1f1500000000\x1fFix the stair car\n\nCo-authored-by: Lindsay Bluth <lb@example.com>\n\x00\n" +
+				"bbbb\x1fGeorge Bluth\x1fgb@example.com\x1f1500000060\x1fHide the money\n\x00\n",
+			[]*Commit{
+				{Hash: "aaaa", Author: "Michael Bluth", Email: "mb@example.com", Time: time.Unix(1500000000, 0), Message: "Fix the stair car\n\nCo-authored-by: Lindsay Bluth <lb@example.com>"},
+				{Hash: "bbbb", Author: "George Bluth", Email: "gb@example.com", Time: time.Unix(1500000060, 0), Message: "Hide the money"},
+			},
+		},
+		{
+			"malformed records skipped",
+			"aaaa\x1fMichael Bluth\x00bbbb\x1fGeorge Bluth\x1fgb@example.com\x1f1500000060\x1fHide the money\x00",
+			[]*Commit{{Hash: "bbbb", Author: "George Bluth", Email: "gb@example.com", Time: time.Unix(1500000060, 0), Message: "Hide the money"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits := parseCommits(tt.output)
+			if len(commits) != len(tt.expected) {
+				t.Fatalf("expected %d commits, got %d", len(tt.expected), len(commits))
+			}
+			for i, c := range commits {
+				if *c != *tt.expected[i] {
+					t.Fatalf("expected %+v, got %+v", tt.expected[i], c)
+				}
+			}
+		})
+	}
+}
+
+// tempRepo creates a git repository in a temporary HOME which includes the
+// IdentityFile, so its author is whatever pair sets. Call the returned func
+// to clean up.
+func tempRepo(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "vcs")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	env := map[string]string{
+		"HOME":                dir,
+		"XDG_DATA_HOME":       filepath.Join(dir, "data"),
+		"PAIR_GIT_CONFIG":     filepath.Join(dir, ".gitconfig_local"),
+		"PAIR_LOG":            "",
+		"GIT_CONFIG_GLOBAL":   "",
+		"GIT_CONFIG_NOSYSTEM": "1",
+	}
+	saved := map[string]string{}
+	for k, v := range env {
+		saved[k] = os.Getenv(k)
+		os.Setenv(k, v)
+	}
+	cleanup := func() {
+		for k, v := range saved {
+			os.Setenv(k, v)
+		}
+		os.RemoveAll(dir)
+	}
+	repo := filepath.Join(dir, "repo")
+	if _, err := Git("init", "-q", repo); err != nil {
+		cleanup()
+		t.Skipf("unable to create a git repository: %v", err)
+	}
+	if _, err := Git("-C", repo, "config", "include.path", IdentityFile()); err != nil {
+		cleanup()
+		t.Fatalf("unable to include the identity file: %v", err)
+	}
+	return repo, cleanup
+}
+
+func TestSetSplitIdentity(t *testing.T) {
+	repo, cleanup := tempRepo(t)
+	defer cleanup()
+
+	tests := []struct {
+		name, email, committer, committerEmail string
+		expectedCommitter                      string
+	}{
+		{"Lindsay Bluth and Michael Bluth", "git+lb+mb@example.com", "Michael Bluth", "mb@example.com", "Michael Bluth"},
+		{"Michael Bluth", "mb@example.com", "", "", ""},
+	}
+	for _, tt := range tests {
+		if err := SetSplitIdentity(tt.name, tt.email, tt.committer, tt.committerEmail); err != nil {
+			t.Fatalf("error setting the identity: %v", err)
+		}
+		if name, _ := Git("-C", repo, "config", "user.name"); name != tt.name {
+			t.Fatalf("expected author %s, got %s", tt.name, name)
+		}
+		if email, _ := Git("-C", repo, "config", "user.email"); email != tt.email {
+			t.Fatalf("expected author email %s, got %s", tt.email, email)
+		}
+		if committer, _ := Git("-C", repo, "config", "committer.name"); committer != tt.expectedCommitter {
+			t.Fatalf("expected committer %q, got %q", tt.expectedCommitter, committer)
+		}
+	}
+}
+
+func TestRestoreIdentity(t *testing.T) {
+	repo, cleanup := tempRepo(t)
+	defer cleanup()
+	file := IdentityFile()
+
+	if _, _, err := RestoreIdentity(file); err == nil {
+		t.Fatal("expected an error with nothing to restore")
+	}
+	SetIdentity("Michael Bluth", "mb@example.com")
+	SetSplitIdentity("Lindsay Bluth and Michael Bluth", "git+lb+mb@example.com", "Michael Bluth", "mb@example.com")
+
+	name, email, err := RestoreIdentity(file)
+	if err != nil {
+		t.Fatalf("error restoring the identity: %v", err)
+	}
+	if name != "Michael Bluth" || email != "mb@example.com" {
+		t.Fatalf("expected to restore Michael Bluth <mb@example.com>, got %s <%s>", name, email)
+	}
+	if committer, err := Git("-C", repo, "config", "committer.name"); err == nil {
+		t.Fatalf("expected the committer to be unset again, got %s", committer)
+	}
+
+	if name, _, err = RestoreIdentity(file); err != nil || name != "Lindsay Bluth and Michael Bluth" {
+		t.Fatalf("expected restoring again to redo the change, got %s, %v", name, err)
+	}
+}
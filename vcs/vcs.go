@@ -0,0 +1,136 @@
+package vcs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VCS is a version control system whose commit author pair manages.
+type VCS interface {
+	// Name returns the name of the VCS, as used in the vcs config setting.
+	Name() string
+	// SetAuthor makes name and email the author of new commits.
+	SetAuthor(name, email string) error
+	// SetSplitAuthor makes name and email the author of new commits, but
+	// committerName and committerEmail their committer.
+	SetSplitAuthor(name, email, committerName, committerEmail string) error
+	// GetAuthor returns the author of new commits.
+	GetAuthor() (name, email string, err error)
+	// BranchExists reports whether branch exists in the repository.
+	BranchExists(branch string) bool
+	// Checkout switches to branch, first creating it from base if it
+	// doesn't exist, tracking base as its upstream if track is set.
+	Checkout(branch, base string, track bool) error
+	// CurrentBranch returns the name of the checked out branch, or the
+	// empty string if there isn't one.
+	CurrentBranch() string
+	// DefaultBranch returns the branch new branches start from when no base
+	// is given, such as main.
+	DefaultBranch() string
+	// Branches returns the names of the local branches.
+	Branches() ([]string, error)
+	// MergedBranches returns the names of the local branches merged into
+	// base.
+	MergedBranches(base string) ([]string, error)
+	// DeleteBranch deletes the local branch, merged or not.
+	DeleteBranch(branch string) error
+}
+
+// New returns the VCS called name, as set by the vcs config setting. An
+// empty name is git.
+func New(name string) (VCS, error) {
+	switch name {
+	case "", "git":
+		return gitVCS{}, nil
+	}
+	return nil, fmt.Errorf("unsupported vcs %q", name)
+}
+
+// gitVCS is the VCS for git, writing the author to IdentityFile.
+type gitVCS struct{}
+
+func (gitVCS) Name() string {
+	return "git"
+}
+
+func (gitVCS) SetAuthor(name, email string) error {
+	return SetIdentity(name, email)
+}
+
+func (gitVCS) SetSplitAuthor(name, email, committerName, committerEmail string) error {
+	return SetSplitIdentity(name, email, committerName, committerEmail)
+}
+
+// GetAuthor returns the author in IdentityFile, or else the author git would
+// otherwise use.
+func (gitVCS) GetAuthor() (string, string, error) {
+	name, nameErr := GetConfig(IdentityFile(), "user.name")
+	email, emailErr := GetConfig(IdentityFile(), "user.email")
+	if nameErr == nil && emailErr == nil {
+		return name, email, nil
+	}
+	name, email = ConfigValue("user.name"), ConfigValue("user.email")
+	if name == "" || email == "" {
+		return "", "", fmt.Errorf("no git author is set in %s", IdentityFile())
+	}
+	return name, email, nil
+}
+
+func (gitVCS) BranchExists(branch string) bool {
+	return branchExists(branch)
+}
+
+func (g gitVCS) Checkout(branch, base string, track bool) error {
+	args := []string{"checkout", "--quiet", branch}
+	if !g.BranchExists(branch) {
+		args = []string{"checkout", "--quiet", "-b", branch}
+		if track {
+			args = append(args, "--track")
+		}
+		args = append(args, base)
+	}
+	output, err := Command(args...).CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("unable to check out %s: %s", branch, message)
+		}
+		return fmt.Errorf("unable to check out %s: %v", branch, err)
+	}
+	return nil
+}
+
+func (gitVCS) CurrentBranch() string {
+	return CurrentBranch()
+}
+
+func (gitVCS) DefaultBranch() string {
+	return DefaultBranch()
+}
+
+func (gitVCS) Branches() ([]string, error) {
+	return gitLines("for-each-ref", "--format=%(refname:short)", "refs/heads/")
+}
+
+func (gitVCS) MergedBranches(base string) ([]string, error) {
+	return gitLines("for-each-ref", "--format=%(refname:short)", "--merged", base, "refs/heads/")
+}
+
+func (gitVCS) DeleteBranch(branch string) error {
+	output, err := Command("branch", "--delete", "--force", branch).CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("unable to delete %s: %s", branch, message)
+		}
+		return fmt.Errorf("unable to delete %s: %v", branch, err)
+	}
+	return nil
+}
+
+// gitLines runs git with args and returns the lines of its output.
+func gitLines(args ...string) ([]string, error) {
+	output, err := Git(args...)
+	if err != nil || output == "" {
+		return nil, err
+	}
+	return strings.Split(output, "\n"), nil
+}